@@ -0,0 +1,31 @@
+// Command recompute-ratings rebuilds every player's and partnership's rating from the confirmed
+// game history. Stop the API while it runs: games confirmed in the meantime would be dropped from
+// the result.
+package main
+
+import (
+	"log"
+
+	"swipe-sports-backend/internal/config"
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/service"
+)
+
+func main() {
+	if err := config.Load(); err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	db, err := database.Init()
+	if err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+	defer db.Close()
+
+	games, err := service.NewRatingService().RecomputeAll()
+	if err != nil {
+		log.Fatal("Failed to recompute ratings:", err)
+	}
+
+	log.Printf("Recomputed ratings from %d confirmed games", games)
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"swipe-sports-backend/internal/config"
 )
 
 func AuthMiddleware() gin.HandlerFunc {
@@ -39,6 +40,31 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// AdminMiddleware only lets through users whose email is listed in ADMIN_EMAILS.
+// It must run after AuthMiddleware.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		email, exists := GetEmailFromContext(c)
+		if !exists || !IsAdminEmail(email) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// IsAdminEmail reports whether the email belongs to a configured admin
+func IsAdminEmail(email string) bool {
+	for _, adminEmail := range config.AppConfig.Admin.Emails {
+		if email != "" && strings.EqualFold(email, adminEmail) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetUserIDFromContext extracts user ID from gin context
 func GetUserIDFromContext(c *gin.Context) (int64, bool) {
 	userID, exists := c.Get("user_id")
@@ -0,0 +1,561 @@
+// Package bracket builds tournament brackets and advances them as results come in.
+//
+// A bracket is a flat list of matches. Each slot of a match names where its player comes from:
+// a seed, or the winner or loser of an earlier match. Whenever a result is recorded, every
+// match whose slots are all decided is filled in. A slot with nobody to fill it is a bye, and
+// the other player goes through without playing, so byes ripple through the bracket on their own.
+//
+// A player's report only stands once someone else confirms it. A recorded result can still be
+// corrected until a later match that depends on it has been played.
+package bracket
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+type Format string
+
+const (
+	SingleElimination Format = "single_elimination"
+	DoubleElimination Format = "double_elimination"
+	RoundRobin        Format = "round_robin"
+)
+
+// Section is the part of the bracket a match belongs to
+type Section string
+
+const (
+	Winners Section = "winners"
+	Losers  Section = "losers" // double elimination only
+	Final   Section = "final"  // the grand final of double elimination
+	Pool    Section = "pool"   // round robin
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending" // waiting for earlier results
+	StatusReady     Status = "ready"
+	StatusCompleted Status = "completed"
+	StatusBye       Status = "bye" // decided without being played
+)
+
+// MinEntrants is the smallest field a bracket can be built for
+const MinEntrants = 2
+
+// Source says who takes a slot: a seed, or the winner or loser of an earlier match
+type Source struct {
+	Seed  int  `json:"seed,omitempty"`  // 1 is the top seed
+	Match int  `json:"match,omitempty"` // ID of the earlier match
+	Loser bool `json:"loser,omitempty"`
+}
+
+type Match struct {
+	ID       int       `json:"id"`
+	Section  Section   `json:"section"`
+	Round    int       `json:"round"`
+	Position int       `json:"position"`
+	Sources  [2]Source `json:"sources"`
+	// Players are 0 while the slot is undecided or empty
+	Players [2]int64        `json:"players"`
+	Status  Status          `json:"status"`
+	Winner  int64           `json:"winner,omitempty"` // 0 for a draw, or a bye with nobody in it
+	Score   json.RawMessage `json:"score,omitempty"`
+	// Reset marks the second grand final, only played when the losers' bracket champion wins
+	// the first
+	Reset bool `json:"reset,omitempty"`
+	// Report is a result waiting to be confirmed
+	Report *Report `json:"report,omitempty"`
+}
+
+// Report is a result reported by one of the players of a ready match
+type Report struct {
+	By     int64           `json:"by"`
+	Winner int64           `json:"winner,omitempty"` // 0 for a draw
+	Score  json.RawMessage `json:"score,omitempty"`
+}
+
+type Bracket struct {
+	Format Format `json:"format"`
+	// Seeds are the entrant IDs, top seed first
+	Seeds   []int64 `json:"seeds"`
+	Matches []Match `json:"matches"`
+}
+
+// Entrant is a player to seed
+type Entrant struct {
+	ID     int64
+	Rating float64
+}
+
+// Standing is an entrant's record across the bracket
+type Standing struct {
+	ID     int64   `json:"id"`
+	Seed   int     `json:"seed"`
+	Played int     `json:"played"`
+	Wins   int     `json:"wins"`
+	Draws  int     `json:"draws"`
+	Losses int     `json:"losses"`
+	Points float64 `json:"points"` // 1 for a win, 0.5 for a draw
+}
+
+// Seed orders the entrants by rating, strongest first. Equal ratings keep the earlier entrant
+// ahead.
+func Seed(entrants []Entrant) []int64 {
+	sorted := append([]Entrant(nil), entrants...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Rating > sorted[j].Rating })
+
+	seeds := make([]int64, len(sorted))
+	for i, e := range sorted {
+		seeds[i] = e.ID
+	}
+	return seeds
+}
+
+// New builds the bracket for the seeded entrants and plays out any byes
+func New(format Format, seeds []int64) (*Bracket, error) {
+	if len(seeds) < MinEntrants {
+		return nil, fmt.Errorf("at least %d entrants are needed", MinEntrants)
+	}
+	seen := make(map[int64]bool, len(seeds))
+	for _, id := range seeds {
+		if id == 0 || seen[id] {
+			return nil, fmt.Errorf("entrant %d is invalid or listed twice", id)
+		}
+		seen[id] = true
+	}
+
+	b := &Bracket{Format: format, Seeds: seeds}
+	switch format {
+	case SingleElimination:
+		b.addElimination(false)
+	case DoubleElimination:
+		b.addElimination(true)
+	case RoundRobin:
+		b.addRoundRobin()
+	default:
+		return nil, fmt.Errorf("unknown format %s", format)
+	}
+
+	b.advance()
+	return b, nil
+}
+
+// Match returns the match with the ID, or nil
+func (b *Bracket) Match(id int) *Match {
+	if id < 1 || id > len(b.Matches) {
+		return nil
+	}
+	return &b.Matches[id-1]
+}
+
+// Record stores the result of a ready match and moves the players on. A winner of 0 is a draw,
+// which only round robin allows.
+func (b *Bracket) Record(id int, winner int64, score json.RawMessage) error {
+	m, err := b.ready(id, winner)
+	if err != nil {
+		return err
+	}
+
+	m.Status = StatusCompleted
+	m.Winner = winner
+	m.Score = score
+	m.Report = nil
+	b.advance()
+	return nil
+}
+
+// Report stores a player's result for a ready match until someone else confirms it. A later
+// report replaces it.
+func (b *Bracket) Report(id int, by, winner int64, score json.RawMessage) error {
+	m, err := b.ready(id, winner)
+	if err != nil {
+		return err
+	}
+	if by != m.Players[0] && by != m.Players[1] {
+		return fmt.Errorf("player %d is not in match %d", by, id)
+	}
+
+	m.Report = &Report{By: by, Winner: winner, Score: score}
+	return nil
+}
+
+// Confirm records the reported result of a match. It cannot be confirmed by whoever reported it.
+func (b *Bracket) Confirm(id int, by int64) error {
+	m := b.Match(id)
+	if m == nil {
+		return fmt.Errorf("match %d not found", id)
+	}
+	if m.Report == nil {
+		return fmt.Errorf("match %d has no result to confirm", id)
+	}
+	if m.Report.By == by {
+		return fmt.Errorf("a result must be confirmed by someone other than who reported it")
+	}
+
+	return b.Record(id, m.Report.Winner, m.Report.Score)
+}
+
+// Correct replaces the result of a completed match. A new winner is only allowed while no later
+// match that depends on it has been played; those matches are then filled in again.
+func (b *Bracket) Correct(id int, winner int64, score json.RawMessage) error {
+	m := b.Match(id)
+	if m == nil {
+		return fmt.Errorf("match %d not found", id)
+	}
+	if m.Status != StatusCompleted {
+		return fmt.Errorf("match %d has no result to correct", id)
+	}
+	if err := b.checkWinner(m, winner); err != nil {
+		return err
+	}
+	if winner == m.Winner {
+		m.Score = score
+		return nil
+	}
+
+	later := b.dependents(id)
+	for _, laterID := range later {
+		if b.Match(laterID).Status == StatusCompleted {
+			return fmt.Errorf("match %d has already been played", laterID)
+		}
+	}
+	for _, laterID := range later {
+		l := b.Match(laterID)
+		*l = Match{ID: l.ID, Section: l.Section, Round: l.Round, Position: l.Position, Sources: l.Sources,
+			Status: StatusPending, Reset: l.Reset}
+	}
+
+	m.Winner = winner
+	m.Score = score
+	b.advance()
+	return nil
+}
+
+// ready returns a match that is waiting for its result, checking that winner can win it
+func (b *Bracket) ready(id int, winner int64) (*Match, error) {
+	m := b.Match(id)
+	if m == nil {
+		return nil, fmt.Errorf("match %d not found", id)
+	}
+	switch m.Status {
+	case StatusCompleted, StatusBye:
+		return nil, fmt.Errorf("match %d already has a result", id)
+	case StatusPending:
+		return nil, fmt.Errorf("match %d is waiting for earlier results", id)
+	}
+	if err := b.checkWinner(m, winner); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (b *Bracket) checkWinner(m *Match, winner int64) error {
+	if winner == 0 && b.Format != RoundRobin {
+		return fmt.Errorf("knockout matches cannot end in a draw")
+	}
+	if winner != 0 && winner != m.Players[0] && winner != m.Players[1] {
+		return fmt.Errorf("player %d is not in match %d", winner, m.ID)
+	}
+	return nil
+}
+
+// dependents returns the IDs of the later matches whose players depend, directly or through
+// other matches, on the result of the match
+func (b *Bracket) dependents(id int) []int {
+	found := map[int]bool{id: true}
+	var ids []int
+	// Sources always point at earlier matches, so a single pass finds them all
+	for _, m := range b.Matches {
+		for _, src := range m.Sources {
+			if src.Match != 0 && found[src.Match] && !found[m.ID] {
+				found[m.ID] = true
+				ids = append(ids, m.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// Done reports whether every match has been decided
+func (b *Bracket) Done() bool {
+	for _, m := range b.Matches {
+		if m.Status != StatusCompleted && m.Status != StatusBye {
+			return false
+		}
+	}
+	return true
+}
+
+// Champion returns the winner of a finished bracket, or 0 while it is still going
+func (b *Bracket) Champion() int64 {
+	if !b.Done() || len(b.Matches) == 0 {
+		return 0
+	}
+	if b.Format == RoundRobin {
+		return b.Standings()[0].ID
+	}
+	// The last match built is the final, or the grand final reset
+	return b.Matches[len(b.Matches)-1].Winner
+}
+
+// Standings tallies every entrant's results, best first: by points, then wins, then seed
+func (b *Bracket) Standings() []Standing {
+	standings := make([]Standing, len(b.Seeds))
+	index := make(map[int64]int, len(b.Seeds))
+	for i, id := range b.Seeds {
+		standings[i] = Standing{ID: id, Seed: i + 1}
+		index[id] = i
+	}
+
+	for _, m := range b.Matches {
+		if m.Status != StatusCompleted {
+			continue
+		}
+		for _, id := range m.Players {
+			s := &standings[index[id]]
+			s.Played++
+			switch m.Winner {
+			case 0:
+				s.Draws++
+				s.Points += 0.5
+			case id:
+				s.Wins++
+				s.Points++
+			default:
+				s.Losses++
+			}
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Points != standings[j].Points {
+			return standings[i].Points > standings[j].Points
+		}
+		if standings[i].Wins != standings[j].Wins {
+			return standings[i].Wins > standings[j].Wins
+		}
+		return standings[i].Seed < standings[j].Seed
+	})
+	return standings
+}
+
+func (b *Bracket) add(m Match) int {
+	m.ID = len(b.Matches) + 1
+	m.Status = StatusPending
+	b.Matches = append(b.Matches, m)
+	return m.ID
+}
+
+// addElimination lays out a knockout bracket sized to the next power of two, with the top
+// seeds drawn against the empty slots. A double elimination bracket adds the losers' bracket,
+// where players get a second life, and a grand final between the two champions.
+func (b *Bracket) addElimination(double bool) {
+	size, rounds := 1, 0
+	for size < len(b.Seeds) {
+		size *= 2
+		rounds++
+	}
+
+	// winners[r][p] is the ID of the match at position p of round r+1
+	order := seedOrder(size)
+	winners := make([][]int, rounds)
+	for r := 0; r < rounds; r++ {
+		winners[r] = make([]int, size>>(r+1))
+		for p := range winners[r] {
+			m := Match{Section: Winners, Round: r + 1, Position: p + 1}
+			if r == 0 {
+				m.Sources = [2]Source{{Seed: order[2*p]}, {Seed: order[2*p+1]}}
+			} else {
+				m.Sources = [2]Source{{Match: winners[r-1][2*p]}, {Match: winners[r-1][2*p+1]}}
+			}
+			winners[r][p] = b.add(m)
+		}
+	}
+	if !double {
+		return
+	}
+
+	// Losers of the first round play each other; from then on, rounds alternate between
+	// taking in the losers of the next winners' round and halving the field. Dropped players
+	// are drawn in reverse so they do not meet the player who just beat them again.
+	losersChampion := Source{Match: winners[0][0], Loser: true}
+	var previous []int
+	round := 0
+	for r := 0; r < rounds-1; r++ {
+		if r == 0 {
+			round++
+			previous = make([]int, len(winners[0])/2)
+			for p := range previous {
+				previous[p] = b.add(Match{Section: Losers, Round: round, Position: p + 1, Sources: [2]Source{
+					{Match: winners[0][2*p], Loser: true}, {Match: winners[0][2*p+1], Loser: true},
+				}})
+			}
+		} else {
+			round++
+			halved := make([]int, len(previous)/2)
+			for p := range halved {
+				halved[p] = b.add(Match{Section: Losers, Round: round, Position: p + 1, Sources: [2]Source{
+					{Match: previous[2*p]}, {Match: previous[2*p+1]},
+				}})
+			}
+			previous = halved
+		}
+
+		round++
+		dropping := winners[r+1]
+		merged := make([]int, len(previous))
+		for p := range merged {
+			merged[p] = b.add(Match{Section: Losers, Round: round, Position: p + 1, Sources: [2]Source{
+				{Match: previous[p]}, {Match: dropping[len(dropping)-1-p], Loser: true},
+			}})
+		}
+		previous = merged
+	}
+	if previous != nil {
+		losersChampion = Source{Match: previous[0]}
+	}
+
+	final := b.add(Match{Section: Final, Round: 1, Position: 1, Sources: [2]Source{
+		{Match: winners[rounds-1][0]}, losersChampion,
+	}})
+	b.add(Match{Section: Final, Round: 2, Position: 1, Reset: true, Sources: [2]Source{
+		{Match: final}, {Match: final, Loser: true},
+	}})
+}
+
+// addRoundRobin pairs everyone with everyone once using the circle method. With an odd field
+// one player sits out each round.
+func (b *Bracket) addRoundRobin() {
+	players := make([]int, len(b.Seeds))
+	for i := range players {
+		players[i] = i + 1
+	}
+	if len(players)%2 == 1 {
+		players = append(players, 0)
+	}
+
+	n := len(players)
+	for round := 1; round < n; round++ {
+		position := 0
+		for i := 0; i < n/2; i++ {
+			a, c := players[i], players[n-1-i]
+			if a == 0 || c == 0 {
+				continue
+			}
+			position++
+			b.add(Match{Section: Pool, Round: round, Position: position, Sources: [2]Source{{Seed: a}, {Seed: c}}})
+		}
+		// Keep the first player in place and rotate the rest
+		players = append([]int{players[0], players[n-1]}, players[1:n-1]...)
+	}
+}
+
+// advance fills in every match whose players are now known, until nothing changes
+func (b *Bracket) advance() {
+	for changed := true; changed; {
+		changed = false
+		for i := range b.Matches {
+			m := &b.Matches[i]
+			if m.Status != StatusPending {
+				continue
+			}
+			if m.Reset {
+				changed = b.advanceReset(m) || changed
+				continue
+			}
+
+			decided := true
+			for slot, src := range m.Sources {
+				player, ok := b.resolve(src)
+				if !ok {
+					decided = false
+					continue
+				}
+				if m.Players[slot] != player {
+					m.Players[slot] = player
+					changed = true
+				}
+			}
+			if !decided {
+				continue
+			}
+
+			if m.Players[0] != 0 && m.Players[1] != 0 {
+				m.Status = StatusReady
+			} else {
+				m.Status = StatusBye
+				m.Winner = m.Players[0] + m.Players[1]
+			}
+			changed = true
+		}
+	}
+}
+
+// advanceReset decides whether the grand final has to be replayed: only when the player coming
+// from the losers' bracket won it, since both then have a single loss
+func (b *Bracket) advanceReset(m *Match) bool {
+	final := b.Match(m.Sources[0].Match)
+	switch final.Status {
+	case StatusCompleted:
+		if final.Winner == final.Players[1] {
+			m.Players = final.Players
+			m.Status = StatusReady
+		} else {
+			m.Status = StatusBye
+			m.Winner = final.Winner
+		}
+		return true
+	case StatusBye:
+		m.Status = StatusBye
+		m.Winner = final.Winner
+		return true
+	}
+	return false
+}
+
+// resolve returns the player a source points at, and whether that is decided yet. A decided
+// slot with nobody in it is 0.
+func (b *Bracket) resolve(src Source) (int64, bool) {
+	if src.Match == 0 {
+		if src.Seed > len(b.Seeds) {
+			return 0, true
+		}
+		return b.Seeds[src.Seed-1], true
+	}
+
+	m := b.Match(src.Match)
+	switch m.Status {
+	case StatusCompleted:
+		if !src.Loser {
+			return m.Winner, true
+		}
+		if m.Winner == m.Players[0] {
+			return m.Players[1], true
+		}
+		return m.Players[0], true
+	case StatusBye:
+		if src.Loser {
+			return 0, true
+		}
+		return m.Winner, true
+	}
+	return 0, false
+}
+
+// seedOrder lists the seeds in bracket order for a field of size players, so that the top
+// seeds can only meet in the latest rounds: 1, 8, 4, 5, 2, 7, 3, 6 for eight
+func seedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		n := len(order) * 2
+		next := make([]int, 0, n)
+		for _, seed := range order {
+			next = append(next, seed, n+1-seed)
+		}
+		order = next
+	}
+	return order
+}
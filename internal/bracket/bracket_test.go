@@ -0,0 +1,300 @@
+package bracket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ids(n int) []int64 {
+	seeds := make([]int64, n)
+	for i := range seeds {
+		seeds[i] = int64(i + 1)
+	}
+	return seeds
+}
+
+// playOut records a win for the better seed (the lower ID) in every ready match until the
+// bracket is done
+func playOut(t *testing.T, b *Bracket) {
+	for !b.Done() {
+		progress := false
+		for _, m := range b.Matches {
+			if m.Status != StatusReady {
+				continue
+			}
+			winner := m.Players[0]
+			if m.Players[1] < winner {
+				winner = m.Players[1]
+			}
+			require.NoError(t, b.Record(m.ID, winner, nil))
+			progress = true
+		}
+		require.True(t, progress, "bracket is stuck")
+	}
+}
+
+func TestSeed_StrongestFirst(t *testing.T) {
+	seeds := Seed([]Entrant{{ID: 1, Rating: 1400}, {ID: 2, Rating: 1700}, {ID: 3, Rating: 1550}, {ID: 4, Rating: 1700}})
+	assert.Equal(t, []int64{2, 4, 3, 1}, seeds)
+}
+
+func TestSeedOrder(t *testing.T) {
+	assert.Equal(t, []int{1, 8, 4, 5, 2, 7, 3, 6}, seedOrder(8))
+}
+
+func TestSingleElimination_ByesForTopSeeds(t *testing.T) {
+	b, err := New(SingleElimination, ids(6))
+	require.NoError(t, err)
+
+	// Eight slots: the first round is 1-bye, 4-5, 2-bye, 3-6
+	require.Len(t, b.Matches, 7)
+	assert.Equal(t, StatusBye, b.Matches[0].Status)
+	assert.Equal(t, int64(1), b.Matches[0].Winner)
+	assert.Equal(t, StatusReady, b.Matches[1].Status)
+	assert.Equal(t, [2]int64{4, 5}, b.Matches[1].Players)
+
+	// Seed 1 already waits in the semi-final
+	semi := b.Matches[4]
+	assert.Equal(t, Winners, semi.Section)
+	assert.Equal(t, 2, semi.Round)
+	assert.Equal(t, int64(1), semi.Players[0])
+	assert.Equal(t, StatusPending, semi.Status)
+
+	playOut(t, b)
+	assert.Equal(t, int64(1), b.Champion())
+}
+
+func TestSingleElimination_AdvancesWinners(t *testing.T) {
+	b, err := New(SingleElimination, ids(4))
+	require.NoError(t, err)
+
+	require.NoError(t, b.Record(1, 4, nil)) // upset: 4 beats 1
+	require.NoError(t, b.Record(2, 2, nil))
+
+	final := b.Match(3)
+	assert.Equal(t, StatusReady, final.Status)
+	assert.Equal(t, [2]int64{4, 2}, final.Players)
+	assert.Equal(t, int64(0), b.Champion())
+
+	require.NoError(t, b.Record(3, 4, nil))
+	assert.True(t, b.Done())
+	assert.Equal(t, int64(4), b.Champion())
+}
+
+func TestRecord_Errors(t *testing.T) {
+	b, err := New(SingleElimination, ids(4))
+	require.NoError(t, err)
+
+	assert.Error(t, b.Record(3, 1, nil), "final is still pending")
+	assert.Error(t, b.Record(1, 0, nil), "knockout draw")
+	assert.Error(t, b.Record(1, 2, nil), "player is in the other match")
+	assert.Error(t, b.Record(9, 1, nil), "no such match")
+
+	require.NoError(t, b.Record(1, 1, nil))
+	assert.Error(t, b.Record(1, 1, nil), "already played")
+}
+
+func TestReport_StandsOnceConfirmed(t *testing.T) {
+	b, err := New(SingleElimination, ids(4))
+	require.NoError(t, err)
+
+	require.NoError(t, b.Report(1, 4, 4, nil))
+	assert.Equal(t, StatusReady, b.Match(1).Status)
+	assert.Error(t, b.Confirm(1, 4), "reporter confirms their own result")
+
+	// The other player reports it the other way round, so now the first has to confirm
+	require.NoError(t, b.Report(1, 1, 1, nil))
+	assert.Error(t, b.Confirm(1, 1))
+	require.NoError(t, b.Confirm(1, 4))
+
+	assert.Equal(t, StatusCompleted, b.Match(1).Status)
+	assert.Equal(t, int64(1), b.Match(1).Winner)
+	assert.Nil(t, b.Match(1).Report)
+	assert.Equal(t, int64(1), b.Match(3).Players[0])
+}
+
+func TestReport_Errors(t *testing.T) {
+	b, err := New(SingleElimination, ids(4))
+	require.NoError(t, err)
+
+	assert.Error(t, b.Report(1, 2, 1, nil), "reporter is in the other match")
+	assert.Error(t, b.Report(1, 1, 2, nil), "winner is in the other match")
+	assert.Error(t, b.Report(3, 1, 1, nil), "final is still pending")
+	assert.Error(t, b.Confirm(1, 1), "nothing reported")
+	assert.Error(t, b.Confirm(9, 1), "no such match")
+}
+
+func TestCorrect_RefillsLaterMatches(t *testing.T) {
+	b, err := New(SingleElimination, ids(4))
+	require.NoError(t, err)
+
+	require.NoError(t, b.Record(1, 1, nil))
+	require.NoError(t, b.Record(2, 2, nil))
+	require.NoError(t, b.Report(3, 1, 1, nil))
+
+	require.NoError(t, b.Correct(1, 4, nil))
+	final := b.Match(3)
+	assert.Equal(t, StatusReady, final.Status)
+	assert.Equal(t, [2]int64{4, 2}, final.Players)
+	assert.Nil(t, final.Report, "the report was for the old pairing")
+
+	// Same winner, new score: nothing moves
+	require.NoError(t, b.Correct(2, 2, []byte(`{"sets":[]}`)))
+	assert.Equal(t, [2]int64{4, 2}, b.Match(3).Players)
+
+	assert.Error(t, b.Correct(3, 4, nil), "final not played yet")
+	assert.Error(t, b.Correct(1, 2, nil), "player is in the other match")
+}
+
+func TestCorrect_BlockedOnceLaterMatchPlayed(t *testing.T) {
+	b, err := New(DoubleElimination, ids(4))
+	require.NoError(t, err)
+
+	require.NoError(t, b.Record(1, 1, nil))
+	require.NoError(t, b.Record(2, 2, nil))
+	require.NoError(t, b.Record(3, 1, nil)) // winners' final
+
+	// Match 1 feeds the winners' final, which has been played
+	assert.Error(t, b.Correct(1, 4, nil))
+	// The winners' final only feeds matches still to be played, and its new loser drops down
+	require.NoError(t, b.Correct(3, 2, nil))
+	assert.Equal(t, int64(1), b.Match(5).Players[1])
+	assert.Equal(t, int64(2), b.Match(6).Players[0])
+	assert.Equal(t, StatusPending, b.Match(5).Status)
+}
+
+func TestCorrect_GrandFinalReset(t *testing.T) {
+	b, err := New(DoubleElimination, ids(2))
+	require.NoError(t, err)
+
+	require.NoError(t, b.Record(1, 1, nil))
+	require.NoError(t, b.Record(2, 1, nil))
+	assert.True(t, b.Done())
+
+	// Had the losers' champion won, the final is replayed
+	require.NoError(t, b.Correct(2, 2, nil))
+	assert.Equal(t, StatusReady, b.Match(3).Status)
+	assert.False(t, b.Done())
+}
+
+func TestDoubleElimination_EveryoneLosesTwice(t *testing.T) {
+	for n := 2; n <= 12; n++ {
+		b, err := New(DoubleElimination, ids(n))
+		require.NoError(t, err)
+
+		playOut(t, b)
+		assert.Equal(t, int64(1), b.Champion(), "field of %d", n)
+
+		// Everyone but the champion is knocked out by their second loss
+		for _, s := range b.Standings() {
+			if s.ID == 1 {
+				assert.Equal(t, 0, s.Losses)
+			} else {
+				assert.Equal(t, 2, s.Losses, "player %d in a field of %d", s.ID, n)
+			}
+		}
+	}
+}
+
+func TestDoubleElimination_GrandFinalReset(t *testing.T) {
+	b, err := New(DoubleElimination, ids(4))
+	require.NoError(t, err)
+
+	// Winners' bracket: 1 beats 4, 2 beats 3, 1 beats 2
+	require.NoError(t, b.Record(1, 1, nil))
+	require.NoError(t, b.Record(2, 2, nil))
+	require.NoError(t, b.Record(3, 1, nil))
+
+	// Losers' bracket: 4 beats 3, then 2 beats 4
+	loserRound1 := b.Match(4)
+	assert.Equal(t, [2]int64{4, 3}, loserRound1.Players)
+	require.NoError(t, b.Record(4, 4, nil))
+	loserFinal := b.Match(5)
+	assert.Equal(t, [2]int64{4, 2}, loserFinal.Players)
+	require.NoError(t, b.Record(5, 2, nil))
+
+	grandFinal, reset := b.Match(6), b.Match(7)
+	assert.Equal(t, [2]int64{1, 2}, grandFinal.Players)
+	require.NoError(t, b.Record(6, 2, nil))
+
+	// 1 has now lost once too, so the final is replayed
+	assert.Equal(t, StatusReady, reset.Status)
+	assert.False(t, b.Done())
+	require.NoError(t, b.Record(7, 2, nil))
+	assert.Equal(t, int64(2), b.Champion())
+}
+
+func TestDoubleElimination_NoResetWhenWinnersChampionWins(t *testing.T) {
+	b, err := New(DoubleElimination, ids(2))
+	require.NoError(t, err)
+
+	require.NoError(t, b.Record(1, 1, nil))
+	require.NoError(t, b.Record(2, 1, nil))
+
+	assert.Equal(t, StatusBye, b.Match(3).Status)
+	assert.True(t, b.Done())
+	assert.Equal(t, int64(1), b.Champion())
+}
+
+func TestRoundRobin_EveryoneMeetsOnce(t *testing.T) {
+	b, err := New(RoundRobin, ids(5))
+	require.NoError(t, err)
+
+	require.Len(t, b.Matches, 10)
+	pairs := make(map[[2]int64]bool)
+	rounds := make(map[int]map[int64]bool)
+	for _, m := range b.Matches {
+		assert.Equal(t, StatusReady, m.Status)
+		a, c := m.Players[0], m.Players[1]
+		if a > c {
+			a, c = c, a
+		}
+		assert.False(t, pairs[[2]int64{a, c}], "%d and %d meet twice", a, c)
+		pairs[[2]int64{a, c}] = true
+
+		if rounds[m.Round] == nil {
+			rounds[m.Round] = make(map[int64]bool)
+		}
+		assert.False(t, rounds[m.Round][a] || rounds[m.Round][c], "a player plays twice in round %d", m.Round)
+		rounds[m.Round][a], rounds[m.Round][c] = true, true
+	}
+	assert.Len(t, rounds, 5)
+}
+
+func TestRoundRobin_StandingsAndDraws(t *testing.T) {
+	b, err := New(RoundRobin, ids(3))
+	require.NoError(t, err)
+
+	for _, m := range b.Matches {
+		var winner int64
+		switch {
+		case m.Players[0] == 3 || m.Players[1] == 3:
+			winner = 3
+		default:
+			winner = 0 // 1 and 2 draw
+		}
+		require.NoError(t, b.Record(m.ID, winner, nil))
+	}
+
+	standings := b.Standings()
+	assert.Equal(t, int64(3), standings[0].ID)
+	assert.Equal(t, 2.0, standings[0].Points)
+	// 1 and 2 are level, so the higher seed goes first
+	assert.Equal(t, int64(1), standings[1].ID)
+	assert.Equal(t, 0.5, standings[1].Points)
+	assert.Equal(t, 1, standings[1].Draws)
+	assert.Equal(t, int64(3), b.Champion())
+}
+
+func TestNew_Errors(t *testing.T) {
+	_, err := New(SingleElimination, ids(1))
+	assert.Error(t, err)
+
+	_, err = New(RoundRobin, []int64{1, 2, 2})
+	assert.Error(t, err)
+
+	_, err = New(Format("swiss"), ids(4))
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -16,6 +17,15 @@ type Config struct {
 	AWS      AWSConfig
 	Server   ServerConfig
 	RateLimit RateLimitConfig
+	Admin    AdminConfig
+	Ranking  RankingConfig
+	Swipe    SwipeConfig
+	Games    GamesConfig
+	Rating   RatingConfig
+	Leaderboard LeaderboardConfig
+	Ladder   LadderConfig
+	Calendar CalendarConfig
+	Plans    map[string]PlanConfig
 }
 
 type DatabaseConfig struct {
@@ -69,9 +79,83 @@ type ServerConfig struct {
 	CORSOrigin  string
 }
 
+// RateLimitConfig is the default per-user limit and the stricter per-IP limit on /auth
 type RateLimitConfig struct {
-	Requests int
-	Window   time.Duration
+	Requests     int
+	Window       time.Duration
+	AuthRequests int
+	AuthWindow   time.Duration
+}
+
+// RankingConfig holds the swipe deck scoring weights. Weights are relative to each other.
+type RankingConfig struct {
+	SharedSportsWeight float64
+	RatingWeight       float64
+	AvailabilityWeight float64
+	DistanceWeight     float64
+	RecencyWeight      float64
+	RankWeight         float64
+	VenuesWeight       float64
+	PartnerWeight      float64 // only applies to partner-seeking decks
+	CandidatePoolSize  int
+}
+
+type SwipeConfig struct {
+	UndoWindow time.Duration
+}
+
+// GamesConfig controls result reporting. Results the opponent has not answered within
+// ConfirmWindow are confirmed automatically.
+type GamesConfig struct {
+	ConfirmWindow       time.Duration
+	AutoConfirmInterval time.Duration
+}
+
+// RatingConfig tunes Glicko-2. Tau limits how fast volatility changes; each Period without a
+// game widens a player's deviation.
+type RatingConfig struct {
+	Tau    float64
+	Period time.Duration
+}
+
+// LeaderboardConfig controls who is ranked: players drop off after ActiveWindow without a
+// confirmed game. Boards are rebuilt from MySQL every RebuildInterval.
+type LeaderboardConfig struct {
+	ActiveWindow    time.Duration
+	RebuildInterval time.Duration
+}
+
+// LadderConfig controls challenge deadlines: overdue challenges are forfeited or expired every
+// DeadlineInterval
+type LadderConfig struct {
+	DeadlineInterval time.Duration
+}
+
+// CalendarConfig controls calendar feeds. PublicURL is the externally reachable base of the
+// API used in feed links, UIDDomain makes event UIDs globally unique, and feeds keep games
+// from the last Lookback so cancellations reach subscribers.
+type CalendarConfig struct {
+	PublicURL       string
+	UIDDomain       string
+	Lookback        time.Duration
+	RefreshInterval time.Duration
+}
+
+// PlanConfig is what a subscription plan includes
+type PlanConfig struct {
+	Quotas   QuotaLimits
+	Features []string
+}
+
+// QuotaLimits are daily allowances; a negative value means unlimited
+type QuotaLimits struct {
+	RightSwipes int
+	SuperLikes  int
+	Rewinds     int
+}
+
+type AdminConfig struct {
+	Emails []string
 }
 
 var AppConfig Config
@@ -146,9 +230,96 @@ func Load() error {
 	// Rate limit config
 	rateLimitRequests, _ := strconv.Atoi(getEnv("RATE_LIMIT_REQUESTS", "100"))
 	rateLimitWindow, _ := time.ParseDuration(getEnv("RATE_LIMIT_WINDOW", "1m"))
+	authRateLimitWindow, _ := time.ParseDuration(getEnv("RATE_LIMIT_AUTH_WINDOW", "1m"))
 	AppConfig.RateLimit = RateLimitConfig{
-		Requests: rateLimitRequests,
-		Window:   rateLimitWindow,
+		Requests:     rateLimitRequests,
+		Window:       rateLimitWindow,
+		AuthRequests: getEnvInt("RATE_LIMIT_AUTH_REQUESTS", 20),
+		AuthWindow:   authRateLimitWindow,
+	}
+
+	// Ranking config
+	candidatePoolSize, _ := strconv.Atoi(getEnv("RANKING_CANDIDATE_POOL", "200"))
+	AppConfig.Ranking = RankingConfig{
+		SharedSportsWeight: getEnvFloat("RANKING_WEIGHT_SHARED_SPORTS", 3),
+		RatingWeight:       getEnvFloat("RANKING_WEIGHT_RATING", 2),
+		AvailabilityWeight: getEnvFloat("RANKING_WEIGHT_AVAILABILITY", 2),
+		DistanceWeight:     getEnvFloat("RANKING_WEIGHT_DISTANCE", 1.5),
+		RecencyWeight:      getEnvFloat("RANKING_WEIGHT_RECENCY", 1),
+		RankWeight:         getEnvFloat("RANKING_WEIGHT_RANK", 0.5),
+		VenuesWeight:       getEnvFloat("RANKING_WEIGHT_VENUES", 1),
+		PartnerWeight:      getEnvFloat("RANKING_WEIGHT_PARTNER", 3),
+		CandidatePoolSize:  candidatePoolSize,
+	}
+
+	// Swipe config
+	undoWindow, _ := time.ParseDuration(getEnv("SWIPE_UNDO_WINDOW", "5m"))
+	AppConfig.Swipe = SwipeConfig{
+		UndoWindow: undoWindow,
+	}
+
+	// Games config
+	confirmWindow, _ := time.ParseDuration(getEnv("GAME_CONFIRM_WINDOW", "48h"))
+	autoConfirmInterval, _ := time.ParseDuration(getEnv("GAME_AUTO_CONFIRM_INTERVAL", "1m"))
+	AppConfig.Games = GamesConfig{
+		ConfirmWindow:       confirmWindow,
+		AutoConfirmInterval: autoConfirmInterval,
+	}
+
+	// Rating config
+	ratingPeriod, _ := time.ParseDuration(getEnv("RATING_PERIOD", "168h"))
+	AppConfig.Rating = RatingConfig{
+		Tau:    getEnvFloat("RATING_TAU", 0.5),
+		Period: ratingPeriod,
+	}
+
+	// Leaderboard config
+	activeWindow, _ := time.ParseDuration(getEnv("LEADERBOARD_ACTIVE_WINDOW", "2160h"))
+	rebuildInterval, _ := time.ParseDuration(getEnv("LEADERBOARD_REBUILD_INTERVAL", "1h"))
+	AppConfig.Leaderboard = LeaderboardConfig{
+		ActiveWindow:    activeWindow,
+		RebuildInterval: rebuildInterval,
+	}
+
+	// Ladder config
+	ladderDeadlineInterval, _ := time.ParseDuration(getEnv("LADDER_DEADLINE_INTERVAL", "1m"))
+	AppConfig.Ladder = LadderConfig{
+		DeadlineInterval: ladderDeadlineInterval,
+	}
+
+	// Calendar config
+	calendarLookback, _ := time.ParseDuration(getEnv("CALENDAR_FEED_LOOKBACK", "720h"))
+	calendarRefresh, _ := time.ParseDuration(getEnv("CALENDAR_FEED_REFRESH", "1h"))
+	AppConfig.Calendar = CalendarConfig{
+		PublicURL:       strings.TrimSuffix(getEnv("CALENDAR_PUBLIC_URL", "http://localhost:8080"), "/"),
+		UIDDomain:       getEnv("CALENDAR_UID_DOMAIN", "swipesports.app"),
+		Lookback:        calendarLookback,
+		RefreshInterval: calendarRefresh,
+	}
+
+	// Plan config
+	AppConfig.Plans = map[string]PlanConfig{
+		"free": {
+			Quotas: QuotaLimits{
+				RightSwipes: getEnvInt("QUOTA_FREE_RIGHT_SWIPES", 100),
+				SuperLikes:  getEnvInt("QUOTA_FREE_SUPER_LIKES", 1),
+				Rewinds:     getEnvInt("QUOTA_FREE_REWINDS", 3),
+			},
+			Features: getEnvList("PLAN_FREE_FEATURES", ""),
+		},
+		"plus": {
+			Quotas: QuotaLimits{
+				RightSwipes: getEnvInt("QUOTA_PLUS_RIGHT_SWIPES", -1),
+				SuperLikes:  getEnvInt("QUOTA_PLUS_SUPER_LIKES", 5),
+				Rewinds:     getEnvInt("QUOTA_PLUS_REWINDS", -1),
+			},
+			Features: getEnvList("PLAN_PLUS_FEATURES", "likes_inbox"),
+		},
+	}
+
+	// Admin config
+	AppConfig.Admin = AdminConfig{
+		Emails: getEnvList("ADMIN_EMAILS", ""),
 	}
 
 	return nil
@@ -159,4 +330,28 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-} 
\ No newline at end of file
+} 
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
\ No newline at end of file
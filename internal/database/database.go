@@ -71,20 +71,27 @@ func initSchema() error {
 			play_style VARCHAR(100),
 			preferred_timeslots VARCHAR(100),
 			availability JSON,
+			partner_sports JSON,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			last_active_at TIMESTAMP NULL,
+			plan VARCHAR(20) NOT NULL DEFAULT 'free',
+			-- Maintained by MySQL so every writer keeps it in sync with the coordinates
+			geohash VARCHAR(12) AS (ST_GeoHash(longitude, latitude, 12)) STORED,
 			INDEX idx_location (location),
 			INDEX idx_gender (gender),
 			INDEX idx_rank (rank),
 			INDEX idx_oauth (oauth_id, oauth_provider),
 			INDEX idx_age (age),
-			INDEX idx_skill_level (skill_level)
+			INDEX idx_skill_level (skill_level),
+			INDEX idx_last_active (last_active_at),
+			INDEX idx_geohash (geohash)
 		)`,
 		`CREATE TABLE IF NOT EXISTS swipes (
 			id BIGINT PRIMARY KEY AUTO_INCREMENT,
 			swiper_id BIGINT NOT NULL,
 			swipee_id BIGINT NOT NULL,
-			direction ENUM('left', 'right') NOT NULL,
+			direction ENUM('left', 'right', 'super') NOT NULL,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (swiper_id) REFERENCES users(id) ON DELETE CASCADE,
 			FOREIGN KEY (swipee_id) REFERENCES users(id) ON DELETE CASCADE,
@@ -94,8 +101,15 @@ func initSchema() error {
 		)`,
 		`CREATE TABLE IF NOT EXISTS matches (
 			id BIGINT PRIMARY KEY AUTO_INCREMENT,
+			kind ENUM('direct', 'group') NOT NULL DEFAULT 'direct',
 			user1_id BIGINT NOT NULL,
-			user2_id BIGINT NOT NULL,
+			user2_id BIGINT NULL,
+			title VARCHAR(100) NULL,
+			sport VARCHAR(50) NULL,
+			status ENUM('active', 'ended') NOT NULL DEFAULT 'active',
+			ended_by BIGINT NULL,
+			end_reason VARCHAR(50),
+			ended_at TIMESTAMP NULL,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (user1_id) REFERENCES users(id) ON DELETE CASCADE,
 			FOREIGN KEY (user2_id) REFERENCES users(id) ON DELETE CASCADE,
@@ -103,19 +117,325 @@ func initSchema() error {
 			INDEX idx_user1 (user1_id),
 			INDEX idx_user2 (user2_id)
 		)`,
+		`CREATE TABLE IF NOT EXISTS match_participants (
+			match_id BIGINT NOT NULL,
+			user_id BIGINT NOT NULL,
+			role ENUM('host', 'member') NOT NULL DEFAULT 'member',
+			status ENUM('active', 'left', 'removed') NOT NULL DEFAULT 'active',
+			joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			left_at TIMESTAMP NULL,
+			PRIMARY KEY (match_id, user_id),
+			FOREIGN KEY (match_id) REFERENCES matches(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			INDEX idx_user_status (user_id, status)
+		)`,
 		`CREATE TABLE IF NOT EXISTS messages (
 			id BIGINT PRIMARY KEY AUTO_INCREMENT,
 			match_id BIGINT NOT NULL,
 			sender_id BIGINT NOT NULL,
 			content TEXT NOT NULL,
-			message_type ENUM('text', 'image', 'audio') DEFAULT 'text',
+			message_type ENUM('text', 'image', 'audio', 'proposal') DEFAULT 'text',
 			media_url VARCHAR(500),
+			proposal_id BIGINT NULL,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (match_id) REFERENCES matches(id) ON DELETE CASCADE,
 			FOREIGN KEY (sender_id) REFERENCES users(id) ON DELETE CASCADE,
 			INDEX idx_match_created (match_id, created_at),
 			INDEX idx_sender (sender_id)
 		)`,
+		`CREATE TABLE IF NOT EXISTS sports (
+			id BIGINT PRIMARY KEY AUTO_INCREMENT,
+			slug VARCHAR(50) NOT NULL UNIQUE,
+			name VARCHAR(100) NOT NULL,
+			display_names JSON,
+			team_size ENUM('singles', 'doubles', 'team') NOT NULL,
+			rating_system ENUM('elo', 'ntrp', 'dupr', 'none') NOT NULL DEFAULT 'elo',
+			skill_levels JSON NOT NULL,
+			active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			INDEX idx_active (active)
+		)`,
+		`CREATE TABLE IF NOT EXISTS discovery_preferences (
+			user_id BIGINT PRIMARY KEY,
+			min_age INT,
+			max_age INT,
+			max_distance_km DECIMAL(7, 2),
+			genders JSON,
+			sports JSON,
+			skill_levels JSON,
+			min_rating INT,
+			max_rating INT,
+			play_styles JSON,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		// Ladders come before games, which reference their challenges
+		`CREATE TABLE IF NOT EXISTS ladders (
+			id BIGINT PRIMARY KEY AUTO_INCREMENT,
+			organizer_id BIGINT NOT NULL,
+			group_id BIGINT NULL,
+			name VARCHAR(100) NOT NULL,
+			sport VARCHAR(50) NOT NULL,
+			challenge_range INT NOT NULL DEFAULT 3,
+			accept_hours INT NOT NULL DEFAULT 72,
+			play_hours INT NOT NULL DEFAULT 336,
+			status ENUM('active', 'closed') NOT NULL DEFAULT 'active',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			FOREIGN KEY (organizer_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (group_id) REFERENCES matches(id) ON DELETE SET NULL,
+			INDEX idx_status_sport (status, sport),
+			INDEX idx_group (group_id),
+			INDEX idx_organizer (organizer_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS ladder_players (
+			ladder_id BIGINT NOT NULL,
+			user_id BIGINT NOT NULL,
+			position INT NOT NULL,
+			wins INT NOT NULL DEFAULT 0,
+			losses INT NOT NULL DEFAULT 0,
+			joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (ladder_id, user_id),
+			FOREIGN KEY (ladder_id) REFERENCES ladders(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			INDEX idx_ladder_position (ladder_id, position),
+			INDEX idx_user (user_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS ladder_challenges (
+			id BIGINT PRIMARY KEY AUTO_INCREMENT,
+			ladder_id BIGINT NOT NULL,
+			challenger_id BIGINT NOT NULL,
+			defender_id BIGINT NOT NULL,
+			status ENUM('pending', 'accepted', 'completed', 'forfeited', 'expired', 'cancelled') NOT NULL DEFAULT 'pending',
+			accept_deadline TIMESTAMP NOT NULL,
+			play_deadline TIMESTAMP NULL,
+			game_id BIGINT NULL,
+			winner_id BIGINT NULL,
+			forfeited_by BIGINT NULL,
+			resolved_at TIMESTAMP NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			FOREIGN KEY (ladder_id) REFERENCES ladders(id) ON DELETE CASCADE,
+			FOREIGN KEY (challenger_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (defender_id) REFERENCES users(id) ON DELETE CASCADE,
+			INDEX idx_ladder_status (ladder_id, status),
+			INDEX idx_status_accept (status, accept_deadline),
+			INDEX idx_status_play (status, play_deadline),
+			INDEX idx_game (game_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS games (
+			id BIGINT PRIMARY KEY AUTO_INCREMENT,
+			match_id BIGINT NULL,
+			challenge_id BIGINT NULL,
+			sport VARCHAR(50) NOT NULL,
+			played_at DATETIME NOT NULL,
+			score JSON NOT NULL,
+			winner_team TINYINT NULL,
+			status ENUM('pending', 'confirmed', 'disputed', 'voided') NOT NULL DEFAULT 'pending',
+			reported_by BIGINT NOT NULL,
+			confirm_deadline TIMESTAMP NOT NULL,
+			confirmed_at TIMESTAMP NULL,
+			disputed_by BIGINT NULL,
+			dispute_reason VARCHAR(500),
+			resolved_by BIGINT NULL,
+			resolution_note VARCHAR(500),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			FOREIGN KEY (match_id) REFERENCES matches(id) ON DELETE SET NULL,
+			FOREIGN KEY (challenge_id) REFERENCES ladder_challenges(id) ON DELETE SET NULL,
+			FOREIGN KEY (reported_by) REFERENCES users(id) ON DELETE CASCADE,
+			INDEX idx_match (match_id),
+			INDEX idx_challenge (challenge_id),
+			INDEX idx_status_deadline (status, confirm_deadline)
+		)`,
+		`CREATE TABLE IF NOT EXISTS game_participants (
+			game_id BIGINT NOT NULL,
+			user_id BIGINT NOT NULL,
+			team TINYINT NOT NULL,
+			PRIMARY KEY (game_id, user_id),
+			FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			INDEX idx_user (user_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS user_ratings (
+			user_id BIGINT NOT NULL,
+			sport VARCHAR(50) NOT NULL,
+			format ENUM('singles', 'doubles', 'team') NOT NULL,
+			rating DOUBLE NOT NULL,
+			deviation DOUBLE NOT NULL,
+			volatility DOUBLE NOT NULL,
+			games_played INT NOT NULL DEFAULT 0,
+			last_played_at DATETIME NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, sport, format),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			INDEX idx_sport_rating (sport, format, rating)
+		)`,
+		`CREATE TABLE IF NOT EXISTS game_proposals (
+			id BIGINT PRIMARY KEY AUTO_INCREMENT,
+			match_id BIGINT NOT NULL,
+			proposed_by BIGINT NOT NULL,
+			sport VARCHAR(50) NOT NULL,
+			starts_at DATETIME NOT NULL,
+			duration_minutes INT NOT NULL,
+			venue VARCHAR(255),
+			venue_id BIGINT NULL,
+			note VARCHAR(500),
+			status ENUM('pending', 'accepted', 'declined', 'countered', 'cancelled') NOT NULL DEFAULT 'pending',
+			counter_of BIGINT NULL,
+			responded_at TIMESTAMP NULL,
+			sequence INT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			FOREIGN KEY (match_id) REFERENCES matches(id) ON DELETE CASCADE,
+			FOREIGN KEY (proposed_by) REFERENCES users(id) ON DELETE CASCADE,
+			INDEX idx_match_created (match_id, created_at),
+			INDEX idx_status_starts (status, starts_at),
+			INDEX idx_venue (venue_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS calendar_feeds (
+			user_id BIGINT PRIMARY KEY,
+			token CHAR(64) NOT NULL UNIQUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS venues (
+			id BIGINT PRIMARY KEY AUTO_INCREMENT,
+			external_id VARCHAR(100) NULL UNIQUE,
+			name VARCHAR(200) NOT NULL,
+			address VARCHAR(500) NOT NULL DEFAULT '',
+			latitude DECIMAL(10, 8) NOT NULL,
+			longitude DECIMAL(11, 8) NOT NULL,
+			sports JSON NOT NULL,
+			surface VARCHAR(20) NULL,
+			indoor BOOLEAN NOT NULL DEFAULT FALSE,
+			lights BOOLEAN NOT NULL DEFAULT FALSE,
+			cost_per_hour DECIMAL(8, 2) NULL,
+			currency CHAR(3) NULL,
+			opening_hours JSON,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			geohash VARCHAR(12) AS (ST_GeoHash(longitude, latitude, 12)) STORED,
+			INDEX idx_geohash (geohash)
+		)`,
+		`CREATE TABLE IF NOT EXISTS venue_favourites (
+			user_id BIGINT NOT NULL,
+			venue_id BIGINT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, venue_id),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (venue_id) REFERENCES venues(id) ON DELETE CASCADE,
+			INDEX idx_venue (venue_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS open_games (
+			id BIGINT PRIMARY KEY AUTO_INCREMENT,
+			host_id BIGINT NOT NULL,
+			sport VARCHAR(50) NOT NULL,
+			starts_at DATETIME NOT NULL,
+			duration_minutes INT NOT NULL,
+			venue_id BIGINT NULL,
+			venue VARCHAR(255),
+			latitude DECIMAL(10, 8) NOT NULL,
+			longitude DECIMAL(11, 8) NOT NULL,
+			skill_levels JSON NOT NULL,
+			spots INT NOT NULL,
+			note VARCHAR(500),
+			status ENUM('open', 'cancelled') NOT NULL DEFAULT 'open',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			geohash VARCHAR(12) AS (ST_GeoHash(longitude, latitude, 12)) STORED,
+			FOREIGN KEY (host_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (venue_id) REFERENCES venues(id) ON DELETE SET NULL,
+			INDEX idx_status_starts (status, starts_at),
+			INDEX idx_host_starts (host_id, starts_at),
+			INDEX idx_geohash (geohash)
+		)`,
+		`CREATE TABLE IF NOT EXISTS open_game_requests (
+			id BIGINT PRIMARY KEY AUTO_INCREMENT,
+			game_id BIGINT NOT NULL,
+			user_id BIGINT NOT NULL,
+			status ENUM('pending', 'approved', 'waitlisted', 'rejected', 'withdrawn') NOT NULL DEFAULT 'pending',
+			message VARCHAR(500),
+			responded_at TIMESTAMP NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			FOREIGN KEY (game_id) REFERENCES open_games(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			UNIQUE KEY uniq_game_user (game_id, user_id),
+			INDEX idx_game_status (game_id, status),
+			INDEX idx_user_status (user_id, status)
+		)`,
+		`CREATE TABLE IF NOT EXISTS open_game_teams (
+			game_id BIGINT PRIMARY KEY,
+			teams JSON NOT NULL,
+			manual BOOLEAN NOT NULL DEFAULT FALSE,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			FOREIGN KEY (game_id) REFERENCES open_games(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS partnerships (
+			id BIGINT PRIMARY KEY AUTO_INCREMENT,
+			sport VARCHAR(50) NOT NULL,
+			user1_id BIGINT NOT NULL,
+			user2_id BIGINT NOT NULL,
+			proposed_by BIGINT NOT NULL,
+			status ENUM('pending', 'active', 'declined', 'dissolved') NOT NULL DEFAULT 'pending',
+			rating DOUBLE NOT NULL,
+			deviation DOUBLE NOT NULL,
+			volatility DOUBLE NOT NULL,
+			seed_rating DOUBLE NOT NULL,
+			seed_deviation DOUBLE NOT NULL,
+			seed_volatility DOUBLE NOT NULL,
+			games_played INT NOT NULL DEFAULT 0,
+			last_played_at DATETIME NULL,
+			formed_at TIMESTAMP NULL,
+			ended_at TIMESTAMP NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			FOREIGN KEY (user1_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (user2_id) REFERENCES users(id) ON DELETE CASCADE,
+			UNIQUE KEY unique_pair (sport, user1_id, user2_id),
+			INDEX idx_user1 (user1_id, status),
+			INDEX idx_user2 (user2_id, status)
+		)`,
+		`CREATE TABLE IF NOT EXISTS tournaments (
+			id BIGINT PRIMARY KEY AUTO_INCREMENT,
+			organizer_id BIGINT NOT NULL,
+			group_id BIGINT NULL,
+			name VARCHAR(100) NOT NULL,
+			sport VARCHAR(50) NOT NULL,
+			format ENUM('single_elimination', 'double_elimination', 'round_robin') NOT NULL,
+			status ENUM('registration', 'in_progress', 'completed', 'cancelled') NOT NULL DEFAULT 'registration',
+			max_entrants INT NOT NULL,
+			starts_at DATETIME NOT NULL,
+			venue VARCHAR(255),
+			note TEXT,
+			bracket JSON NULL,
+			winner_id BIGINT NULL,
+			started_at TIMESTAMP NULL,
+			completed_at TIMESTAMP NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			FOREIGN KEY (organizer_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (group_id) REFERENCES matches(id) ON DELETE SET NULL,
+			FOREIGN KEY (winner_id) REFERENCES users(id) ON DELETE SET NULL,
+			INDEX idx_status_starts (status, starts_at),
+			INDEX idx_group (group_id),
+			INDEX idx_organizer (organizer_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS tournament_entries (
+			tournament_id BIGINT NOT NULL,
+			user_id BIGINT NOT NULL,
+			status ENUM('registered', 'withdrawn') NOT NULL DEFAULT 'registered',
+			seed INT NULL,
+			rating DOUBLE NULL,
+			registered_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			PRIMARY KEY (tournament_id, user_id),
+			FOREIGN KEY (tournament_id) REFERENCES tournaments(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			INDEX idx_user_status (user_id, status)
+		)`,
 	}
 
 	for _, query := range queries {
@@ -129,26 +449,127 @@ func initSchema() error {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	if err := seedSports(); err != nil {
+		return fmt.Errorf("failed to seed sports: %w", err)
+	}
+
 	return nil
 }
 
+// migration is a schema change for databases created before it. One that adds a column or an
+// index names it, so it only runs when the column or index is missing (MySQL has no
+// ADD COLUMN IF NOT EXISTS); other migrations must be safe to run on every start.
+type migration struct {
+	table  string
+	column string
+	index  string
+	query  string
+}
+
+// applied reports whether the column or index the migration adds already exists
+func (m migration) applied() (bool, error) {
+	var query, name string
+	switch {
+	case m.column != "":
+		query = `SELECT COUNT(*) FROM information_schema.COLUMNS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?`
+		name = m.column
+	case m.index != "":
+		query = `SELECT COUNT(*) FROM information_schema.STATISTICS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_NAME = ?`
+		name = m.index
+	default:
+		return false, nil
+	}
+
+	var count int
+	if err := DB.QueryRow(query, m.table, name).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check schema for %s.%s: %w", m.table, name, err)
+	}
+	return count > 0, nil
+}
+
 func runMigrations() error {
-	migrations := []string{
-		`ALTER TABLE users ADD COLUMN IF NOT EXISTS first_name VARCHAR(255)`,
-		`ALTER TABLE users ADD COLUMN IF NOT EXISTS last_name VARCHAR(255)`,
-		`ALTER TABLE users ADD COLUMN IF NOT EXISTS age INT`,
-		`ALTER TABLE users ADD COLUMN IF NOT EXISTS ntrp_rating DECIMAL(3, 1)`,
-		`ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_timeslots VARCHAR(100)`,
-		`ALTER TABLE users ADD INDEX IF NOT EXISTS idx_age (age)`,
-		`ALTER TABLE users ADD INDEX IF NOT EXISTS idx_skill_level (skill_level)`,
+	migrations := []migration{
+		{table: "users", column: "first_name", query: `ALTER TABLE users ADD COLUMN first_name VARCHAR(255)`},
+		{table: "users", column: "last_name", query: `ALTER TABLE users ADD COLUMN last_name VARCHAR(255)`},
+		{table: "users", column: "age", query: `ALTER TABLE users ADD COLUMN age INT`},
+		{table: "users", column: "ntrp_rating", query: `ALTER TABLE users ADD COLUMN ntrp_rating DECIMAL(3, 1)`},
+		{table: "users", column: "preferred_timeslots", query: `ALTER TABLE users ADD COLUMN preferred_timeslots VARCHAR(100)`},
+		{table: "users", index: "idx_age", query: `ALTER TABLE users ADD INDEX idx_age (age)`},
+		{table: "users", index: "idx_skill_level", query: `ALTER TABLE users ADD INDEX idx_skill_level (skill_level)`},
+		{table: "users", column: "last_active_at", query: `ALTER TABLE users ADD COLUMN last_active_at TIMESTAMP NULL`},
+		{table: "users", index: "idx_last_active", query: `ALTER TABLE users ADD INDEX idx_last_active (last_active_at)`},
+		{table: "users", column: "geohash", query: `ALTER TABLE users ADD COLUMN geohash VARCHAR(12) AS (ST_GeoHash(longitude, latitude, 12)) STORED`},
+		{table: "users", index: "idx_geohash", query: `ALTER TABLE users ADD INDEX idx_geohash (geohash)`},
+		{table: "users", column: "plan", query: `ALTER TABLE users ADD COLUMN plan VARCHAR(20) NOT NULL DEFAULT 'free'`},
+		{table: "users", column: "partner_sports", query: `ALTER TABLE users ADD COLUMN partner_sports JSON`},
+		{query: `ALTER TABLE swipes MODIFY direction ENUM('left', 'right', 'super') NOT NULL`},
+		{table: "matches", column: "status", query: `ALTER TABLE matches ADD COLUMN status ENUM('active', 'ended') NOT NULL DEFAULT 'active'`},
+		{table: "matches", column: "ended_by", query: `ALTER TABLE matches ADD COLUMN ended_by BIGINT NULL`},
+		{table: "matches", column: "end_reason", query: `ALTER TABLE matches ADD COLUMN end_reason VARCHAR(50)`},
+		{table: "matches", column: "ended_at", query: `ALTER TABLE matches ADD COLUMN ended_at TIMESTAMP NULL`},
+		{table: "matches", column: "kind", query: `ALTER TABLE matches ADD COLUMN kind ENUM('direct', 'group') NOT NULL DEFAULT 'direct' AFTER id`},
+		{table: "matches", column: "title", query: `ALTER TABLE matches ADD COLUMN title VARCHAR(100) NULL`},
+		{table: "matches", column: "sport", query: `ALTER TABLE matches ADD COLUMN sport VARCHAR(50) NULL`},
+		{query: `ALTER TABLE matches MODIFY user2_id BIGINT NULL`},
+		// Both players of every one-to-one match become its participants
+		{query: `INSERT IGNORE INTO match_participants (match_id, user_id, joined_at)
+			SELECT id, user1_id, created_at FROM matches WHERE kind = 'direct'
+			UNION ALL
+			SELECT id, user2_id, created_at FROM matches WHERE kind = 'direct'`},
+		{query: `ALTER TABLE messages MODIFY message_type ENUM('text', 'image', 'audio', 'proposal') DEFAULT 'text'`},
+		{table: "messages", column: "proposal_id", query: `ALTER TABLE messages ADD COLUMN proposal_id BIGINT NULL`},
+		{query: `ALTER TABLE game_proposals MODIFY status ENUM('pending', 'accepted', 'declined', 'countered', 'cancelled') NOT NULL DEFAULT 'pending'`},
+		{table: "game_proposals", column: "sequence", query: `ALTER TABLE game_proposals ADD COLUMN sequence INT NOT NULL DEFAULT 0`},
+		{table: "game_proposals", column: "venue_id", query: `ALTER TABLE game_proposals ADD COLUMN venue_id BIGINT NULL`},
+		{table: "game_proposals", index: "idx_venue", query: `ALTER TABLE game_proposals ADD INDEX idx_venue (venue_id)`},
+		{table: "games", column: "challenge_id", query: `ALTER TABLE games ADD COLUMN challenge_id BIGINT NULL`},
+		{table: "games", index: "idx_challenge", query: `ALTER TABLE games ADD INDEX idx_challenge (challenge_id)`},
+	}
+
+	for _, m := range migrations {
+		applied, err := m.applied()
+		if err != nil {
+			return err
+		}
+		if applied {
+			continue
+		}
+		if _, err := DB.Exec(m.query); err != nil {
+			return fmt.Errorf("failed to run migration %q: %w", m.query, err)
+		}
+	}
+
+	return nil
+}
+
+// seedSports inserts the default sports catalog. Existing rows are left untouched
+// so that changes made by admins survive restarts.
+func seedSports() error {
+	query := `
+		INSERT IGNORE INTO sports (slug, name, display_names, team_size, rating_system, skill_levels)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	defaultSkillLevels := `["beginner", "intermediate", "advanced"]`
+	sports := []struct {
+		slug, name, displayNames, teamSize, ratingSystem string
+	}{
+		{"basketball", "Basketball", `{"en": "Basketball", "fr": "Basketball"}`, "team", "elo"},
+		{"soccer", "Soccer", `{"en": "Soccer", "fr": "Soccer"}`, "team", "elo"},
+		{"volleyball", "Volleyball", `{"en": "Volleyball", "fr": "Volleyball"}`, "team", "elo"},
+		{"tennis", "Tennis", `{"en": "Tennis", "fr": "Tennis"}`, "singles", "ntrp"},
+		{"pickleball", "Pickleball", `{"en": "Pickleball", "fr": "Pickleball"}`, "doubles", "dupr"},
+		{"squash", "Squash", `{"en": "Squash", "fr": "Squash"}`, "singles", "elo"},
+		{"badminton", "Badminton", `{"en": "Badminton", "fr": "Badminton"}`, "singles", "elo"},
 	}
 
-	for _, migration := range migrations {
-		if _, err := DB.Exec(migration); err != nil {
-			// Ignore errors for existing columns/indexes, but log them
-			log.Printf("Migration warning (likely column/index already exists): %v", err)
+	for _, sport := range sports {
+		if _, err := DB.Exec(query, sport.slug, sport.name, sport.displayNames, sport.teamSize, sport.ratingSystem, defaultSkillLevels); err != nil {
+			return fmt.Errorf("failed to seed sport %s: %w", sport.slug, err)
 		}
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
\ No newline at end of file
@@ -0,0 +1,169 @@
+package geo
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// EarthRadiusKm is the mean Earth radius used for distances
+const EarthRadiusKm = 6371.0
+
+// MaxPrecision matches the geohash column length in the users table
+const MaxPrecision = 12
+
+// kmPerDegree is the length of one degree of latitude
+const kmPerDegree = math.Pi * EarthRadiusKm / 180
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Encode returns the geohash of a point. It matches MySQL's ST_GeoHash.
+func Encode(lat, lng float64, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+
+	var hash strings.Builder
+	bit, ch := 0, 0
+	even := true
+	for hash.Len() < precision {
+		if even {
+			mid := (minLng + maxLng) / 2
+			if lng >= mid {
+				ch |= 1 << (4 - bit)
+				minLng = mid
+			} else {
+				maxLng = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if lat >= mid {
+				ch |= 1 << (4 - bit)
+				minLat = mid
+			} else {
+				maxLat = mid
+			}
+		}
+		even = !even
+
+		if bit < 4 {
+			bit++
+		} else {
+			hash.WriteByte(base32[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return hash.String()
+}
+
+// CellSize returns the height and width in degrees of a geohash cell at the given precision
+func CellSize(precision int) (latDeg, lngDeg float64) {
+	bits := 5 * precision
+	lngBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / math.Exp2(float64(latBits)), 360 / math.Exp2(float64(lngBits))
+}
+
+// CoveringPrefixes returns geohash prefixes whose cells together contain every point within
+// radiusKm of the centre: the cell holding the centre and its eight neighbours, at the finest
+// precision whose cells are at least radiusKm across. It returns nil when the radius is too
+// large for prefixes to narrow the search.
+func CoveringPrefixes(lat, lng, radiusKm float64) []string {
+	// Cells are narrowest at the edge of the circle closest to a pole
+	edgeLat := math.Min(90, math.Abs(lat)+radiusKm/kmPerDegree)
+	lngKmPerDegree := kmPerDegree * math.Cos(edgeLat*math.Pi/180)
+
+	precision := 0
+	for p := MaxPrecision; p > 0; p-- {
+		latDeg, lngDeg := CellSize(p)
+		if latDeg*kmPerDegree >= radiusKm && lngDeg*lngKmPerDegree >= radiusKm {
+			precision = p
+			break
+		}
+	}
+	if precision == 0 {
+		return nil
+	}
+
+	latDeg, lngDeg := CellSize(precision)
+	seen := make(map[string]bool, 9)
+	var prefixes []string
+	for _, dLat := range []float64{-latDeg, 0, latDeg} {
+		neighbourLat := lat + dLat
+		if neighbourLat < -90 || neighbourLat > 90 {
+			continue
+		}
+		for _, dLng := range []float64{-lngDeg, 0, lngDeg} {
+			hash := Encode(neighbourLat, wrapLongitude(lng+dLng), precision)
+			if !seen[hash] {
+				seen[hash] = true
+				prefixes = append(prefixes, hash)
+			}
+		}
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+// BoundingBox returns the latitude and longitude ranges containing every point within radiusKm
+// of the centre. Longitudes span the whole globe when the box would cross a pole or the
+// antimeridian.
+func BoundingBox(lat, lng, radiusKm float64) (minLat, maxLat, minLng, maxLng float64) {
+	dLat := radiusKm / kmPerDegree
+	minLat, maxLat = math.Max(-90, lat-dLat), math.Min(90, lat+dLat)
+
+	edgeLat := math.Max(math.Abs(minLat), math.Abs(maxLat))
+	if edgeLat >= 90 {
+		return minLat, maxLat, -180, 180
+	}
+	dLng := dLat / math.Cos(edgeLat*math.Pi/180)
+	minLng, maxLng = lng-dLng, lng+dLng
+	if minLng < -180 || maxLng > 180 {
+		return minLat, maxLat, -180, 180
+	}
+	return minLat, maxLat, minLng, maxLng
+}
+
+// DistanceKm is the great-circle distance between two points
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLng := (lng2 - lng1) * math.Pi / 180
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// RoundDistance coarsens a distance before it is shown to other users, so exact positions
+// cannot be triangulated: whole kilometres, never less than 1.
+func RoundDistance(km float64) float64 {
+	return math.Max(1, math.Round(km))
+}
+
+// Region normalises a free-text location into a stable key, so that "San Francisco, CA" and
+// " san francisco,CA " fall in the same region. It returns "" when there is nothing to go on.
+func Region(location string) string {
+	var b strings.Builder
+	separator := false
+	for _, r := range strings.ToLower(location) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if separator && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			separator = false
+		} else {
+			separator = true
+		}
+	}
+	return b.String()
+}
+
+func wrapLongitude(lng float64) float64 {
+	if lng < -180 {
+		return lng + 360
+	}
+	if lng >= 180 {
+		return lng - 360
+	}
+	return lng
+}
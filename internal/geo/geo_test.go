@@ -0,0 +1,138 @@
+package geo
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	assert.Equal(t, "u4pruydqqvj", Encode(57.64911, 10.40744, 11))
+	assert.Equal(t, "9q8yy", Encode(37.7749, -122.4194, 5))
+	assert.Equal(t, "s0000", Encode(0, 0, 5))
+}
+
+func TestDistanceKm(t *testing.T) {
+	// San Francisco to Los Angeles
+	assert.InDelta(t, 559, DistanceKm(37.7749, -122.4194, 34.0522, -118.2437), 2)
+	assert.Equal(t, 0.0, DistanceKm(40, -74, 40, -74))
+}
+
+func TestRoundDistance(t *testing.T) {
+	assert.Equal(t, 1.0, RoundDistance(0.2))
+	assert.Equal(t, 3.0, RoundDistance(2.6))
+}
+
+func TestRegion(t *testing.T) {
+	assert.Equal(t, "san-francisco-ca", Region("San Francisco, CA"))
+	assert.Equal(t, "san-francisco-ca", Region("  san francisco,CA "))
+	assert.Equal(t, "münchen", Region("München"))
+	assert.Equal(t, "", Region(" , "))
+}
+
+func TestCoveringPrefixes_LargeRadius(t *testing.T) {
+	assert.Nil(t, CoveringPrefixes(0, 0, 10000))
+}
+
+// The prefilter must never drop a user that the exact distance check would keep
+func TestIndexMatchesFullScan(t *testing.T) {
+	index := newIndex(syntheticUsers(50000, 1))
+	centres := []struct{ lat, lng, radius float64 }{
+		{37.77, -122.42, 25},
+		{40.71, -74.0, 5},
+		{47.6, -122.3, 120},
+		{64.8, -147.7, 60},  // far north, narrow cells
+		{51.5, -179.95, 30}, // antimeridian
+	}
+	for _, c := range centres {
+		expected := index.fullScan(c.lat, c.lng, c.radius)
+		actual := index.search(c.lat, c.lng, c.radius)
+		sort.Ints(expected)
+		sort.Ints(actual)
+		require.Equal(t, expected, actual, "centre %v", c)
+	}
+}
+
+func BenchmarkRadiusSearch(b *testing.B) {
+	index := newIndex(syntheticUsers(300000, 2))
+	queries := syntheticUsers(1000, 3)
+
+	b.Run("full_scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			q := queries[i%len(queries)]
+			index.fullScan(q.lat, q.lng, 25)
+		}
+	})
+	b.Run("geohash", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			q := queries[i%len(queries)]
+			index.search(q.lat, q.lng, 25)
+		}
+	})
+}
+
+type point struct {
+	lat, lng float64
+	hash     string
+}
+
+// index mirrors the users table: rows sorted by geohash, as the idx_geohash B-tree keeps them
+type index []point
+
+func syntheticUsers(n int, seed int64) []point {
+	rng := rand.New(rand.NewSource(seed))
+	// Clustered around a handful of cities, like real users
+	cities := [][2]float64{{37.77, -122.42}, {40.71, -74.0}, {47.6, -122.3}, {64.8, -147.7}, {51.5, -179.9}, {-33.87, 151.21}}
+	points := make([]point, n)
+	for i := range points {
+		city := cities[rng.Intn(len(cities))]
+		lat := city[0] + rng.NormFloat64()*0.5
+		lng := wrapLongitude(city[1] + rng.NormFloat64()*0.5)
+		points[i] = point{lat: lat, lng: lng, hash: Encode(lat, lng, MaxPrecision)}
+	}
+	return points
+}
+
+func newIndex(points []point) index {
+	sort.Slice(points, func(i, j int) bool { return points[i].hash < points[j].hash })
+	return points
+}
+
+func (idx index) fullScan(lat, lng, radius float64) []int {
+	var matches []int
+	for i, p := range idx {
+		if DistanceKm(lat, lng, p.lat, p.lng) <= radius {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// search does what the swipe query does: prefix range scans, then the bounding box, then the
+// exact distance
+func (idx index) search(lat, lng, radius float64) []int {
+	prefixes := CoveringPrefixes(lat, lng, radius)
+	if prefixes == nil {
+		return idx.fullScan(lat, lng, radius)
+	}
+	minLat, maxLat, minLng, maxLng := BoundingBox(lat, lng, radius)
+
+	var matches []int
+	for _, prefix := range prefixes {
+		start := sort.Search(len(idx), func(i int) bool { return idx[i].hash >= prefix })
+		for i := start; i < len(idx) && strings.HasPrefix(idx[i].hash, prefix); i++ {
+			p := idx[i]
+			if p.lat < minLat || p.lat > maxLat || p.lng < minLng || p.lng > maxLng {
+				continue
+			}
+			if DistanceKm(lat, lng, p.lat, p.lng) <= radius {
+				matches = append(matches, i)
+			}
+		}
+	}
+	return matches
+}
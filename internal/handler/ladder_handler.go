@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"swipe-sports-backend/internal/auth"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/service"
+)
+
+type LadderHandler struct {
+	ladderService *service.LadderService
+}
+
+func NewLadderHandler() *LadderHandler {
+	return &LadderHandler{
+		ladderService: service.NewLadderService(),
+	}
+}
+
+// GET /ladders
+func (h *LadderHandler) GetLadders(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var query models.LadderQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ladders, err := h.ladderService.ListLadders(userID, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ladders": ladders})
+}
+
+// POST /ladders
+func (h *LadderHandler) CreateLadder(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.CreateLadderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ladder, err := h.ladderService.CreateLadder(userID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, ladder)
+}
+
+// GET /ladders/mine
+func (h *LadderHandler) GetMyLadders(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ladders, err := h.ladderService.GetMyLadders(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ladders": ladders})
+}
+
+// GET /ladders/:id
+func (h *LadderHandler) GetLadder(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ladderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ladder id"})
+		return
+	}
+
+	ladder, err := h.ladderService.GetLadder(userID, ladderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if ladder == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ladder not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ladder)
+}
+
+// POST /ladders/:id/join
+func (h *LadderHandler) Join(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ladderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ladder id"})
+		return
+	}
+
+	ladder, err := h.ladderService.Join(userID, ladderID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if ladder == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ladder not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ladder)
+}
+
+// POST /ladders/:id/leave
+func (h *LadderHandler) Leave(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ladderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ladder id"})
+		return
+	}
+
+	ladder, err := h.ladderService.Leave(userID, ladderID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if ladder == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ladder not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ladder)
+}
+
+// POST /ladders/:id/close
+func (h *LadderHandler) Close(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ladderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ladder id"})
+		return
+	}
+
+	ladder, err := h.ladderService.Close(userID, ladderID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if ladder == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ladder not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ladder)
+}
+
+// POST /ladders/:id/challenges
+func (h *LadderHandler) Challenge(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ladderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ladder id"})
+		return
+	}
+
+	var req models.CreateChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	challenge, err := h.ladderService.Challenge(userID, ladderID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if challenge == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ladder not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, challenge)
+}
+
+// POST /ladders/challenges/:id/accept
+func (h *LadderHandler) AcceptChallenge(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	challengeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid challenge id"})
+		return
+	}
+
+	challenge, err := h.ladderService.AcceptChallenge(userID, challengeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if challenge == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Challenge not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, challenge)
+}
+
+// POST /ladders/challenges/:id/forfeit
+func (h *LadderHandler) ForfeitChallenge(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	challengeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid challenge id"})
+		return
+	}
+
+	challenge, err := h.ladderService.ForfeitChallenge(userID, challengeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if challenge == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Challenge not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, challenge)
+}
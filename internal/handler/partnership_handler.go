@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"swipe-sports-backend/internal/auth"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/service"
+)
+
+type PartnershipHandler struct {
+	partnershipService *service.PartnershipService
+}
+
+func NewPartnershipHandler() *PartnershipHandler {
+	return &PartnershipHandler{
+		partnershipService: service.NewPartnershipService(),
+	}
+}
+
+// PUT /profile/partner-sports
+func (h *PartnershipHandler) SetPartnerSports(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.UpdatePartnerSportsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.partnershipService.SetPartnerSports(userID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"partner_sports": user.PartnerSports})
+}
+
+// POST /matches/:id/partnership
+func (h *PartnershipHandler) Propose(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	matchID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match id"})
+		return
+	}
+
+	var req models.ProposePartnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	partnership, err := h.partnershipService.Propose(userID, matchID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if partnership == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, partnership)
+}
+
+// GET /partnerships
+func (h *PartnershipHandler) GetPartnerships(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	partnerships, err := h.partnershipService.GetPartnerships(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"partnerships": partnerships})
+}
+
+// GET /partnerships/:id
+func (h *PartnershipHandler) GetPartnership(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	partnershipID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid partnership id"})
+		return
+	}
+
+	partnership, err := h.partnershipService.GetPartnership(userID, partnershipID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if partnership == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Partnership not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, partnership)
+}
+
+// POST /partnerships/:id/accept
+func (h *PartnershipHandler) AcceptPartnership(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	partnershipID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid partnership id"})
+		return
+	}
+
+	partnership, err := h.partnershipService.Accept(userID, partnershipID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if partnership == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Partnership not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, partnership)
+}
+
+// POST /partnerships/:id/decline
+func (h *PartnershipHandler) DeclinePartnership(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	partnershipID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid partnership id"})
+		return
+	}
+
+	partnership, err := h.partnershipService.Decline(userID, partnershipID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if partnership == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Partnership not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, partnership)
+}
+
+// DELETE /partnerships/:id
+func (h *PartnershipHandler) DissolvePartnership(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	partnershipID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid partnership id"})
+		return
+	}
+
+	partnership, err := h.partnershipService.Dissolve(userID, partnershipID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if partnership == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Partnership not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, partnership)
+}
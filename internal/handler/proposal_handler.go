@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"swipe-sports-backend/internal/auth"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/service"
+)
+
+type ProposalHandler struct {
+	proposalService *service.ProposalService
+}
+
+func NewProposalHandler() *ProposalHandler {
+	return &ProposalHandler{
+		proposalService: service.NewProposalService(),
+	}
+}
+
+// POST /matches/:id/proposals
+func (h *ProposalHandler) CreateProposal(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	matchID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match id"})
+		return
+	}
+
+	var req models.CreateProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	proposal, err := h.proposalService.CreateProposal(userID, matchID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if proposal == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, proposal)
+}
+
+// GET /matches/:id/proposals
+func (h *ProposalHandler) GetProposals(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	matchID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match id"})
+		return
+	}
+
+	proposals, err := h.proposalService.GetProposals(userID, matchID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if proposals == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"proposals": proposals})
+}
+
+// GET /matches/:id/venues
+func (h *ProposalHandler) GetVenueSuggestions(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	matchID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match id"})
+		return
+	}
+
+	venues, err := h.proposalService.SuggestVenues(userID, matchID, c.Query("sport"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if venues == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"venues": venues})
+}
+
+// POST /proposals/:id/accept
+func (h *ProposalHandler) AcceptProposal(c *gin.Context) {
+	h.respond(c, h.proposalService.AcceptProposal)
+}
+
+// POST /proposals/:id/decline
+func (h *ProposalHandler) DeclineProposal(c *gin.Context) {
+	h.respond(c, h.proposalService.DeclineProposal)
+}
+
+// POST /proposals/:id/counter
+func (h *ProposalHandler) CounterProposal(c *gin.Context) {
+	var req models.CreateProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.respond(c, func(userID, proposalID int64) (*models.GameProposal, error) {
+		return h.proposalService.CounterProposal(userID, proposalID, req)
+	})
+}
+
+// POST /proposals/:id/cancel
+func (h *ProposalHandler) CancelProposal(c *gin.Context) {
+	h.respond(c, h.proposalService.CancelProposal)
+}
+
+func (h *ProposalHandler) respond(c *gin.Context, action func(userID, proposalID int64) (*models.GameProposal, error)) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	proposalID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid proposal id"})
+		return
+	}
+
+	proposal, err := action(userID, proposalID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if proposal == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Proposal not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, proposal)
+}
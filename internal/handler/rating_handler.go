@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"swipe-sports-backend/internal/auth"
+	"swipe-sports-backend/internal/service"
+)
+
+type RatingHandler struct {
+	ratingService *service.RatingService
+}
+
+func NewRatingHandler() *RatingHandler {
+	return &RatingHandler{
+		ratingService: service.NewRatingService(),
+	}
+}
+
+// GET /profile/ratings
+func (h *RatingHandler) GetMyRatings(c *gin.Context) {
+	userID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ratings, err := h.ratingService.GetRatings(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ratings": ratings})
+}
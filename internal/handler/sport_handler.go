@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/service"
+)
+
+type SportHandler struct {
+	sportService *service.SportService
+}
+
+func NewSportHandler() *SportHandler {
+	return &SportHandler{
+		sportService: service.NewSportService(),
+	}
+}
+
+// GET /sports
+func (h *SportHandler) GetSports(c *gin.Context) {
+	sports, err := h.sportService.ListSports()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sports": sports})
+}
+
+// GET /sports/:slug
+func (h *SportHandler) GetSport(c *gin.Context) {
+	sport, err := h.sportService.GetSport(c.Param("slug"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if sport == nil || !sport.Active {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sport not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sport)
+}
+
+// GET /admin/sports
+func (h *SportHandler) GetAllSports(c *gin.Context) {
+	sports, err := h.sportService.ListAllSports()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sports": sports})
+}
+
+// POST /admin/sports
+func (h *SportHandler) CreateSport(c *gin.Context) {
+	var req models.CreateSportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sport, err := h.sportService.CreateSport(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sport)
+}
+
+// PUT /admin/sports/:slug
+func (h *SportHandler) UpdateSport(c *gin.Context) {
+	var req models.UpdateSportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sport, err := h.sportService.UpdateSport(c.Param("slug"), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if sport == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sport not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sport)
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -10,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/redis"
 	"swipe-sports-backend/internal/service"
 	"swipe-sports-backend/internal/repository"
 )
@@ -17,20 +20,22 @@ import (
 type WebSocketHandler struct {
 	messageService *service.MessageService
 	upgrader       websocket.Upgrader
-	clients        map[int64]map[*websocket.Conn]bool // userID -> connections
+	clients        map[int64]map[*websocket.Conn]int64 // userID -> connections -> matchID
 	mutex          sync.RWMutex
 }
 
 func NewWebSocketHandler() *WebSocketHandler {
-	return &WebSocketHandler{
+	h := &WebSocketHandler{
 		messageService: service.NewMessageService(),
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true // In production, check against allowed origins
 			},
 		},
-		clients: make(map[int64]map[*websocket.Conn]bool),
+		clients: make(map[int64]map[*websocket.Conn]int64),
 	}
+	go h.forwardUserEvents()
+	return h
 }
 
 // GET /ws/chat
@@ -83,7 +88,7 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	defer conn.Close()
 
 	// Register client
-	h.registerClient(userID, conn)
+	h.registerClient(userID, matchID, conn)
 	defer h.unregisterClient(userID, conn)
 
 	// Mark user as online
@@ -199,14 +204,14 @@ func (h *WebSocketHandler) handleTypingMessage(userID, matchID int64, wsMsg mode
 	})
 }
 
-func (h *WebSocketHandler) registerClient(userID int64, conn *websocket.Conn) {
+func (h *WebSocketHandler) registerClient(userID, matchID int64, conn *websocket.Conn) {
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
 
 	if h.clients[userID] == nil {
-		h.clients[userID] = make(map[*websocket.Conn]bool)
+		h.clients[userID] = make(map[*websocket.Conn]int64)
 	}
-	h.clients[userID][conn] = true
+	h.clients[userID][conn] = matchID
 
 	log.Printf("Client registered for user %d. Total connections: %d", userID, len(h.clients[userID]))
 }
@@ -226,22 +231,14 @@ func (h *WebSocketHandler) unregisterClient(userID int64, conn *websocket.Conn)
 }
 
 func (h *WebSocketHandler) broadcastToMatch(matchID int64, message models.WSMessage) {
-	// Get all users in the match
-	swipeRepo := repository.NewSwipeRepository()
-	match, err := swipeRepo.GetMatchByID(matchID)
+	// Get everyone currently in the match
+	groupRepo := repository.NewGroupRepository()
+	userIDs, err := groupRepo.GetActiveParticipantIDs(matchID)
 	if err != nil {
-		log.Printf("Failed to get match: %v", err)
-		return
-	}
-
-	if match == nil {
-		log.Printf("Match not found: %d", matchID)
+		log.Printf("Failed to get match participants: %v", err)
 		return
 	}
 
-	// Broadcast to both users in the match
-	userIDs := []int64{match.User1ID, match.User2ID}
-
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
 
@@ -278,6 +275,59 @@ func (h *WebSocketHandler) broadcastToUser(userID int64, message models.WSMessag
 	}
 }
 
+// forwardUserEvents delivers events published by the services (see redis.PublishUserEvent)
+// to the users connected to this server
+func (h *WebSocketHandler) forwardUserEvents() {
+	pubsub := redis.SubscribeUserEvents()
+	defer pubsub.Close()
+
+	for msg := range pubsub.Channel() {
+		var userID int64
+		if _, err := fmt.Sscanf(msg.Channel, redis.UserEventsChannel, &userID); err != nil {
+			log.Printf("Invalid user event channel %s: %v", msg.Channel, err)
+			continue
+		}
+
+		var event models.WSMessage
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			log.Printf("Invalid user event: %v", err)
+			continue
+		}
+
+		h.broadcastToUser(userID, event)
+
+		// A match that is gone, or a group the user is no longer in, can no longer be chatted in
+		if event.Type == models.WSMessageTypeMatchEnded || event.Type == models.WSMessageTypeMatchUndone ||
+			event.Type == models.WSMessageTypeGroupRemoved {
+			var ended struct {
+				Payload struct {
+					MatchID int64 `json:"match_id"`
+				} `json:"payload"`
+			}
+			if err := json.Unmarshal([]byte(msg.Payload), &ended); err == nil {
+				h.closeMatchConnections(userID, ended.Payload.MatchID)
+			}
+		}
+	}
+}
+
+// closeMatchConnections closes the user's chat connections for a match. Their read loops then
+// exit and unregister them.
+func (h *WebSocketHandler) closeMatchConnections(userID, matchID int64) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for conn, connMatchID := range h.clients[userID] {
+		if connMatchID != matchID {
+			continue
+		}
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, "match ended"),
+			time.Now().Add(time.Second))
+		conn.Close()
+	}
+}
+
 // Broadcast match notification
 func (h *WebSocketHandler) BroadcastMatch(matchID int64, user1ID, user2ID int64) {
 	matchMsg := models.WSMessage{
@@ -0,0 +1,250 @@
+// Package ical writes iCalendar (RFC 5545) calendars of timed events.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// ProductID identifies the calendars as ours to the apps importing them
+	ProductID = "-//Swipe Sports//Scheduled Games//EN"
+
+	// longest content line allowed before folding, in octets, excluding the CRLF
+	maxLineLength = 75
+
+	utcFormat   = "20060102T150405Z"
+	localFormat = "20060102T150405"
+)
+
+type EventStatus string
+
+const (
+	StatusConfirmed EventStatus = "CONFIRMED"
+	StatusCancelled EventStatus = "CANCELLED"
+)
+
+// Event is a VEVENT. Calendar apps match updates by UID and keep the copy with the highest
+// Sequence, so Sequence must grow whenever a published event changes.
+type Event struct {
+	UID         string
+	Sequence    int
+	Status      EventStatus
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	// Modified is when the event last changed; it is written as DTSTAMP and LAST-MODIFIED
+	Modified time.Time
+}
+
+// Calendar is a VCALENDAR. Event times are written in TimeZone, with the VTIMEZONE that
+// defines it, or in UTC when TimeZone is nil or UTC.
+type Calendar struct {
+	Name     string
+	TimeZone *time.Location
+	// RefreshInterval suggests how often subscribers should poll a feed; zero leaves it unset
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Encode renders the calendar as an iCalendar object
+func (c Calendar) Encode() []byte {
+	var w writer
+	zone := c.TimeZone
+	if zone == nil || zone.String() == "UTC" {
+		zone = nil
+	}
+
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + ProductID)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		w.line("NAME:" + escapeText(c.Name))
+		w.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	if zone != nil {
+		w.line("X-WR-TIMEZONE:" + zone.String())
+	}
+	if c.RefreshInterval > 0 {
+		w.line("REFRESH-INTERVAL;VALUE=DURATION:" + formatDuration(c.RefreshInterval))
+		w.line("X-PUBLISHED-TTL:" + formatDuration(c.RefreshInterval))
+	}
+
+	if zone != nil && len(c.Events) > 0 {
+		from, to := c.Events[0].Start, c.Events[0].End
+		for _, e := range c.Events[1:] {
+			if e.Start.Before(from) {
+				from = e.Start
+			}
+			if e.End.After(to) {
+				to = e.End
+			}
+		}
+		writeTimeZone(&w, zone, from, to)
+	}
+
+	for _, e := range c.Events {
+		writeEvent(&w, e, zone)
+	}
+
+	w.line("END:VCALENDAR")
+	return w.buf.Bytes()
+}
+
+func writeEvent(w *writer, e Event, zone *time.Location) {
+	status := e.Status
+	if status == "" {
+		status = StatusConfirmed
+	}
+
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + e.UID)
+	w.line("DTSTAMP:" + e.Modified.UTC().Format(utcFormat))
+	w.line("LAST-MODIFIED:" + e.Modified.UTC().Format(utcFormat))
+	w.line("SEQUENCE:" + fmt.Sprint(e.Sequence))
+	w.line("STATUS:" + string(status))
+	w.line("DTSTART" + formatTime(e.Start, zone))
+	w.line("DTEND" + formatTime(e.End, zone))
+	w.line("SUMMARY:" + escapeText(e.Summary))
+	if e.Location != "" {
+		w.line("LOCATION:" + escapeText(e.Location))
+	}
+	if e.Description != "" {
+		w.line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	if status == StatusCancelled {
+		w.line("TRANSP:TRANSPARENT")
+	}
+	w.line("END:VEVENT")
+}
+
+// writeTimeZone writes a VTIMEZONE listing each offset change of the zone between from and
+// to, starting with the observance in effect at from. The changes are spelled out rather than
+// summarised as RRULEs, which holds for zones whose rules changed over the years too.
+func writeTimeZone(w *writer, zone *time.Location, from, to time.Time) {
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + zone.String())
+
+	t := from.In(zone)
+	onset, _ := t.ZoneBounds()
+	for {
+		_, offsetTo := t.Zone()
+		offsetFrom := offsetTo
+		if !onset.IsZero() {
+			_, offsetFrom = onset.Add(-time.Second).In(zone).Zone()
+		} else {
+			onset = t
+		}
+		writeObservance(w, t, onset, offsetFrom, offsetTo)
+
+		_, next := t.ZoneBounds()
+		if next.IsZero() || next.After(to) {
+			break
+		}
+		t, onset = next.In(zone), next
+	}
+
+	w.line("END:VTIMEZONE")
+}
+
+func writeObservance(w *writer, t, onset time.Time, offsetFrom, offsetTo int) {
+	kind := "STANDARD"
+	if t.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	name, _ := t.Zone()
+
+	// The onset is given in the wall-clock time that was in effect just before it
+	wall := onset.UTC().Add(time.Duration(offsetFrom) * time.Second)
+
+	w.line("BEGIN:" + kind)
+	w.line("DTSTART:" + wall.Format(localFormat))
+	w.line("TZOFFSETFROM:" + formatOffset(offsetFrom))
+	w.line("TZOFFSETTO:" + formatOffset(offsetTo))
+	w.line("TZNAME:" + escapeText(name))
+	w.line("END:" + kind)
+}
+
+// formatTime returns the parameters and value of a DATE-TIME property, starting with the
+// separator after the property name
+func formatTime(t time.Time, zone *time.Location) string {
+	if zone == nil {
+		return ":" + t.UTC().Format(utcFormat)
+	}
+	return ";TZID=" + zone.String() + ":" + t.In(zone).Format(localFormat)
+}
+
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	offset := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+	if seconds%60 != 0 {
+		offset += fmt.Sprintf("%02d", seconds%60)
+	}
+	return offset
+}
+
+// formatDuration writes a positive duration as an RFC 5545 DURATION, to the second
+func formatDuration(d time.Duration) string {
+	seconds := int64(d / time.Second)
+	days := seconds / 86400
+	seconds %= 86400
+
+	out := "P"
+	if days > 0 {
+		out += fmt.Sprintf("%dD", days)
+	}
+	if seconds > 0 || days == 0 {
+		out += "T"
+		if h := seconds / 3600; h > 0 {
+			out += fmt.Sprintf("%dH", h)
+		}
+		if m := seconds % 3600 / 60; m > 0 {
+			out += fmt.Sprintf("%dM", m)
+		}
+		if s := seconds % 60; s > 0 || seconds == 0 {
+			out += fmt.Sprintf("%dS", s)
+		}
+	}
+	return out
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escapeText escapes a TEXT value
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// writer emits content lines, folding those longer than maxLineLength octets without
+// splitting a UTF-8 sequence
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) line(s string) {
+	limit := maxLineLength
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		// continuation lines lose one octet to the leading space
+		limit = maxLineLength - 1
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
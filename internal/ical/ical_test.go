@@ -0,0 +1,85 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode_UTC(t *testing.T) {
+	start := time.Date(2024, 7, 2, 18, 0, 0, 0, time.UTC)
+	cal := Calendar{
+		Name: "Games",
+		Events: []Event{{
+			UID:      "game-1@example.com",
+			Sequence: 2,
+			Status:   StatusCancelled,
+			Summary:  "Tennis; doubles, maybe",
+			Start:    start,
+			End:      start.Add(90 * time.Minute),
+			Modified: start.Add(-time.Hour),
+		}},
+	}
+
+	out := string(cal.Encode())
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.NotContains(t, out, "VTIMEZONE")
+	assert.Contains(t, out, "\r\nDTSTART:20240702T180000Z\r\n")
+	assert.Contains(t, out, "\r\nDTEND:20240702T193000Z\r\n")
+	assert.Contains(t, out, "\r\nDTSTAMP:20240702T170000Z\r\n")
+	assert.Contains(t, out, "\r\nSEQUENCE:2\r\n")
+	assert.Contains(t, out, "\r\nSTATUS:CANCELLED\r\n")
+	assert.Contains(t, out, `SUMMARY:Tennis\; doubles\, maybe`)
+}
+
+func TestEncode_TimeZoneAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	summer := time.Date(2024, 10, 20, 18, 0, 0, 0, berlin)
+	winter := time.Date(2024, 11, 3, 18, 0, 0, 0, berlin)
+	cal := Calendar{
+		TimeZone: berlin,
+		Events: []Event{
+			{UID: "a", Summary: "a", Start: summer, End: summer.Add(time.Hour), Modified: summer},
+			{UID: "b", Summary: "b", Start: winter, End: winter.Add(time.Hour), Modified: winter},
+		},
+	}
+
+	out := string(cal.Encode())
+
+	assert.Contains(t, out, "DTSTART;TZID=Europe/Berlin:20241020T180000")
+	assert.Contains(t, out, "DTSTART;TZID=Europe/Berlin:20241103T180000")
+
+	// The summer observance began at 02:00 CET in March; the switch back happened at 03:00 CEST
+	assert.Contains(t, out, "BEGIN:DAYLIGHT\r\nDTSTART:20240331T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nTZNAME:CEST\r\nEND:DAYLIGHT")
+	assert.Contains(t, out, "BEGIN:STANDARD\r\nDTSTART:20241027T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\nTZNAME:CET\r\nEND:STANDARD")
+	assert.Equal(t, 1, strings.Count(out, "BEGIN:VTIMEZONE"))
+	assert.Equal(t, 2, strings.Count(out, "TZOFFSETTO:"))
+}
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	start := time.Date(2024, 7, 2, 18, 0, 0, 0, time.UTC)
+	description := strings.Repeat("é", 100)
+	cal := Calendar{Events: []Event{{UID: "a", Summary: "a", Description: description, Start: start, End: start, Modified: start}}}
+
+	out := string(cal.Encode())
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineLength)
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, "DESCRIPTION:"+description+"\r\n")
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "PT1H", formatDuration(time.Hour))
+	assert.Equal(t, "PT1H30M", formatDuration(90*time.Minute))
+	assert.Equal(t, "P1D", formatDuration(24*time.Hour))
+	assert.Equal(t, "P1DT2H", formatDuration(26*time.Hour))
+}
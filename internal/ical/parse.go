@@ -0,0 +1,465 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxEvents bounds the work done on an uploaded calendar
+	maxEvents = 10000
+	// maxRecurrenceSteps bounds how many periods of a rule are walked before giving up
+	maxRecurrenceSteps = 100000
+)
+
+// Document is what Parse reads from an iCalendar object: its events and the calendar's
+// declared time zone, if any
+type Document struct {
+	TimeZone string
+	Events   []VEvent
+}
+
+// VEvent is a parsed event. A recurring event carries its rule and exceptions; an event with a
+// RecurrenceID overrides one instance of the recurring event with the same UID.
+type VEvent struct {
+	UID          string
+	RecurrenceID *time.Time
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Transparent  bool // does not block time, like a reminder or a birthday
+	Cancelled    bool
+	Rule         *Rule
+	ExDates      []time.Time
+	RDates       []time.Time
+
+	// set by a DURATION property, which finishEvent resolves against Start
+	duration    time.Duration
+	hasDuration bool
+}
+
+// Interval is a span of time [Start, End)
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// contentLine is one unfolded "NAME;PARAM=value:VALUE" line
+type contentLine struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the events of an iCalendar object. Floating times and dates are read in loc;
+// properties it does not understand are ignored.
+func Parse(data []byte, loc *time.Location) (*Document, error) {
+	lines, err := unfold(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{}
+	var stack []string
+	var event *VEvent
+	var calendars int
+	for _, raw := range lines {
+		line, err := parseContentLine(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		switch line.name {
+		case "BEGIN":
+			component := strings.ToUpper(line.value)
+			stack = append(stack, component)
+			if component == "VCALENDAR" {
+				calendars++
+			}
+			if component == "VEVENT" && len(stack) == 2 {
+				event = &VEvent{}
+			}
+			continue
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1] != strings.ToUpper(line.value) {
+				return nil, fmt.Errorf("unexpected END:%s", line.value)
+			}
+			if event != nil && len(stack) == 2 && stack[1] == "VEVENT" {
+				if err := finishEvent(event); err == nil {
+					doc.Events = append(doc.Events, *event)
+				}
+				event = nil
+				if len(doc.Events) > maxEvents {
+					return nil, fmt.Errorf("calendar has more than %d events", maxEvents)
+				}
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		switch {
+		case len(stack) == 1 && stack[0] == "VCALENDAR" && line.name == "X-WR-TIMEZONE":
+			doc.TimeZone = line.value
+		case event != nil && len(stack) == 2:
+			readEventProperty(event, line, loc)
+		}
+	}
+
+	if calendars == 0 {
+		return nil, fmt.Errorf("not an iCalendar file")
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("unterminated %s", stack[len(stack)-1])
+	}
+	return doc, nil
+}
+
+// readEventProperty applies one property to the event being read. Values that fail to parse
+// are skipped rather than failing the whole file.
+func readEventProperty(event *VEvent, line contentLine, loc *time.Location) {
+	switch line.name {
+	case "UID":
+		event.UID = line.value
+	case "DTSTART":
+		if t, allDay, err := parseTime(line.value, line.params, loc); err == nil {
+			event.Start, event.AllDay = t, allDay
+		}
+	case "DTEND":
+		if t, _, err := parseTime(line.value, line.params, loc); err == nil {
+			event.End = t
+		}
+	case "DURATION":
+		if d, err := parseDuration(line.value); err == nil {
+			event.duration, event.hasDuration = d, true
+		}
+	case "RECURRENCE-ID":
+		if t, _, err := parseTime(line.value, line.params, loc); err == nil {
+			event.RecurrenceID = &t
+		}
+	case "RRULE":
+		if rule, err := ParseRule(line.value, loc); err == nil {
+			event.Rule = rule
+		}
+	case "EXDATE":
+		event.ExDates = append(event.ExDates, parseTimeList(line.value, line.params, loc)...)
+	case "RDATE":
+		event.RDates = append(event.RDates, parseTimeList(line.value, line.params, loc)...)
+	case "TRANSP":
+		event.Transparent = strings.EqualFold(line.value, "TRANSPARENT")
+	case "STATUS":
+		event.Cancelled = strings.EqualFold(line.value, "CANCELLED")
+	}
+}
+
+// finishEvent fills in the end of an event read without DTEND
+func finishEvent(event *VEvent) error {
+	if event.Start.IsZero() {
+		return fmt.Errorf("event without DTSTART")
+	}
+
+	switch {
+	case event.hasDuration:
+		event.End = event.Start.Add(event.duration)
+	case event.End.IsZero() && event.AllDay:
+		event.End = event.Start.AddDate(0, 0, 1)
+	case event.End.Before(event.Start):
+		event.End = event.Start
+	}
+	return nil
+}
+
+// Busy returns the time the events block within [from, to): every opaque, non-cancelled
+// occurrence, with recurring events expanded and their exceptions and overridden instances
+// applied. Intervals are clipped to the window, sorted by start and may overlap.
+func (d *Document) Busy(from, to time.Time) []Interval {
+	overrides := make(map[string]map[int64]bool)
+	for _, e := range d.Events {
+		if e.RecurrenceID != nil && e.UID != "" {
+			if overrides[e.UID] == nil {
+				overrides[e.UID] = make(map[int64]bool)
+			}
+			overrides[e.UID][e.RecurrenceID.Unix()] = true
+		}
+	}
+
+	var busy []Interval
+	add := func(e VEvent, start time.Time) {
+		end := start.Add(e.End.Sub(e.Start))
+		if e.AllDay {
+			// all-day events span whole local days, whatever DST does in between
+			days := int(e.End.Sub(e.Start).Hours()/24 + 0.5)
+			end = start.AddDate(0, 0, days)
+		}
+		if !end.After(from) || !start.Before(to) || !end.After(start) {
+			return
+		}
+		busy = append(busy, Interval{Start: latest(start, from), End: earliest(end, to)})
+	}
+
+	for _, e := range d.Events {
+		if e.Transparent || e.Cancelled {
+			continue
+		}
+		if e.RecurrenceID != nil || (e.Rule == nil && len(e.RDates) == 0) {
+			add(e, e.Start)
+			continue
+		}
+
+		excluded := make(map[int64]bool, len(e.ExDates))
+		for _, t := range e.ExDates {
+			excluded[t.Unix()] = true
+		}
+		for k := range overrides[e.UID] {
+			excluded[k] = true
+		}
+
+		length := e.End.Sub(e.Start)
+		starts := e.RDates
+		if e.Rule != nil {
+			starts = append(e.Rule.Expand(e.Start, from.Add(-length-24*time.Hour), to), starts...)
+		} else {
+			starts = append([]time.Time{e.Start}, starts...)
+		}
+		for _, start := range starts {
+			if !excluded[start.Unix()] {
+				add(e, start)
+			}
+		}
+	}
+
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+	return busy
+}
+
+// unfold splits the data into content lines, joining folded continuation lines
+func unfold(data []byte) ([]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+func parseContentLine(raw string) (contentLine, error) {
+	// The value starts at the first colon outside a quoted parameter value
+	colon := -1
+	quoted := false
+	for i, r := range raw {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return contentLine{}, fmt.Errorf("invalid content line %q", truncate(raw, 40))
+	}
+
+	line := contentLine{value: raw[colon+1:], params: map[string]string{}}
+	parts := splitOutsideQuotes(raw[:colon], ';')
+	line.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		if eq := strings.IndexByte(param, '='); eq > 0 {
+			line.params[strings.ToUpper(param[:eq])] = strings.Trim(param[eq+1:], `"`)
+		}
+	}
+	return line, nil
+}
+
+func splitOutsideQuotes(s string, sep rune) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// parseTime reads a DATE or DATE-TIME value. UTC values end in Z, zoned ones carry a TZID
+// parameter and floating ones are read in loc, as are dates.
+func parseTime(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcFormat, value)
+		return t, false, err
+	}
+	if tzid, ok := params["TZID"]; ok {
+		loc = resolveZone(tzid, loc)
+	}
+	t, err := time.ParseInLocation(localFormat, value, loc)
+	return t, false, err
+}
+
+func parseTimeList(value string, params map[string]string, loc *time.Location) []time.Time {
+	var times []time.Time
+	for _, v := range strings.Split(value, ",") {
+		// PERIOD values are not supported
+		if strings.Contains(v, "/") {
+			continue
+		}
+		if t, _, err := parseTime(strings.TrimSpace(v), params, loc); err == nil {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
+// windowsZones maps the time zone names Outlook and Exchange write as TZIDs to IANA zones
+var windowsZones = map[string]string{
+	"Dateline Standard Time":          "Etc/GMT+12",
+	"Hawaiian Standard Time":          "Pacific/Honolulu",
+	"Alaskan Standard Time":           "America/Anchorage",
+	"Pacific Standard Time":           "America/Los_Angeles",
+	"Mountain Standard Time":          "America/Denver",
+	"US Mountain Standard Time":       "America/Phoenix",
+	"Central Standard Time":           "America/Chicago",
+	"Eastern Standard Time":           "America/New_York",
+	"Atlantic Standard Time":          "America/Halifax",
+	"Newfoundland Standard Time":      "America/St_Johns",
+	"E. South America Standard Time":  "America/Sao_Paulo",
+	"GMT Standard Time":               "Europe/London",
+	"Greenwich Standard Time":         "Atlantic/Reykjavik",
+	"W. Europe Standard Time":         "Europe/Berlin",
+	"Romance Standard Time":           "Europe/Paris",
+	"Central Europe Standard Time":    "Europe/Budapest",
+	"Central European Standard Time":  "Europe/Warsaw",
+	"E. Europe Standard Time":         "Europe/Chisinau",
+	"FLE Standard Time":               "Europe/Kiev",
+	"GTB Standard Time":               "Europe/Bucharest",
+	"Russian Standard Time":           "Europe/Moscow",
+	"South Africa Standard Time":      "Africa/Johannesburg",
+	"Arabian Standard Time":           "Asia/Dubai",
+	"India Standard Time":             "Asia/Kolkata",
+	"China Standard Time":             "Asia/Shanghai",
+	"Singapore Standard Time":         "Asia/Singapore",
+	"Tokyo Standard Time":             "Asia/Tokyo",
+	"AUS Eastern Standard Time":       "Australia/Sydney",
+	"New Zealand Standard Time":       "Pacific/Auckland",
+	"UTC":                             "UTC",
+	"Coordinated Universal Time":      "UTC",
+	"Mountain Standard Time (Mexico)": "America/Chihuahua",
+}
+
+// resolveZone finds the location named by a TZID: an IANA name, possibly behind a vendor
+// prefix like "/mozilla.org/20050126_1/", or a Windows zone name. Unknown zones fall back to
+// the default.
+func resolveZone(tzid string, fallback *time.Location) *time.Location {
+	candidates := []string{tzid}
+	if name, ok := windowsZones[tzid]; ok {
+		candidates = append(candidates, name)
+	}
+	// Vendor prefixes end just before the Area/City name
+	parts := strings.Split(strings.Trim(tzid, "/"), "/")
+	for i := len(parts) - 2; i >= 0; i-- {
+		candidates = append(candidates, strings.Join(parts[i:], "/"))
+	}
+
+	for _, name := range candidates {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return fallback
+}
+
+// parseDuration reads an RFC 5545 DURATION such as PT1H30M, P1D or -PT15M
+func parseDuration(value string) (time.Duration, error) {
+	s := value
+	sign := time.Duration(1)
+	if strings.HasPrefix(s, "-") {
+		sign, s = -1, s[1:]
+	}
+	s = strings.TrimPrefix(s, "+")
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	s = s[1:]
+
+	var total time.Duration
+	inTime := false
+	number := ""
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			number += string(r)
+		case r == 'T':
+			inTime = true
+		default:
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			number = ""
+			switch {
+			case r == 'W' && !inTime:
+				total += time.Duration(n) * 7 * 24 * time.Hour
+			case r == 'D' && !inTime:
+				total += time.Duration(n) * 24 * time.Hour
+			case r == 'H' && inTime:
+				total += time.Duration(n) * time.Hour
+			case r == 'M' && inTime:
+				total += time.Duration(n) * time.Minute
+			case r == 'S' && inTime:
+				total += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+		}
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return sign * total, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,121 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func calendar(lines ...string) []byte {
+	return []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n")
+}
+
+func TestParse_RecurrenceWithExceptions(t *testing.T) {
+	data := calendar(
+		"X-WR-TIMEZONE:Europe/Berlin",
+		"BEGIN:VEVENT",
+		"UID:standup",
+		"DTSTART;TZID=Europe/Berlin:20241021T090000",
+		"DTEND;TZID=Europe/Berlin:20241021T093000",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=6",
+		"EXDATE;TZID=Europe/Berlin:20241023T090000",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT10M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup",
+		"RECURRENCE-ID;TZID=Europe/Berlin:20241028T090000",
+		"DTSTART;TZID=Europe/Berlin:20241028T140000",
+		"DURATION:PT1H",
+		"END:VEVENT",
+	)
+
+	doc, err := Parse(data, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", doc.TimeZone)
+	require.Len(t, doc.Events, 2)
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	from := time.Date(2024, 10, 21, 0, 0, 0, 0, berlin)
+	busy := doc.Busy(from, from.AddDate(0, 0, 28))
+
+	var starts []string
+	for _, b := range busy {
+		starts = append(starts, b.Start.In(berlin).Format("Mon 02 15:04"))
+	}
+	// Six occurrences: Wed 23 excluded, Mon 28 moved to the afternoon (across the DST change)
+	assert.Equal(t, []string{"Mon 21 09:00", "Mon 28 14:00", "Wed 30 09:00", "Mon 04 09:00", "Wed 06 09:00"}, starts)
+	assert.Equal(t, time.Hour, busy[1].End.Sub(busy[1].Start))
+	assert.Equal(t, 8, busy[2].Start.UTC().Hour(), "wall-clock time kept after DST ends")
+}
+
+func TestParse_SkipsFreeAndCancelledEvents(t *testing.T) {
+	data := calendar(
+		"BEGIN:VEVENT", "UID:a", "DTSTART;VALUE=DATE:20240701", "TRANSP:TRANSPARENT", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:b", "DTSTART:20240701T100000Z", "DTEND:20240701T110000Z", "STATUS:CANCELLED", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:c", "DTSTART;VALUE=DATE:20240702", "END:VEVENT",
+	)
+
+	doc, err := Parse(data, time.UTC)
+	require.NoError(t, err)
+
+	busy := doc.Busy(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC))
+	require.Len(t, busy, 1)
+	assert.Equal(t, time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC), busy[0].Start)
+	assert.Equal(t, 24*time.Hour, busy[0].End.Sub(busy[0].Start))
+}
+
+func TestParse_Folding(t *testing.T) {
+	data := calendar(
+		"BEGIN:VEVENT",
+		"UID:a",
+		"DTSTART;TZID=\"/mozilla.org/20050126_1/America/New_Y",
+		" ork\":20240701T100000",
+		"END:VEVENT",
+	)
+
+	doc, err := Parse(data, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, doc.Events, 1)
+	assert.Equal(t, "America/New_York", doc.Events[0].Start.Location().String())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse([]byte("hello"), time.UTC)
+	assert.Error(t, err)
+
+	_, err = Parse([]byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n"), time.UTC)
+	assert.Error(t, err)
+}
+
+func TestRule_Monthly(t *testing.T) {
+	dtstart := time.Date(2024, 1, 26, 18, 0, 0, 0, time.UTC) // last Friday of January
+	rule, err := ParseRule("FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20240601T000000Z", time.UTC)
+	require.NoError(t, err)
+
+	starts := rule.Expand(dtstart, dtstart, dtstart.AddDate(1, 0, 0))
+	var days []int
+	for _, s := range starts {
+		days = append(days, s.Day())
+	}
+	assert.Equal(t, []int{26, 23, 29, 26, 31}, days)
+}
+
+func TestRule_SkipsShortMonths(t *testing.T) {
+	dtstart := time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+	rule, err := ParseRule("FREQ=MONTHLY;COUNT=3", time.UTC)
+	require.NoError(t, err)
+
+	starts := rule.Expand(dtstart, dtstart, dtstart.AddDate(1, 0, 0))
+	require.Len(t, starts, 3)
+	assert.Equal(t, time.May, starts[2].Month())
+}
+
+func TestRule_Unsupported(t *testing.T) {
+	_, err := ParseRule("FREQ=HOURLY", time.UTC)
+	assert.Error(t, err)
+}
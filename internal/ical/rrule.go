@@ -0,0 +1,336 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry: a weekday, optionally the Nth (or, negative, Nth from last) of
+// the month or year
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// Rule is an RRULE. Sub-daily frequencies and BYSETPOS, BYYEARDAY and BYWEEKNO are not
+// supported; ParseRule rejects the former and ignores the latter.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+}
+
+var icalWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// ParseRule reads an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20241231T235959Z".
+// A floating UNTIL is read in loc.
+func ParseRule(value string, loc *time.Location) (*Rule, error) {
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+	for _, part := range strings.Split(value, ";") {
+		eq := strings.IndexByte(part, '=')
+		if eq < 0 {
+			continue
+		}
+		key, val := strings.ToUpper(part[:eq]), part[eq+1:]
+
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(val))
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, _, err := parseTime(val, nil, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", val)
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, d := range strings.Split(val, ",") {
+				day, err := parseWeekdayNum(d)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(d)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", d)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, m := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(m)
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("invalid BYMONTH %q", m)
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(n))
+			}
+		case "WKST":
+			if wd, ok := icalWeekdays[strings.ToUpper(val)]; ok {
+				rule.WeekStart = wd
+			}
+		}
+	}
+
+	switch rule.Freq {
+	case Daily, Weekly, Monthly, Yearly:
+	default:
+		return nil, fmt.Errorf("unsupported FREQ %q", rule.Freq)
+	}
+	return rule, nil
+}
+
+func parseWeekdayNum(value string) (WeekdayNum, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+	weekday, ok := icalWeekdays[value[len(value)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+	n := 0
+	if prefix := value[:len(value)-2]; prefix != "" {
+		var err error
+		if n, err = strconv.Atoi(prefix); err != nil || n == 0 || n < -53 || n > 53 {
+			return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+		}
+	}
+	return WeekdayNum{N: n, Weekday: weekday}, nil
+}
+
+// Expand returns the start times of the occurrences beginning in [from, to), for a series
+// starting at dtstart. COUNT is counted from dtstart, which is always the first occurrence.
+// Occurrences keep dtstart's wall-clock time in its location, across DST changes.
+func (r *Rule) Expand(dtstart, from, to time.Time) []time.Time {
+	var starts []time.Time
+	count := 0
+	emit := func(t time.Time) bool {
+		if t.Before(dtstart) {
+			return true
+		}
+		if r.Until != nil && t.After(*r.Until) {
+			return false
+		}
+		if r.Count > 0 && count >= r.Count {
+			return false
+		}
+		if !t.Before(to) {
+			return false
+		}
+		count++
+		if !t.Before(from) {
+			starts = append(starts, t)
+		}
+		return true
+	}
+
+	if !emit(dtstart) {
+		return starts
+	}
+
+	for step := 0; step < maxRecurrenceSteps; step++ {
+		periodStart, candidates := r.period(dtstart, step)
+		// Whole periods past the window have nothing to add
+		if !periodStart.Before(to) {
+			break
+		}
+		for _, t := range candidates {
+			if t.Equal(dtstart) {
+				continue
+			}
+			if !emit(t) {
+				return starts
+			}
+		}
+	}
+	return starts
+}
+
+// period returns the first day of the step-th period after dtstart's, and the sorted
+// occurrence candidates within it
+func (r *Rule) period(dtstart time.Time, step int) (time.Time, []time.Time) {
+	loc := dtstart.Location()
+	hour, minute, second := dtstart.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, loc)
+	}
+
+	var start time.Time
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		day := at(dtstart.Year(), dtstart.Month(), dtstart.Day()+step*r.Interval)
+		start = day
+		if r.matchesMonth(day.Month()) && r.matchesMonthDay(day) && r.matchesWeekday(day) {
+			days = append(days, day)
+		}
+
+	case Weekly:
+		// The week containing dtstart, counted from WeekStart
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := at(dtstart.Year(), dtstart.Month(), dtstart.Day()-offset+7*step*r.Interval)
+		start = weekStart
+		weekdays := []time.Weekday{dtstart.Weekday()}
+		if len(r.ByDay) > 0 {
+			weekdays = weekdays[:0]
+			for _, d := range r.ByDay {
+				weekdays = append(weekdays, d.Weekday)
+			}
+		}
+		for _, wd := range weekdays {
+			day := at(weekStart.Year(), weekStart.Month(), weekStart.Day()+(int(wd)-int(r.WeekStart)+7)%7)
+			if r.matchesMonth(day.Month()) {
+				days = append(days, day)
+			}
+		}
+
+	case Monthly:
+		month := at(dtstart.Year(), dtstart.Month()+time.Month(step*r.Interval), 1)
+		start = month
+		if r.matchesMonth(month.Month()) {
+			days = r.daysInMonth(month.Year(), month.Month(), dtstart.Day(), at)
+		}
+
+	case Yearly:
+		year := dtstart.Year() + step*r.Interval
+		start = at(year, time.January, 1)
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{dtstart.Month()}
+		}
+		for _, m := range months {
+			days = append(days, r.daysInMonth(year, m, dtstart.Day(), at)...)
+		}
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return start, dedupe(days)
+}
+
+// daysInMonth applies BYMONTHDAY and BYDAY within one month; without either, the series
+// repeats on dtstart's day of the month, skipping months too short for it
+func (r *Rule) daysInMonth(year int, month time.Month, defaultDay int, at func(int, time.Month, int) time.Time) []time.Time {
+	length := at(year, month+1, 0).Day()
+
+	var days []time.Time
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, d := range r.ByMonthDay {
+			if d < 0 {
+				d = length + d + 1
+			}
+			if d < 1 || d > length {
+				continue
+			}
+			day := at(year, month, d)
+			if r.matchesWeekday(day) {
+				days = append(days, day)
+			}
+		}
+
+	case len(r.ByDay) > 0:
+		for _, wd := range r.ByDay {
+			var matches []time.Time
+			for d := 1; d <= length; d++ {
+				if day := at(year, month, d); day.Weekday() == wd.Weekday {
+					matches = append(matches, day)
+				}
+			}
+			switch {
+			case wd.N == 0:
+				days = append(days, matches...)
+			case wd.N > 0 && wd.N <= len(matches):
+				days = append(days, matches[wd.N-1])
+			case wd.N < 0 && -wd.N <= len(matches):
+				days = append(days, matches[len(matches)+wd.N])
+			}
+		}
+
+	case defaultDay <= length:
+		days = append(days, at(year, month, defaultDay))
+	}
+	return days
+}
+
+func (r *Rule) matchesMonth(m time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, month := range r.ByMonth {
+		if month == m {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	length := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	for _, d := range r.ByMonthDay {
+		if d == t.Day() || (d < 0 && length+d+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekday applies BYDAY as a filter, as it is when combined with BYMONTHDAY or in a
+// DAILY rule; ordinals are ignored there
+func (r *Rule) matchesWeekday(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Weekday == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func dedupe(times []time.Time) []time.Time {
+	out := times[:0]
+	for i, t := range times {
+		if i == 0 || !t.Equal(times[i-1]) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"swipe-sports-backend/internal/auth"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/service"
+)
+
+// RequireFeature only lets through users whose plan includes the feature. It must run after
+// auth.AuthMiddleware.
+func RequireFeature(feature models.Feature) gin.HandlerFunc {
+	entitlementService := service.NewEntitlementService()
+
+	return func(c *gin.Context) {
+		userID, exists := auth.GetUserIDFromContext(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		allowed, err := entitlementService.HasFeature(userID, feature)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Upgrade required", "feature": feature})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,207 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Availability is a recurring weekly schedule expressed in the user's IANA time zone.
+//
+// Stored as JSON:
+//
+//	{"time_zone": "America/Halifax", "windows": [{"day": "monday", "start": "18:00", "end": "20:00"}]}
+//
+// The legacy format {"monday": ["18:00", "20:00"]} (start/end pairs, UTC) is still accepted when reading.
+type Availability struct {
+	TimeZone string               `json:"time_zone"`
+	Windows  []AvailabilityWindow `json:"windows"`
+}
+
+// AvailabilityWindow is a free interval on a given weekday. Start and End are "HH:MM" in
+// local time; End may be "24:00" to run until midnight.
+type AvailabilityWindow struct {
+	Day   string `json:"day"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// TimeRange is a concrete interval in time
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+const maxAvailabilityWindows = 50
+
+// AvailabilityImport is a weekly availability inferred from an uploaded calendar. It is not
+// saved; the user reviews it and saves it through UpdateUserRequest.Availability.
+type AvailabilityImport struct {
+	Availability Availability `json:"availability"`
+	From         time.Time    `json:"from"`
+	To           time.Time    `json:"to"`
+	Events       int          `json:"events"`
+	Busy         []TimeRange  `json:"busy"`
+}
+
+// ImportAvailabilityRequest holds the optional form fields sent with the calendar file. The
+// time zone defaults to the user's, then the calendar's; the day bounds to 07:00 and 22:00.
+type ImportAvailabilityRequest struct {
+	TimeZone string `form:"time_zone"`
+	Weeks    int    `form:"weeks" binding:"omitempty,min=1,max=12"`
+	DayStart string `form:"day_start"`
+	DayEnd   string `form:"day_end"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// ParseWeekday converts a lowercase day name ("monday") to a time.Weekday
+func ParseWeekday(day string) (time.Weekday, bool) {
+	weekday, ok := weekdays[day]
+	return weekday, ok
+}
+
+// ParseClock parses "HH:MM" into minutes since midnight. "24:00" is allowed.
+func ParseClock(value string) (int, error) {
+	var hours, minutes int
+	if len(value) != 5 || value[2] != ':' {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", value)
+	}
+	if _, err := fmt.Sscanf(value, "%02d:%02d", &hours, &minutes); err != nil {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", value)
+	}
+	if minutes < 0 || minutes > 59 || hours < 0 || hours > 24 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return hours*60 + minutes, nil
+}
+
+// FormatClock formats minutes since midnight as "HH:MM"
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Location returns the availability's time zone, falling back to UTC when unset or unknown
+func (a Availability) Location() *time.Location {
+	if a.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(a.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Validate checks the time zone, days and clock times, and rejects overlapping windows on the same day
+func (a Availability) Validate() error {
+	if a.TimeZone == "" {
+		return fmt.Errorf("availability time_zone is required")
+	}
+	if _, err := time.LoadLocation(a.TimeZone); err != nil {
+		return fmt.Errorf("invalid availability time_zone %q", a.TimeZone)
+	}
+	if len(a.Windows) > maxAvailabilityWindows {
+		return fmt.Errorf("too many availability windows (max %d)", maxAvailabilityWindows)
+	}
+
+	type interval struct{ start, end int }
+	byDay := make(map[string][]interval)
+	for _, window := range a.Windows {
+		if _, ok := ParseWeekday(window.Day); !ok {
+			return fmt.Errorf("invalid availability day %q", window.Day)
+		}
+		start, err := ParseClock(window.Start)
+		if err != nil {
+			return err
+		}
+		end, err := ParseClock(window.End)
+		if err != nil {
+			return err
+		}
+		if start >= end {
+			return fmt.Errorf("availability window on %s must end after it starts", window.Day)
+		}
+		for _, other := range byDay[window.Day] {
+			if start < other.end && other.start < end {
+				return fmt.Errorf("availability windows on %s overlap", window.Day)
+			}
+		}
+		byDay[window.Day] = append(byDay[window.Day], interval{start, end})
+	}
+
+	return nil
+}
+
+func (a Availability) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+func (a *Availability) Scan(value interface{}) error {
+	if value == nil {
+		*a = Availability{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(bytes, a)
+}
+
+func (a *Availability) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*a = Availability{}
+		return nil
+	}
+
+	_, hasTimeZone := raw["time_zone"]
+	_, hasWindows := raw["windows"]
+	if hasTimeZone || hasWindows || len(raw) == 0 {
+		type availability Availability
+		var parsed availability
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return err
+		}
+		*a = Availability(parsed)
+		return nil
+	}
+
+	// Legacy format: {"monday": ["18:00", "20:00", ...]} with consecutive start/end pairs
+	legacy := Availability{TimeZone: "UTC"}
+	for day, value := range raw {
+		var times []string
+		if err := json.Unmarshal(value, &times); err != nil {
+			return fmt.Errorf("invalid availability for %s: %w", day, err)
+		}
+		for i := 0; i+1 < len(times); i += 2 {
+			legacy.Windows = append(legacy.Windows, AvailabilityWindow{Day: day, Start: times[i], End: times[i+1]})
+		}
+	}
+	sort.Slice(legacy.Windows, func(i, j int) bool {
+		dayI, _ := ParseWeekday(legacy.Windows[i].Day)
+		dayJ, _ := ParseWeekday(legacy.Windows[j].Day)
+		if dayI != dayJ {
+			return dayI < dayJ
+		}
+		return legacy.Windows[i].Start < legacy.Windows[j].Start
+	})
+	*a = legacy
+	return nil
+}
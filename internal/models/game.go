@@ -0,0 +1,148 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type GameStatus string
+
+const (
+	GameStatusPending   GameStatus = "pending"   // waiting for the opponent
+	GameStatusConfirmed GameStatus = "confirmed" // counts towards ratings
+	GameStatusDisputed  GameStatus = "disputed"  // waiting for an admin
+	GameStatusVoided    GameStatus = "voided"    // thrown out by an admin
+)
+
+// Game is a played game and its reported result
+type Game struct {
+	ID              int64             `json:"id" db:"id"`
+	MatchID         *int64            `json:"match_id" db:"match_id"`
+	ChallengeID     *int64            `json:"challenge_id" db:"challenge_id"` // the ladder challenge it decides
+	Sport           string            `json:"sport" db:"sport"`
+	PlayedAt        time.Time         `json:"played_at" db:"played_at"`
+	Score           GameScore         `json:"score" db:"score"`
+	WinnerTeam      *int              `json:"winner_team" db:"winner_team"` // nil for a draw
+	Status          GameStatus        `json:"status" db:"status"`
+	ReportedBy      int64             `json:"reported_by" db:"reported_by"`
+	ConfirmDeadline time.Time         `json:"confirm_deadline" db:"confirm_deadline"`
+	ConfirmedAt     *time.Time        `json:"confirmed_at" db:"confirmed_at"`
+	DisputedBy      *int64            `json:"disputed_by,omitempty" db:"disputed_by"`
+	DisputeReason   *string           `json:"dispute_reason,omitempty" db:"dispute_reason"`
+	ResolvedBy      *int64            `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolutionNote  *string           `json:"resolution_note,omitempty" db:"resolution_note"`
+	Participants    []GameParticipant `json:"participants"`
+	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// GameParticipant places a player on team 1 or 2
+type GameParticipant struct {
+	UserID int64 `json:"user_id" db:"user_id"`
+	Team   int   `json:"team" db:"team"`
+}
+
+// HasParticipant reports whether the user played in the game
+func (g *Game) HasParticipant(userID int64) bool {
+	return g.TeamOf(userID) != 0
+}
+
+// TeamOf returns the user's team, or 0 if they did not play
+func (g *Game) TeamOf(userID int64) int {
+	for _, p := range g.Participants {
+		if p.UserID == userID {
+			return p.Team
+		}
+	}
+	return 0
+}
+
+// GameScore is the score of each set (or the single final score) from each team's side
+type GameScore struct {
+	Sets []SetScore `json:"sets"`
+}
+
+type SetScore struct {
+	Team1 int `json:"team1"`
+	Team2 int `json:"team2"`
+}
+
+// Winner returns the team that won more sets, or nil for a draw
+func (s GameScore) Winner() *int {
+	var team1, team2 int
+	for _, set := range s.Sets {
+		switch {
+		case set.Team1 > set.Team2:
+			team1++
+		case set.Team2 > set.Team1:
+			team2++
+		}
+	}
+
+	var winner int
+	switch {
+	case team1 > team2:
+		winner = 1
+	case team2 > team1:
+		winner = 2
+	default:
+		return nil
+	}
+	return &winner
+}
+
+// Validate checks that the score has at least one set and no negative points
+func (s GameScore) Validate() error {
+	if len(s.Sets) == 0 {
+		return fmt.Errorf("score must have at least one set")
+	}
+	for _, set := range s.Sets {
+		if set.Team1 < 0 || set.Team2 < 0 {
+			return fmt.Errorf("scores cannot be negative")
+		}
+	}
+	return nil
+}
+
+func (s GameScore) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *GameScore) Scan(value interface{}) error {
+	if value == nil {
+		*s = GameScore{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(bytes, s)
+}
+
+// Report a game played with a match partner or within a group, or for an accepted ladder
+// challenge instead. Without participants the reporter is team 1 and the opponent team 2;
+// group and doubles games list all their players.
+type ReportGameRequest struct {
+	MatchID      int64             `json:"match_id" binding:"required_without=ChallengeID"`
+	ChallengeID  *int64            `json:"challenge_id"`
+	Sport        string            `json:"sport" binding:"required"`
+	PlayedAt     time.Time         `json:"played_at" binding:"required"`
+	Score        GameScore         `json:"score"`
+	Participants []GameParticipant `json:"participants"`
+}
+
+type DisputeGameRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// Admin decision on a disputed game; a corrected score replaces the reported one
+type ResolveGameRequest struct {
+	Outcome GameStatus `json:"outcome" binding:"required,oneof=confirmed voided"`
+	Score   *GameScore `json:"score"`
+	Note    *string    `json:"note"`
+}
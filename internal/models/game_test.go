@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sets(scores ...[2]int) GameScore {
+	score := GameScore{}
+	for _, s := range scores {
+		score.Sets = append(score.Sets, SetScore{Team1: s[0], Team2: s[1]})
+	}
+	return score
+}
+
+func TestGameScore_Winner(t *testing.T) {
+	tests := []struct {
+		name   string
+		score  GameScore
+		winner int // 0 for a draw
+	}{
+		{"single final score", sets([2]int{21, 15}), 1},
+		{"straight sets", sets([2]int{3, 6}, [2]int{4, 6}), 2},
+		{"deciding set", sets([2]int{6, 4}, [2]int{3, 6}, [2]int{7, 5}), 1},
+		{"more sets beats more points", sets([2]int{0, 6}, [2]int{7, 6}, [2]int{7, 6}), 1},
+		{"sets split", sets([2]int{6, 4}, [2]int{4, 6}), 0},
+		{"level set counts for neither", sets([2]int{2, 2}, [2]int{1, 3}), 2},
+		{"level final score", sets([2]int{1, 1}), 0},
+		{"no sets", GameScore{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			winner := tt.score.Winner()
+			if tt.winner == 0 {
+				assert.Nil(t, winner)
+				return
+			}
+			if assert.NotNil(t, winner) {
+				assert.Equal(t, tt.winner, *winner)
+			}
+		})
+	}
+}
+
+func TestGameScore_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		score GameScore
+		valid bool
+	}{
+		{"one set", sets([2]int{21, 19}), true},
+		{"several sets", sets([2]int{6, 4}, [2]int{6, 7}), true},
+		{"no points scored", sets([2]int{0, 0}), true},
+		{"no sets", GameScore{}, false},
+		{"empty sets", GameScore{Sets: []SetScore{}}, false},
+		{"negative team 1", sets([2]int{-1, 6}), false},
+		{"negative team 2 in a later set", sets([2]int{6, 4}, [2]int{6, -2}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.score.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
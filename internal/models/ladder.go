@@ -0,0 +1,123 @@
+package models
+
+import "time"
+
+type LadderStatus string
+
+const (
+	LadderStatusActive LadderStatus = "active"
+	LadderStatusClosed LadderStatus = "closed"
+)
+
+type ChallengeStatus string
+
+const (
+	ChallengeStatusPending   ChallengeStatus = "pending"   // waiting for the defender to accept
+	ChallengeStatusAccepted  ChallengeStatus = "accepted"  // waiting for the game
+	ChallengeStatusCompleted ChallengeStatus = "completed" // decided by a confirmed game
+	ChallengeStatusForfeited ChallengeStatus = "forfeited" // conceded, or not accepted in time
+	ChallengeStatusExpired   ChallengeStatus = "expired"   // accepted but never played
+	ChallengeStatusCancelled ChallengeStatus = "cancelled" // a player left or the ladder closed
+)
+
+// Ladder is a club league ranked by position: players climb by challenging and beating
+// someone at most ChallengeRange places above them
+type Ladder struct {
+	ID             int64        `json:"id" db:"id"`
+	OrganizerID    int64        `json:"organizer_id" db:"organizer_id"`
+	GroupID        *int64       `json:"group_id" db:"group_id"` // only the group's members can see and join it
+	Name           string       `json:"name" db:"name"`
+	Sport          string       `json:"sport" db:"sport"`
+	ChallengeRange int          `json:"challenge_range" db:"challenge_range"`
+	AcceptHours    int          `json:"accept_hours" db:"accept_hours"` // to accept a challenge before forfeiting it
+	PlayHours      int          `json:"play_hours" db:"play_hours"`     // to play an accepted challenge before it expires
+	Status         LadderStatus `json:"status" db:"status"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+
+	Players    int               `json:"players"`
+	Organizer  *UserProfile      `json:"organizer,omitempty"`
+	Standings  []LadderPlayer    `json:"standings,omitempty"`
+	Challenges []LadderChallenge `json:"challenges,omitempty"` // open ones first, then the latest results
+}
+
+// CanChallenge reports whether the player at position challenger may challenge the one at
+// position defender. Position 1 is the top of the ladder.
+func (l *Ladder) CanChallenge(challenger, defender int) bool {
+	return defender < challenger && challenger-defender <= l.ChallengeRange
+}
+
+// LadderPlayer is a player's place on a ladder
+type LadderPlayer struct {
+	LadderID int64     `json:"ladder_id" db:"ladder_id"`
+	UserID   int64     `json:"user_id" db:"user_id"`
+	Position int       `json:"position" db:"position"`
+	Wins     int       `json:"wins" db:"wins"`
+	Losses   int       `json:"losses" db:"losses"`
+	JoinedAt time.Time `json:"joined_at" db:"joined_at"`
+
+	User *UserProfile `json:"user,omitempty"`
+}
+
+// LadderChallenge is a challenge for the defender's place. If the challenger wins, by a
+// confirmed game or a forfeit, the two swap positions.
+type LadderChallenge struct {
+	ID             int64           `json:"id" db:"id"`
+	LadderID       int64           `json:"ladder_id" db:"ladder_id"`
+	ChallengerID   int64           `json:"challenger_id" db:"challenger_id"`
+	DefenderID     int64           `json:"defender_id" db:"defender_id"`
+	Status         ChallengeStatus `json:"status" db:"status"`
+	AcceptDeadline time.Time       `json:"accept_deadline" db:"accept_deadline"`
+	PlayDeadline   *time.Time      `json:"play_deadline" db:"play_deadline"` // set on acceptance
+	GameID         *int64          `json:"game_id" db:"game_id"`
+	WinnerID       *int64          `json:"winner_id" db:"winner_id"`
+	ForfeitedBy    *int64          `json:"forfeited_by,omitempty" db:"forfeited_by"`
+	ResolvedAt     *time.Time      `json:"resolved_at" db:"resolved_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+
+	Challenger *UserProfile `json:"challenger,omitempty"`
+	Defender   *UserProfile `json:"defender,omitempty"`
+}
+
+// Open reports whether the challenge is still to be decided
+func (c *LadderChallenge) Open() bool {
+	return c.Status == ChallengeStatusPending || c.Status == ChallengeStatusAccepted
+}
+
+// Other returns the other player in the challenge, or 0 if the user is not in it
+func (c *LadderChallenge) Other(userID int64) int64 {
+	switch userID {
+	case c.ChallengerID:
+		return c.DefenderID
+	case c.DefenderID:
+		return c.ChallengerID
+	}
+	return 0
+}
+
+// Ladder creation request; zero values take the defaults
+type CreateLadderRequest struct {
+	Name           string `json:"name" binding:"required,max=100"`
+	Sport          string `json:"sport" binding:"required"`
+	ChallengeRange int    `json:"challenge_range" binding:"omitempty,min=1,max=10"`
+	AcceptHours    int    `json:"accept_hours" binding:"omitempty,min=1,max=336"`
+	PlayHours      int    `json:"play_hours" binding:"omitempty,min=1,max=720"`
+	GroupID        *int64 `json:"group_id"`
+}
+
+// LadderQuery narrows the ladder list
+type LadderQuery struct {
+	Sport   string `form:"sport"`
+	GroupID *int64 `form:"group_id"` // a group's ladders instead of the public ones
+}
+
+type CreateChallengeRequest struct {
+	DefenderID int64 `json:"defender_id" binding:"required"`
+}
+
+// WSLadderMessage is pushed to everyone on the ladder whenever it changes
+type WSLadderMessage struct {
+	Ladder    *Ladder          `json:"ladder"`
+	Challenge *LadderChallenge `json:"challenge,omitempty"` // the challenge that changed, if any
+}
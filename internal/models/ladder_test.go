@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLadder_CanChallenge(t *testing.T) {
+	ladder := &Ladder{ChallengeRange: 3}
+
+	tests := []struct {
+		name       string
+		challenger int
+		defender   int
+		allowed    bool
+	}{
+		{"one place above", 5, 4, true},
+		{"at the edge of the range", 5, 2, true},
+		{"beyond the range", 5, 1, false},
+		{"for the top spot", 2, 1, true},
+		{"themselves", 3, 3, false},
+		{"one place below", 4, 5, false},
+		{"from the top", 1, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, ladder.CanChallenge(tt.challenger, tt.defender))
+		})
+	}
+}
+
+func TestLadder_CanChallenge_RangeOfOne(t *testing.T) {
+	ladder := &Ladder{ChallengeRange: 1}
+
+	assert.True(t, ladder.CanChallenge(2, 1))
+	assert.False(t, ladder.CanChallenge(3, 1))
+}
@@ -0,0 +1,41 @@
+package models
+
+// LeaderboardEntry is one ranked player
+type LeaderboardEntry struct {
+	Rank          int64   `json:"rank"` // 1-based
+	UserID        int64   `json:"user_id"`
+	Name          string  `json:"name"`
+	ProfilePicURL *string `json:"profile_pic_url"`
+	Rating        float64 `json:"rating"`
+}
+
+// Leaderboard is the top of a sport's ranking, globally or in one region
+type Leaderboard struct {
+	Sport   string             `json:"sport"`
+	Format  RatingFormat       `json:"format"`
+	Region  string             `json:"region,omitempty"`
+	Total   int64              `json:"total"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// LeaderboardStanding is where the user stands and the players ranked around them
+type LeaderboardStanding struct {
+	Sport  string             `json:"sport"`
+	Format RatingFormat       `json:"format"`
+	Region string             `json:"region,omitempty"`
+	Total  int64              `json:"total"`
+	Rank   *int64             `json:"rank"` // nil when the user is not ranked
+	Around []LeaderboardEntry `json:"around"`
+}
+
+type LeaderboardQuery struct {
+	Format RatingFormat `form:"format" binding:"omitempty,oneof=singles doubles team"`
+	Region string       `form:"region"` // a region key, or "me" for the user's own region
+	Limit  int          `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// RankedRating is a rating together with where its player lives
+type RankedRating struct {
+	SportRating
+	Location *string
+}
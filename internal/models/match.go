@@ -4,11 +4,116 @@ import (
 	"time"
 )
 
+// Match is a conversation. A direct match is the two players of a mutual like; a group is
+// created by a host and lists its players in Participants.
 type Match struct {
-	ID        int64     `json:"id" db:"id"`
-	User1ID   int64     `json:"user1_id" db:"user1_id"`
-	User2ID   int64     `json:"user2_id" db:"user2_id"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID        int64       `json:"id" db:"id"`
+	Kind      MatchKind   `json:"kind" db:"kind"`
+	User1ID   int64       `json:"user1_id" db:"user1_id"` // the creator of a group
+	User2ID   int64       `json:"user2_id" db:"user2_id"` // zero for a group
+	Title     *string     `json:"title,omitempty" db:"title"`
+	Sport     *string     `json:"sport,omitempty" db:"sport"`
+	Status    MatchStatus `json:"status" db:"status"`
+	EndedBy   *int64      `json:"ended_by,omitempty" db:"ended_by"`
+	EndReason *EndReason  `json:"end_reason,omitempty" db:"end_reason"`
+	EndedAt   *time.Time  `json:"ended_at,omitempty" db:"ended_at"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+
+	Participants []Participant `json:"participants,omitempty"`
+}
+
+// Partner returns the other player of a direct match, or 0 if the user is not one of its two
+// players or the match is a group
+func (m *Match) Partner(userID int64) int64 {
+	if m.Kind == MatchKindGroup {
+		return 0
+	}
+	switch userID {
+	case m.User1ID:
+		return m.User2ID
+	case m.User2ID:
+		return m.User1ID
+	}
+	return 0
+}
+
+// Host returns the active host of a group, or nil if it has none
+func (m *Match) Host() *Participant {
+	for i := range m.Participants {
+		if m.Participants[i].Role == ParticipantRoleHost && m.Participants[i].Status == ParticipantStatusActive {
+			return &m.Participants[i]
+		}
+	}
+	return nil
+}
+
+type MatchKind string
+
+const (
+	MatchKindDirect MatchKind = "direct"
+	MatchKindGroup  MatchKind = "group"
+)
+
+type MatchStatus string
+
+const (
+	MatchStatusActive MatchStatus = "active"
+	MatchStatusEnded  MatchStatus = "ended"
+)
+
+// EndReason is why a user ended a match
+type EndReason string
+
+const (
+	EndReasonNotInterested EndReason = "not_interested"
+	EndReasonNoResponse    EndReason = "no_response"
+	EndReasonInappropriate EndReason = "inappropriate"
+	EndReasonSpam          EndReason = "spam"
+	EndReasonOther         EndReason = "other"
+)
+
+type ParticipantRole string
+
+const (
+	ParticipantRoleHost   ParticipantRole = "host" // can invite and remove players
+	ParticipantRoleMember ParticipantRole = "member"
+)
+
+type ParticipantStatus string
+
+const (
+	ParticipantStatusActive  ParticipantStatus = "active"
+	ParticipantStatusLeft    ParticipantStatus = "left"
+	ParticipantStatusRemoved ParticipantStatus = "removed" // kicked by the host
+)
+
+// Participant is a player's membership of a match. Both players of a direct match are members.
+type Participant struct {
+	MatchID  int64             `json:"match_id" db:"match_id"`
+	UserID   int64             `json:"user_id" db:"user_id"`
+	Role     ParticipantRole   `json:"role" db:"role"`
+	Status   ParticipantStatus `json:"status" db:"status"`
+	JoinedAt time.Time         `json:"joined_at" db:"joined_at"`
+	LeftAt   *time.Time        `json:"left_at,omitempty" db:"left_at"`
+
+	User *UserProfile `json:"user,omitempty"`
+}
+
+// Group creation request. The creator becomes the host; the invited players must be among
+// their matches.
+type CreateGroupRequest struct {
+	Title   string  `json:"title" binding:"required,max=100"`
+	Sport   *string `json:"sport"`
+	UserIDs []int64 `json:"user_ids" binding:"required,min=1"`
+}
+
+type InviteToGroupRequest struct {
+	UserIDs []int64 `json:"user_ids" binding:"required,min=1"`
+}
+
+// Unmatch request; the reason is optional
+type UnmatchRequest struct {
+	Reason EndReason `json:"reason" binding:"omitempty,oneof=not_interested no_response inappropriate spam other"`
 }
 
 // Match with user details
@@ -21,7 +126,70 @@ type MatchWithUsers struct {
 
 // Match response for API
 type MatchResponse struct {
-	ID        int64       `json:"id"`
-	User      UserProfile `json:"user"`
-	CreatedAt time.Time   `json:"created_at"`
-} 
\ No newline at end of file
+	ID                  int64       `json:"id"`
+	User                UserProfile `json:"user"`
+	AvailabilityOverlap []TimeRange `json:"availability_overlap"`
+	CreatedAt           time.Time   `json:"created_at"`
+}
+
+type Swipe struct {
+	ID        int64          `json:"id" db:"id"`
+	SwiperID  int64          `json:"swiper_id" db:"swiper_id"`
+	SwipeeID  int64          `json:"swipee_id" db:"swipee_id"`
+	Direction SwipeDirection `json:"direction" db:"direction"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+type SwipeDirection string
+
+const (
+	SwipeDirectionLeft  SwipeDirection = "left"
+	SwipeDirectionRight SwipeDirection = "right"
+	SwipeDirectionSuper SwipeDirection = "super" // a right swipe that jumps the queue
+)
+
+// IsLike reports whether the swipe counts towards a match
+func (d SwipeDirection) IsLike() bool {
+	return d == SwipeDirectionRight || d == SwipeDirectionSuper
+}
+
+// Swipe request
+type SwipeRequest struct {
+	SwipeeID  int64          `json:"swipee_id" binding:"required"`
+	Direction SwipeDirection `json:"direction" binding:"required,oneof=left right super"`
+}
+
+// Swipe response; Match is set when the swipe completed a mutual like
+type SwipeResponse struct {
+	Matched             bool           `json:"matched"`
+	Match               *MatchResponse `json:"match,omitempty"`
+	LikesRemaining      *int           `json:"likes_remaining,omitempty"`
+	SuperLikesRemaining *int           `json:"super_likes_remaining,omitempty"`
+}
+
+// Undo swipe response; the profile is returned so the client can put it back on the deck
+type UndoSwipeResponse struct {
+	Swipe            Swipe        `json:"swipe"`
+	Profile          *UserProfile `json:"profile"`
+	MatchRemoved     bool         `json:"match_removed"`
+	RewindsRemaining *int         `json:"rewinds_remaining,omitempty"`
+}
+
+// ReceivedLike is someone who liked the user and is still waiting for an answer
+type ReceivedLike struct {
+	Profile   UserProfile `json:"profile"`
+	SuperLike bool        `json:"super_like"`
+	LikedAt   time.Time   `json:"liked_at"`
+}
+
+// ReceivedLikesPage is one page of the likes inbox. NextCursor is empty on the last page.
+type ReceivedLikesPage struct {
+	Likes      []ReceivedLike `json:"likes"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// Query for the likes inbox
+type ReceivedLikesQuery struct {
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+}
@@ -0,0 +1,117 @@
+package models
+
+import "time"
+
+type MessageType string
+
+const (
+	MessageTypeText  MessageType = "text"
+	MessageTypeImage MessageType = "image"
+	MessageTypeAudio MessageType = "audio"
+	// MessageTypeProposal marks a game proposal or a response to one in the chat timeline
+	MessageTypeProposal MessageType = "proposal"
+)
+
+type Message struct {
+	ID          int64       `json:"id" db:"id"`
+	MatchID     int64       `json:"match_id" db:"match_id"`
+	SenderID    int64       `json:"sender_id" db:"sender_id"`
+	Content     string      `json:"content" db:"content"`
+	MessageType MessageType `json:"message_type" db:"message_type"`
+	MediaURL    *string     `json:"media_url" db:"media_url"`
+	ProposalID  *int64      `json:"proposal_id,omitempty" db:"proposal_id"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+}
+
+type CreateMessageRequest struct {
+	MatchID     int64       `json:"match_id" binding:"required"`
+	Content     string      `json:"content" binding:"required"`
+	MessageType MessageType `json:"message_type"`
+	MediaURL    *string     `json:"media_url"`
+}
+
+// WebSocket message types
+type WSMessageType string
+
+const (
+	WSMessageTypeChat        WSMessageType = "chat"
+	WSMessageTypeTyping      WSMessageType = "typing"
+	WSMessageTypeMatch       WSMessageType = "match"
+	WSMessageTypeMatchUndone WSMessageType = "match_undone"
+	WSMessageTypeSuperLike   WSMessageType = "super_like"
+	WSMessageTypeMatchEnded  WSMessageType = "match_ended"
+	WSMessageTypeGameResult  WSMessageType = "game_result"
+
+	WSMessageTypeProposal          WSMessageType = "game_proposal"
+	WSMessageTypeProposalAccepted  WSMessageType = "game_proposal_accepted"
+	WSMessageTypeProposalDeclined  WSMessageType = "game_proposal_declined"
+	WSMessageTypeProposalCountered WSMessageType = "game_proposal_countered"
+	WSMessageTypeProposalCancelled WSMessageType = "game_proposal_cancelled"
+
+	WSMessageTypeJoinRequest        WSMessageType = "open_game_join_request"
+	WSMessageTypeJoinRequestUpdated WSMessageType = "open_game_join_request_updated"
+	WSMessageTypeOpenGameCancelled  WSMessageType = "open_game_cancelled"
+	WSMessageTypeOpenGameTeams      WSMessageType = "open_game_teams"
+
+	WSMessageTypeGroupUpdated WSMessageType = "group_updated"
+	WSMessageTypeGroupRemoved WSMessageType = "group_removed" // sent to a player who left or was removed
+
+	WSMessageTypePartnershipProposed WSMessageType = "partnership_proposed"
+	WSMessageTypePartnershipUpdated  WSMessageType = "partnership_updated"
+
+	WSMessageTypeTournamentUpdated WSMessageType = "tournament_updated"
+
+	WSMessageTypeLadderUpdated WSMessageType = "ladder_updated"
+)
+
+type WSMessage struct {
+	Type    WSMessageType `json:"type"`
+	Payload interface{}   `json:"payload"`
+}
+
+type WSChatMessage struct {
+	MatchID     int64       `json:"match_id"`
+	SenderID    int64       `json:"sender_id"`
+	Content     string      `json:"content"`
+	MessageType MessageType `json:"message_type"`
+	MediaURL    *string     `json:"media_url"`
+	Timestamp   time.Time   `json:"timestamp"`
+}
+
+type WSTypingMessage struct {
+	MatchID  int64 `json:"match_id"`
+	UserID   int64 `json:"user_id"`
+	IsTyping bool  `json:"is_typing"`
+}
+
+// WSSuperLikeMessage tells a user that someone super-liked them
+type WSSuperLikeMessage struct {
+	From UserProfile `json:"from"`
+}
+
+// WSMatchEndedMessage tells both users that a match was ended. The reason is kept private.
+type WSMatchEndedMessage struct {
+	MatchID int64 `json:"match_id"`
+	EndedBy int64 `json:"ended_by"`
+}
+
+// WSGroupMessage tells a group's players who joined (change "active"), left or was removed.
+// Group is the group as it now stands and is left out for the players who are gone.
+type WSGroupMessage struct {
+	MatchID int64             `json:"match_id"`
+	UserIDs []int64           `json:"user_ids"`
+	Change  ParticipantStatus `json:"change"`
+	Group   *Match            `json:"group,omitempty"`
+}
+
+// WSMatchUndoneMessage tells a user that a match was withdrawn by a rewind
+type WSMatchUndoneMessage struct {
+	MatchID int64 `json:"match_id"`
+	UserID  int64 `json:"user_id"`
+}
+
+// WSProposalMessage carries a proposal action along with the chat message that records it
+type WSProposalMessage struct {
+	Proposal GameProposal `json:"proposal"`
+	Message  Message      `json:"message"`
+}
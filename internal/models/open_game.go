@@ -0,0 +1,171 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+type OpenGameStatus string
+
+const (
+	OpenGameStatusOpen      OpenGameStatus = "open"
+	OpenGameStatusCancelled OpenGameStatus = "cancelled"
+)
+
+type JoinRequestStatus string
+
+const (
+	JoinRequestPending    JoinRequestStatus = "pending"
+	JoinRequestApproved   JoinRequestStatus = "approved"   // has a spot
+	JoinRequestWaitlisted JoinRequestStatus = "waitlisted" // approved, waiting for a spot to open
+	JoinRequestRejected   JoinRequestStatus = "rejected"
+	JoinRequestWithdrawn  JoinRequestStatus = "withdrawn" // left the game or took the request back
+)
+
+// OpenGame is a game posted on the board by a host looking for more players
+type OpenGame struct {
+	ID              int64          `json:"id" db:"id"`
+	HostID          int64          `json:"host_id" db:"host_id"`
+	Sport           string         `json:"sport" db:"sport"`
+	StartsAt        time.Time      `json:"starts_at" db:"starts_at"`
+	DurationMinutes int            `json:"duration_minutes" db:"duration_minutes"`
+	VenueID         *int64         `json:"venue_id" db:"venue_id"`
+	Venue           *string        `json:"venue" db:"venue"`
+	Latitude        float64        `json:"latitude" db:"latitude"`
+	Longitude       float64        `json:"longitude" db:"longitude"`
+	SkillLevels     StringList     `json:"skill_levels" db:"skill_levels"` // empty means any level
+	Spots           int            `json:"spots" db:"spots"`               // players wanted besides the host
+	Note            *string        `json:"note" db:"note"`
+	Status          OpenGameStatus `json:"status" db:"status"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+
+	// Filled and Waitlisted count approved and waitlisted requests
+	Filled     int          `json:"filled"`
+	Waitlisted int          `json:"waitlisted"`
+	Host       *UserProfile `json:"host,omitempty"`
+	DistanceKm *float64     `json:"distance_km,omitempty"`
+	// MyRequest is the viewer's own request, if any
+	MyRequest *JoinRequest `json:"my_request,omitempty"`
+	// Players are the approved players; Requests, seen by the host only, are all the others
+	Players  []JoinRequest `json:"players,omitempty"`
+	Requests []JoinRequest `json:"requests,omitempty"`
+}
+
+// SpotsLeft is how many players can still be approved without going to the waitlist
+func (g *OpenGame) SpotsLeft() int {
+	if g.Filled >= g.Spots {
+		return 0
+	}
+	return g.Spots - g.Filled
+}
+
+// EndsAt is when the game finishes
+func (g *OpenGame) EndsAt() time.Time {
+	return g.StartsAt.Add(time.Duration(g.DurationMinutes) * time.Minute)
+}
+
+// JoinRequest is a player's request for a spot in an open game
+type JoinRequest struct {
+	ID          int64             `json:"id" db:"id"`
+	GameID      int64             `json:"game_id" db:"game_id"`
+	UserID      int64             `json:"user_id" db:"user_id"`
+	Status      JoinRequestStatus `json:"status" db:"status"`
+	Message     *string           `json:"message" db:"message"`
+	RespondedAt *time.Time        `json:"responded_at" db:"responded_at"` // waitlist order
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+
+	User *UserProfile `json:"user,omitempty"`
+}
+
+// Open game creation request. The location is the directory venue's when VenueID is set,
+// otherwise the given coordinates, otherwise the host's own.
+type CreateOpenGameRequest struct {
+	Sport           string     `json:"sport" binding:"required"`
+	StartsAt        time.Time  `json:"starts_at" binding:"required"`
+	DurationMinutes int        `json:"duration_minutes" binding:"required,min=15,max=480"`
+	VenueID         *int64     `json:"venue_id"`
+	Venue           *string    `json:"venue" binding:"omitempty,max=255"`
+	Latitude        *float64   `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude       *float64   `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	SkillLevels     StringList `json:"skill_levels"`
+	Spots           int        `json:"spots" binding:"required,min=1,max=30"`
+	Note            *string    `json:"note" binding:"omitempty,max=500"`
+}
+
+type JoinOpenGameRequest struct {
+	Message *string `json:"message" binding:"omitempty,max=500"`
+}
+
+// OpenGameFeedQuery narrows the board on top of the viewer's discovery filter
+type OpenGameFeedQuery struct {
+	ProfileFilter
+	From *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// WSOpenGameMessage is pushed to the host and players when requests or the game change
+type WSOpenGameMessage struct {
+	Game    *OpenGame    `json:"game"`
+	Request *JoinRequest `json:"request,omitempty"`
+}
+
+// TeamAssignment lists the user IDs of each team, stored as JSON
+type TeamAssignment [][]int64
+
+func (a TeamAssignment) Value() (driver.Value, error) {
+	if a == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(a)
+}
+
+func (a *TeamAssignment) Scan(value interface{}) error {
+	if value == nil {
+		*a = TeamAssignment{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(bytes, a)
+}
+
+// TeamSplit is how an open game's roster (the host and approved players) is divided into teams
+type TeamSplit struct {
+	GameID     int64          `json:"game_id" db:"game_id"`
+	Assignment TeamAssignment `json:"-" db:"teams"`
+	Manual     bool           `json:"manual" db:"manual"` // adjusted by the host rather than generated
+	UpdatedAt  time.Time      `json:"updated_at" db:"updated_at"`
+
+	Teams []GameTeam `json:"teams"`
+	// Spread is the rating gap between the strongest and the weakest team
+	Spread float64 `json:"spread"`
+	// Unassigned are players approved since the teams were picked
+	Unassigned []UserProfile `json:"unassigned"`
+}
+
+type GameTeam struct {
+	Number  int           `json:"number"`
+	Players []UserProfile `json:"players"`
+	Rating  float64       `json:"rating"`
+	// WinProbability is the chance of beating each of the other teams, on average
+	WinProbability float64 `json:"win_probability"`
+}
+
+// Team generation request. Each KeepTogether entry is a set of players to put on the same team.
+type GenerateTeamsRequest struct {
+	Teams         int       `json:"teams" binding:"required,min=2,max=8"`
+	KeepTogether  [][]int64 `json:"keep_together"`
+	BalanceGender bool      `json:"balance_gender"`
+}
+
+// AdjustTeamsRequest sets the teams by hand; every player on the roster must be on one team
+type AdjustTeamsRequest struct {
+	Teams TeamAssignment `json:"teams" binding:"required,min=2"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// PartnershipStatus tracks a doubles partnership from proposal to break-up
+type PartnershipStatus string
+
+const (
+	PartnershipStatusPending   PartnershipStatus = "pending"
+	PartnershipStatusActive    PartnershipStatus = "active"
+	PartnershipStatusDeclined  PartnershipStatus = "declined"
+	PartnershipStatusDissolved PartnershipStatus = "dissolved"
+)
+
+// Partnership is a doubles pair in one sport. It carries its own Glicko-2 rating, earned in
+// doubles games the two play on the same team; it starts from the partners' combined ratings.
+// User1ID is always the lower of the two user IDs.
+type Partnership struct {
+	ID           int64             `json:"id" db:"id"`
+	Sport        string            `json:"sport" db:"sport"`
+	User1ID      int64             `json:"user1_id" db:"user1_id"`
+	User2ID      int64             `json:"user2_id" db:"user2_id"`
+	ProposedBy   int64             `json:"proposed_by" db:"proposed_by"`
+	Status       PartnershipStatus `json:"status" db:"status"`
+	Rating       float64           `json:"rating" db:"rating"`
+	Deviation    float64           `json:"deviation" db:"deviation"`
+	Volatility   float64           `json:"-" db:"volatility"`
+	Confidence   float64           `json:"confidence"`  // 0 (unknown) to 1 (certain)
+	Provisional  bool              `json:"provisional"` // too few games to rank
+	GamesPlayed  int               `json:"games_played" db:"games_played"`
+	LastPlayedAt *time.Time        `json:"last_played_at" db:"last_played_at"`
+	FormedAt     *time.Time        `json:"formed_at" db:"formed_at"`
+	EndedAt      *time.Time        `json:"ended_at" db:"ended_at"`
+	CreatedAt    time.Time         `json:"created_at" db:"created_at"`
+	Partner      *UserProfile      `json:"partner,omitempty"` // the other player, from the viewer's side
+}
+
+// Other returns the partner of the given user, or 0 if the user is not in the partnership
+func (p *Partnership) Other(userID int64) int64 {
+	switch userID {
+	case p.User1ID:
+		return p.User2ID
+	case p.User2ID:
+		return p.User1ID
+	default:
+		return 0
+	}
+}
+
+// ActiveAt reports whether the two were partners at the given time. Only the latest time they
+// were is known.
+func (p *Partnership) ActiveAt(t time.Time) bool {
+	return p.FormedAt != nil && !t.Before(*p.FormedAt) && (p.EndedAt == nil || t.Before(*p.EndedAt))
+}
+
+// Partnership proposal, sent from a one-to-one match
+type ProposePartnershipRequest struct {
+	Sport string `json:"sport" binding:"required"`
+}
+
+// Partner sports update request (replaces the stored list)
+type UpdatePartnerSportsRequest struct {
+	Sports StringList `json:"sports"`
+}
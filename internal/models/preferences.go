@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+)
+
+// DiscoveryPreferences are the stored swipe deck settings of a user. Empty lists and nil
+// bounds mean "no restriction". They are also used in reverse: a candidate is only shown
+// if the viewer would pass the candidate's own preferences.
+type DiscoveryPreferences struct {
+	UserID        int64      `json:"user_id" db:"user_id"`
+	MinAge        *int       `json:"min_age" db:"min_age"`
+	MaxAge        *int       `json:"max_age" db:"max_age"`
+	MaxDistanceKm *float64   `json:"max_distance_km" db:"max_distance_km"`
+	Genders       StringList `json:"genders" db:"genders"`
+	Sports        StringList `json:"sports" db:"sports"`
+	SkillLevels   StringList `json:"skill_levels" db:"skill_levels"`
+	MinRating     *int       `json:"min_rating" db:"min_rating"`
+	MaxRating     *int       `json:"max_rating" db:"max_rating"`
+	PlayStyles    StringList `json:"play_styles" db:"play_styles"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Discovery preferences update request (replaces the stored settings)
+type UpdateDiscoveryPreferencesRequest struct {
+	MinAge        *int       `json:"min_age" binding:"omitempty,min=13,max=100"`
+	MaxAge        *int       `json:"max_age" binding:"omitempty,min=13,max=100"`
+	MaxDistanceKm *float64   `json:"max_distance_km" binding:"omitempty,gt=0,max=20000"`
+	Genders       StringList `json:"genders" binding:"omitempty,dive,oneof=male female other"`
+	Sports        StringList `json:"sports"`
+	SkillLevels   StringList `json:"skill_levels"`
+	MinRating     *int       `json:"min_rating" binding:"omitempty,min=0"`
+	MaxRating     *int       `json:"max_rating" binding:"omitempty,min=0"`
+	PlayStyles    StringList `json:"play_styles"`
+}
+
+// WithDefaults fills every criterion the request left unset from the stored preferences.
+// Request values always win.
+func (f ProfileFilter) WithDefaults(prefs *DiscoveryPreferences) ProfileFilter {
+	if f.Gender != nil && len(f.Genders) == 0 {
+		f.Genders = []Gender{*f.Gender}
+	}
+	if prefs == nil {
+		return f
+	}
+
+	if f.MinAge == nil {
+		f.MinAge = prefs.MinAge
+	}
+	if f.MaxAge == nil {
+		f.MaxAge = prefs.MaxAge
+	}
+	if f.Radius == nil {
+		f.Radius = prefs.MaxDistanceKm
+	}
+	if len(f.Genders) == 0 {
+		for _, gender := range prefs.Genders {
+			f.Genders = append(f.Genders, Gender(gender))
+		}
+	}
+	if len(f.Sports) == 0 {
+		f.Sports = prefs.Sports
+	}
+	if len(f.SkillLevels) == 0 {
+		f.SkillLevels = prefs.SkillLevels
+	}
+	if f.MinRank == nil {
+		f.MinRank = prefs.MinRating
+	}
+	if f.MaxRank == nil {
+		f.MaxRank = prefs.MaxRating
+	}
+	if len(f.PlayStyles) == 0 {
+		f.PlayStyles = prefs.PlayStyles
+	}
+	return f
+}
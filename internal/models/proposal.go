@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+type ProposalStatus string
+
+const (
+	ProposalStatusPending   ProposalStatus = "pending"
+	ProposalStatusAccepted  ProposalStatus = "accepted" // the game is scheduled
+	ProposalStatusDeclined  ProposalStatus = "declined"
+	ProposalStatusCountered ProposalStatus = "countered" // replaced by a counter-proposal
+	ProposalStatusCancelled ProposalStatus = "cancelled" // a scheduled game called off
+)
+
+// GameProposal is an offer to play at a given time and place, made inside a match
+type GameProposal struct {
+	ID              int64          `json:"id" db:"id"`
+	MatchID         int64          `json:"match_id" db:"match_id"`
+	ProposedBy      int64          `json:"proposed_by" db:"proposed_by"`
+	Sport           string         `json:"sport" db:"sport"`
+	StartsAt        time.Time      `json:"starts_at" db:"starts_at"`
+	DurationMinutes int            `json:"duration_minutes" db:"duration_minutes"`
+	Venue           *string        `json:"venue" db:"venue"`
+	VenueID         *int64         `json:"venue_id" db:"venue_id"` // set when the venue is from the directory
+	Note            *string        `json:"note" db:"note"`
+	Status          ProposalStatus `json:"status" db:"status"`
+	CounterOf       *int64         `json:"counter_of" db:"counter_of"` // the proposal this one answers
+	RespondedAt     *time.Time     `json:"responded_at" db:"responded_at"`
+	Sequence        int            `json:"sequence" db:"sequence"` // bumped on every change to a scheduled game
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+	// Conflicts are computed when the proposal is read and are advisory only
+	Conflicts []ProposalConflict `json:"conflicts"`
+}
+
+// EndsAt is when the proposed game finishes
+func (p *GameProposal) EndsAt() time.Time {
+	return p.StartsAt.Add(time.Duration(p.DurationMinutes) * time.Minute)
+}
+
+type ConflictKind string
+
+const (
+	ConflictOutsideAvailability ConflictKind = "outside_availability"
+	ConflictScheduledGame       ConflictKind = "scheduled_game"
+)
+
+// ProposalConflict flags a reason one of the players may not make the proposed time
+type ProposalConflict struct {
+	UserID     int64        `json:"user_id"`
+	Kind       ConflictKind `json:"kind"`
+	ProposalID *int64       `json:"proposal_id,omitempty"` // the clashing scheduled game
+}
+
+type CreateProposalRequest struct {
+	Sport           string    `json:"sport" binding:"required"`
+	StartsAt        time.Time `json:"starts_at" binding:"required"`
+	DurationMinutes int       `json:"duration_minutes" binding:"required,min=15,max=480"`
+	Venue           *string   `json:"venue" binding:"omitempty,max=255"`
+	VenueID         *int64    `json:"venue_id"` // a directory venue; replaces Venue
+	Note            *string   `json:"note" binding:"omitempty,max=500"`
+}
+
+// ScheduledGame is an accepted (or since cancelled) proposal seen from one of its players
+type ScheduledGame struct {
+	GameProposal
+	PartnerID int64
+}
+
+// CalendarFeed is the secret subscription URL of a user's scheduled games
+type CalendarFeed struct {
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Plan is a user's subscription level; daily allowances depend on it
+type Plan string
+
+const (
+	PlanFree Plan = "free"
+	PlanPlus Plan = "plus"
+)
+
+// Feature names a premium feature that plans can include
+type Feature string
+
+const (
+	FeatureLikesInbox Feature = "likes_inbox"
+)
+
+// QuotaKind names a daily allowance
+type QuotaKind string
+
+const (
+	QuotaRightSwipes QuotaKind = "right_swipes"
+	QuotaSuperLikes  QuotaKind = "super_likes"
+	QuotaRewinds     QuotaKind = "rewinds"
+)
+
+// Quota is the state of one daily allowance. Limit and Remaining are null when unlimited.
+type Quota struct {
+	Limit     *int `json:"limit"`
+	Used      int  `json:"used"`
+	Remaining *int `json:"remaining"`
+}
+
+// QuotaStatus lists the user's daily allowances, which reset at their local midnight
+type QuotaStatus struct {
+	Plan     Plan                `json:"plan"`
+	ResetsAt time.Time           `json:"resets_at"`
+	Quotas   map[QuotaKind]Quota `json:"quotas"`
+}
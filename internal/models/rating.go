@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// RatingFormat separates ratings earned alone from ratings earned with partners
+type RatingFormat string
+
+const (
+	RatingFormatSingles RatingFormat = "singles"
+	RatingFormatDoubles RatingFormat = "doubles"
+	RatingFormatTeam    RatingFormat = "team"
+)
+
+// SportRating is a user's Glicko-2 rating in one sport and format
+type SportRating struct {
+	UserID       int64        `json:"-" db:"user_id"`
+	Sport        string       `json:"sport" db:"sport"`
+	Format       RatingFormat `json:"format" db:"format"`
+	Rating       float64      `json:"rating" db:"rating"`
+	Deviation    float64      `json:"deviation" db:"deviation"`
+	Volatility   float64      `json:"-" db:"volatility"`
+	Confidence   float64      `json:"confidence"`  // 0 (unknown) to 1 (certain)
+	Provisional  bool         `json:"provisional"` // too few games to rank
+	GamesPlayed  int          `json:"games_played" db:"games_played"`
+	LastPlayedAt *time.Time   `json:"last_played_at" db:"last_played_at"`
+	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// Format is the rating format a game counts towards, from its team sizes
+func (g *Game) Format() RatingFormat {
+	teams := map[int]int{}
+	for _, p := range g.Participants {
+		teams[p.Team]++
+	}
+	switch {
+	case teams[1] == 1 && teams[2] == 1:
+		return RatingFormatSingles
+	case teams[1] == 2 && teams[2] == 2:
+		return RatingFormatDoubles
+	default:
+		return RatingFormatTeam
+	}
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+type Sport struct {
+	ID           int64          `json:"id" db:"id"`
+	Slug         string         `json:"slug" db:"slug"`
+	Name         string         `json:"name" db:"name"`
+	DisplayNames LocalizedNames `json:"display_names" db:"display_names"`
+	TeamSize     TeamSize       `json:"team_size" db:"team_size"`
+	RatingSystem RatingSystem   `json:"rating_system" db:"rating_system"`
+	SkillLevels  StringList     `json:"skill_levels" db:"skill_levels"`
+	Active       bool           `json:"active" db:"active"`
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+type TeamSize string
+
+const (
+	TeamSizeSingles TeamSize = "singles"
+	TeamSizeDoubles TeamSize = "doubles"
+	TeamSizeTeam    TeamSize = "team"
+)
+
+type RatingSystem string
+
+const (
+	RatingSystemElo  RatingSystem = "elo"
+	RatingSystemNTRP RatingSystem = "ntrp"
+	RatingSystemDUPR RatingSystem = "dupr"
+	RatingSystemNone RatingSystem = "none"
+)
+
+// HasSkillLevel reports whether the sport accepts the given skill level
+func (s *Sport) HasSkillLevel(level string) bool {
+	for _, allowed := range s.SkillLevels {
+		if allowed == level {
+			return true
+		}
+	}
+	return false
+}
+
+// LocalizedNames maps a locale (e.g. "en", "fr") to a display name
+type LocalizedNames map[string]string
+
+func (ln LocalizedNames) Value() (driver.Value, error) {
+	return json.Marshal(ln)
+}
+
+func (ln *LocalizedNames) Scan(value interface{}) error {
+	if value == nil {
+		*ln = make(LocalizedNames)
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(bytes, ln)
+}
+
+type StringList []string
+
+func (sl StringList) Value() (driver.Value, error) {
+	if sl == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(sl)
+}
+
+func (sl *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*sl = StringList{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(bytes, sl)
+}
+
+// Sport creation request (admin)
+type CreateSportRequest struct {
+	Slug         string         `json:"slug" binding:"required,max=50"`
+	Name         string         `json:"name" binding:"required,max=100"`
+	DisplayNames LocalizedNames `json:"display_names"`
+	TeamSize     TeamSize       `json:"team_size" binding:"required,oneof=singles doubles team"`
+	RatingSystem RatingSystem   `json:"rating_system" binding:"required,oneof=elo ntrp dupr none"`
+	SkillLevels  StringList     `json:"skill_levels" binding:"required,min=1"`
+}
+
+// Sport update request (admin)
+type UpdateSportRequest struct {
+	Name         *string         `json:"name" binding:"omitempty,max=100"`
+	DisplayNames *LocalizedNames `json:"display_names"`
+	TeamSize     *TeamSize       `json:"team_size" binding:"omitempty,oneof=singles doubles team"`
+	RatingSystem *RatingSystem   `json:"rating_system" binding:"omitempty,oneof=elo ntrp dupr none"`
+	SkillLevels  *StringList     `json:"skill_levels" binding:"omitempty,min=1"`
+	Active       *bool           `json:"active"`
+}
@@ -0,0 +1,199 @@
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+type TournamentStatus string
+
+const (
+	TournamentStatusRegistration TournamentStatus = "registration"
+	TournamentStatusInProgress   TournamentStatus = "in_progress"
+	TournamentStatusCompleted    TournamentStatus = "completed"
+	TournamentStatusCancelled    TournamentStatus = "cancelled"
+)
+
+type TournamentFormat string
+
+const (
+	TournamentFormatSingleElimination TournamentFormat = "single_elimination"
+	TournamentFormatDoubleElimination TournamentFormat = "double_elimination"
+	TournamentFormatRoundRobin        TournamentFormat = "round_robin"
+)
+
+type EntryStatus string
+
+const (
+	EntryStatusRegistered EntryStatus = "registered"
+	EntryStatusWithdrawn  EntryStatus = "withdrawn"
+)
+
+// Tournament is a club event run by an organizer, optionally for the members of a group chat
+type Tournament struct {
+	ID          int64            `json:"id" db:"id"`
+	OrganizerID int64            `json:"organizer_id" db:"organizer_id"`
+	GroupID     *int64           `json:"group_id" db:"group_id"` // only the group's members can see and enter it
+	Name        string           `json:"name" db:"name"`
+	Sport       string           `json:"sport" db:"sport"`
+	Format      TournamentFormat `json:"format" db:"format"`
+	Status      TournamentStatus `json:"status" db:"status"`
+	MaxEntrants int              `json:"max_entrants" db:"max_entrants"`
+	StartsAt    time.Time        `json:"starts_at" db:"starts_at"`
+	Venue       *string          `json:"venue" db:"venue"`
+	Note        *string          `json:"note" db:"note"`
+	WinnerID    *int64           `json:"winner_id" db:"winner_id"`
+	StartedAt   *time.Time       `json:"started_at" db:"started_at"`
+	CompletedAt *time.Time       `json:"completed_at" db:"completed_at"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at" db:"updated_at"`
+	// State is the stored bracket, set once the tournament starts
+	State BracketState `json:"-" db:"bracket"`
+
+	Entrants  int                `json:"entrants"` // registered players
+	Organizer *UserProfile       `json:"organizer,omitempty"`
+	Entries   []TournamentEntry  `json:"entries,omitempty"`
+	Bracket   *TournamentBracket `json:"bracket,omitempty"`
+}
+
+// TournamentEntry is a player's registration. Seed and Rating are set when the tournament starts.
+type TournamentEntry struct {
+	TournamentID int64       `json:"tournament_id" db:"tournament_id"`
+	UserID       int64       `json:"user_id" db:"user_id"`
+	Status       EntryStatus `json:"status" db:"status"`
+	Seed         *int        `json:"seed" db:"seed"`
+	Rating       *float64    `json:"rating" db:"rating"`
+	RegisteredAt time.Time   `json:"registered_at" db:"registered_at"`
+
+	User *UserProfile `json:"user,omitempty"`
+}
+
+// BracketState is a tournament's bracket as stored, in the bracket package's own JSON
+type BracketState []byte
+
+func (s BracketState) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	return []byte(s), nil
+}
+
+func (s *BracketState) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+
+	*s = append(BracketState(nil), bytes...)
+	return nil
+}
+
+// TournamentBracket is the bracket laid out for display and printing: rounds in playing order,
+// each slot labelled with its player or where the player will come from
+type TournamentBracket struct {
+	Format    TournamentFormat  `json:"format"`
+	Rounds    []BracketRound    `json:"rounds"`
+	Standings []BracketStanding `json:"standings,omitempty"` // round robin only
+	Champion  *BracketEntrant   `json:"champion,omitempty"`
+}
+
+type BracketRound struct {
+	Section string         `json:"section"` // winners, losers, final or pool
+	Round   int            `json:"round"`
+	Name    string         `json:"name"`
+	Matches []BracketMatch `json:"matches"`
+}
+
+type BracketMatch struct {
+	ID       int             `json:"id"`
+	Position int             `json:"position"`
+	Status   string          `json:"status"` // pending, ready, completed or bye
+	Slots    [2]BracketSlot  `json:"slots"`
+	Winner   *BracketEntrant `json:"winner,omitempty"`
+	Score    *GameScore      `json:"score,omitempty"` // team 1 is the first slot
+	Report   *BracketReport  `json:"report,omitempty"`
+}
+
+// BracketReport is a player's result for a match, waiting for the other player or the
+// organizer to confirm it
+type BracketReport struct {
+	ReportedBy *BracketEntrant `json:"reported_by"`
+	Winner     *BracketEntrant `json:"winner,omitempty"` // nil for a draw
+	Score      *GameScore      `json:"score,omitempty"`
+}
+
+// BracketSlot holds a player, or a label such as "Winner of match 3" until the player is known
+type BracketSlot struct {
+	Entrant *BracketEntrant `json:"entrant,omitempty"`
+	Label   string          `json:"label"`
+}
+
+type BracketEntrant struct {
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+	Seed   int    `json:"seed"`
+}
+
+type BracketStanding struct {
+	BracketEntrant
+	Played int     `json:"played"`
+	Wins   int     `json:"wins"`
+	Draws  int     `json:"draws"`
+	Losses int     `json:"losses"`
+	Points float64 `json:"points"`
+}
+
+// TournamentExport is the printable snapshot of a tournament and its bracket
+type TournamentExport struct {
+	Version    int                `json:"version"`
+	ExportedAt time.Time          `json:"exported_at"`
+	Tournament TournamentSummary  `json:"tournament"`
+	Entrants   []BracketEntrant   `json:"entrants"` // in seed order once started
+	Bracket    *TournamentBracket `json:"bracket"`
+}
+
+type TournamentSummary struct {
+	ID        int64            `json:"id"`
+	Name      string           `json:"name"`
+	Sport     string           `json:"sport"`
+	Format    TournamentFormat `json:"format"`
+	Status    TournamentStatus `json:"status"`
+	StartsAt  time.Time        `json:"starts_at"`
+	Venue     *string          `json:"venue"`
+	Organizer string           `json:"organizer"`
+}
+
+// Tournament creation request
+type CreateTournamentRequest struct {
+	Name        string           `json:"name" binding:"required,max=100"`
+	Sport       string           `json:"sport" binding:"required"`
+	Format      TournamentFormat `json:"format" binding:"required,oneof=single_elimination double_elimination round_robin"`
+	MaxEntrants int              `json:"max_entrants" binding:"required,min=2,max=64"`
+	StartsAt    time.Time        `json:"starts_at" binding:"required"`
+	Venue       *string          `json:"venue" binding:"omitempty,max=255"`
+	Note        *string          `json:"note" binding:"omitempty,max=500"`
+	GroupID     *int64           `json:"group_id"`
+}
+
+// TournamentQuery narrows the tournament list
+type TournamentQuery struct {
+	Sport   string `form:"sport"`
+	GroupID *int64 `form:"group_id"` // a group's tournaments instead of the public ones
+}
+
+// Tournament match result; team 1 is the match's first slot. A player's result waits for
+// confirmation, the organizer's is final.
+type TournamentResultRequest struct {
+	Score GameScore `json:"score" binding:"required"`
+}
+
+// WSTournamentMessage is pushed to the organizer and entrants whenever the tournament changes
+type WSTournamentMessage struct {
+	Tournament *Tournament `json:"tournament"`
+	MatchID    int         `json:"match_id,omitempty"` // the bracket match whose result just changed
+}
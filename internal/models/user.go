@@ -28,8 +28,11 @@ type User struct {
 	PlayStyle         *string     `json:"play_style" db:"play_style"`
 	PreferredTimeslots *string    `json:"preferred_timeslots" db:"preferred_timeslots"`
 	Availability      Availability `json:"availability" db:"availability"`
+	PartnerSports     StringList  `json:"partner_sports" db:"partner_sports"` // sports the user wants a doubles partner for
 	CreatedAt         time.Time   `json:"created_at" db:"created_at"`
 	UpdatedAt         time.Time   `json:"updated_at" db:"updated_at"`
+	LastActiveAt      *time.Time  `json:"last_active_at" db:"last_active_at"`
+	Plan              Plan        `json:"plan" db:"plan"`
 }
 
 type Gender string
@@ -60,26 +63,6 @@ func (sp *SportPreferences) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, sp)
 }
 
-type Availability map[string][]string
-
-func (a Availability) Value() (driver.Value, error) {
-	return json.Marshal(a)
-}
-
-func (a *Availability) Scan(value interface{}) error {
-	if value == nil {
-		*a = make(Availability)
-		return nil
-	}
-	
-	bytes, ok := value.([]byte)
-	if !ok {
-		return nil
-	}
-	
-	return json.Unmarshal(bytes, a)
-}
-
 // User creation request
 type CreateUserRequest struct {
 	OAuthID         string            `json:"oauth_id" binding:"required"`
@@ -137,16 +120,32 @@ type ProfileUpdateRequest struct {
 }
 
 // Profile filtering
+// Any field left unset falls back to the user's stored DiscoveryPreferences.
 type ProfileFilter struct {
-	Gender    *Gender `json:"gender"`
-	Location  *string `json:"location"`
-	MinRank   *int    `json:"min_rank"`
-	MaxRank   *int    `json:"max_rank"`
-	Latitude  *float64 `json:"latitude"`
-	Longitude *float64 `json:"longitude"`
-	Radius    *float64 `json:"radius"` // in kilometers
-	Limit     int      `json:"limit"`
-	Offset    int      `json:"offset"`
+	Gender      *Gender  `json:"gender" form:"gender"`
+	Genders     []Gender `json:"genders" form:"genders"`
+	Location    *string  `json:"location" form:"location"`
+	MinAge      *int     `json:"min_age" form:"min_age"`
+	MaxAge      *int     `json:"max_age" form:"max_age"`
+	MinRank     *int     `json:"min_rank" form:"min_rank"`
+	MaxRank     *int     `json:"max_rank" form:"max_rank"`
+	Sports      []string `json:"sports" form:"sports"`
+	SkillLevels []string `json:"skill_levels" form:"skill_levels"`
+	PlayStyles  []string `json:"play_styles" form:"play_styles"`
+	Latitude    *float64 `json:"latitude" form:"latitude"`
+	Longitude   *float64 `json:"longitude" form:"longitude"`
+	Radius      *float64 `json:"radius" form:"radius"` // in kilometers
+	Limit       int      `json:"limit" form:"limit"`
+	Cursor      string   `json:"cursor" form:"cursor"`
+	// PartnerSport switches the deck to partner-seeking mode: only players looking for a
+	// doubles partner in the sport are shown, ranked as partners rather than opponents
+	PartnerSport string `json:"partner_sport,omitempty" form:"partner_sport"`
+}
+
+// ProfilePage is one page of the swipe deck. NextCursor is empty when the deck is exhausted.
+type ProfilePage struct {
+	Profiles   []UserProfile `json:"profiles"`
+	NextCursor string        `json:"next_cursor,omitempty"`
 }
 
 // User profile for swiping (excludes sensitive info)
@@ -165,5 +164,53 @@ type UserProfile struct {
 	PlayStyle         *string          `json:"play_style"`
 	PreferredTimeslots *string         `json:"preferred_timeslots"`
 	Availability      Availability     `json:"availability"`
+	PartnerSports     StringList       `json:"partner_sports"`
 	CreatedAt         time.Time        `json:"created_at"`
-} 
\ No newline at end of file
+	DistanceKm        *float64         `json:"distance_km,omitempty"` // rounded, set on swipe candidates
+	SuperLiked        bool             `json:"super_liked,omitempty"` // the profile super-liked the viewer
+	Compatibility     *Compatibility   `json:"compatibility,omitempty"`
+	Ratings           []SportRating    `json:"ratings,omitempty"` // per sport and format, from confirmed games
+}
+
+// SwipeCandidate is a profile considered for the swipe deck along with ranking inputs
+// that are not exposed on the profile itself
+type SwipeCandidate struct {
+	Profile      UserProfile
+	DistanceKm   *float64
+	LastActiveAt *time.Time
+}
+
+// Compatibility explains how well a profile fits the viewer
+type Compatibility struct {
+	Score   float64       `json:"score"` // 0-100
+	Reasons []MatchReason `json:"reasons"`
+}
+
+// MatchReason is one line of the "why you match" breakdown
+type MatchReason struct {
+	Factor string  `json:"factor"`
+	Score  float64 `json:"score"` // 0-1, before weighting
+	Detail string  `json:"detail"`
+} 
+
+// Profile returns the public profile view of the user
+func (u *User) Profile() UserProfile {
+	return UserProfile{
+		ID:                 u.ID,
+		Name:               u.Name,
+		Age:                u.Age,
+		Gender:             u.Gender,
+		Location:           u.Location,
+		Rank:               u.Rank,
+		ProfilePicURL:      u.ProfilePicURL,
+		Bio:                u.Bio,
+		SportPreferences:   u.SportPreferences,
+		SkillLevel:         u.SkillLevel,
+		NTRPRating:         u.NTRPRating,
+		PlayStyle:          u.PlayStyle,
+		PreferredTimeslots: u.PreferredTimeslots,
+		Availability:       u.Availability,
+		PartnerSports:      u.PartnerSports,
+		CreatedAt:          u.CreatedAt,
+	}
+}
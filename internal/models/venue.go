@@ -0,0 +1,153 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Surface string
+
+const (
+	SurfaceHard       Surface = "hard"
+	SurfaceClay       Surface = "clay"
+	SurfaceGrass      Surface = "grass"
+	SurfaceArtificial Surface = "artificial"
+	SurfaceCarpet     Surface = "carpet"
+	SurfaceWood       Surface = "wood"
+	SurfaceSand       Surface = "sand"
+	SurfaceOther      Surface = "other"
+)
+
+var surfaces = map[Surface]bool{
+	SurfaceHard: true, SurfaceClay: true, SurfaceGrass: true, SurfaceArtificial: true,
+	SurfaceCarpet: true, SurfaceWood: true, SurfaceSand: true, SurfaceOther: true,
+}
+
+// Venue is a place to play: a club, a park or a single court
+type Venue struct {
+	ID          int64      `json:"id" db:"id"`
+	ExternalID  *string    `json:"external_id,omitempty" db:"external_id"` // key of the source a venue was imported from
+	Name        string     `json:"name" db:"name"`
+	Address     string     `json:"address" db:"address"`
+	Latitude    float64    `json:"latitude" db:"latitude"`
+	Longitude   float64    `json:"longitude" db:"longitude"`
+	Sports      StringList `json:"sports" db:"sports"`
+	Surface     *Surface   `json:"surface" db:"surface"`
+	Indoor      bool       `json:"indoor" db:"indoor"`
+	Lights      bool       `json:"lights" db:"lights"`
+	CostPerHour *float64   `json:"cost_per_hour" db:"cost_per_hour"` // 0 is free, nil unknown
+	Currency    *string    `json:"currency" db:"currency"`
+	// OpeningHours are weekly windows in the venue's time zone; none listed means unknown
+	OpeningHours Availability `json:"opening_hours" db:"opening_hours"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`
+
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+	Favourite  bool     `json:"favourite"`
+}
+
+// HasSport reports whether the venue hosts the sport. Venues that list no sports host any.
+func (v *Venue) HasSport(sport string) bool {
+	if len(v.Sports) == 0 {
+		return true
+	}
+	for _, s := range v.Sports {
+		if s == sport {
+			return true
+		}
+	}
+	return false
+}
+
+// Label is how the venue is written in a game proposal, whose venue field holds 255 characters
+func (v *Venue) Label() string {
+	if label := v.Name + ", " + v.Address; v.Address != "" && len(label) <= 255 {
+		return label
+	}
+	return v.Name
+}
+
+// Venue creation request (admin and bulk import)
+type CreateVenueRequest struct {
+	ExternalID   *string      `json:"external_id" binding:"omitempty,max=100"`
+	Name         string       `json:"name" binding:"required,max=200"`
+	Address      string       `json:"address" binding:"max=500"`
+	Latitude     *float64     `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude    *float64     `json:"longitude" binding:"required,min=-180,max=180"`
+	Sports       StringList   `json:"sports"`
+	Surface      *Surface     `json:"surface" binding:"omitempty,oneof=hard clay grass artificial carpet wood sand other"`
+	Indoor       bool         `json:"indoor"`
+	Lights       bool         `json:"lights"`
+	CostPerHour  *float64     `json:"cost_per_hour" binding:"omitempty,min=0"`
+	Currency     *string      `json:"currency" binding:"omitempty,len=3"`
+	OpeningHours Availability `json:"opening_hours"`
+}
+
+// Validate repeats the binding checks, for requests that do not come through the API
+func (r *CreateVenueRequest) Validate() error {
+	switch {
+	case strings.TrimSpace(r.Name) == "":
+		return fmt.Errorf("name is required")
+	case len(r.Name) > 200:
+		return fmt.Errorf("name is too long")
+	case len(r.Address) > 500:
+		return fmt.Errorf("address is too long")
+	case r.ExternalID != nil && len(*r.ExternalID) > 100:
+		return fmt.Errorf("external_id is too long")
+	case r.Latitude == nil || *r.Latitude < -90 || *r.Latitude > 90:
+		return fmt.Errorf("latitude must be between -90 and 90")
+	case r.Longitude == nil || *r.Longitude < -180 || *r.Longitude > 180:
+		return fmt.Errorf("longitude must be between -180 and 180")
+	case r.Surface != nil && !surfaces[*r.Surface]:
+		return fmt.Errorf("invalid surface %q", *r.Surface)
+	case r.CostPerHour != nil && *r.CostPerHour < 0:
+		return fmt.Errorf("cost_per_hour cannot be negative")
+	case r.Currency != nil && len(*r.Currency) != 3:
+		return fmt.Errorf("currency must be a 3-letter code")
+	}
+	if len(r.OpeningHours.Windows) > 0 {
+		if err := r.OpeningHours.Validate(); err != nil {
+			return fmt.Errorf("opening_hours: %w", err)
+		}
+	}
+	return nil
+}
+
+// Venue update request (admin)
+type UpdateVenueRequest struct {
+	Name         *string       `json:"name" binding:"omitempty,max=200"`
+	Address      *string       `json:"address" binding:"omitempty,max=500"`
+	Latitude     *float64      `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude    *float64      `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Sports       *StringList   `json:"sports"`
+	Surface      *Surface      `json:"surface" binding:"omitempty,oneof=hard clay grass artificial carpet wood sand other"`
+	Indoor       *bool         `json:"indoor"`
+	Lights       *bool         `json:"lights"`
+	CostPerHour  *float64      `json:"cost_per_hour" binding:"omitempty,min=0"`
+	Currency     *string       `json:"currency" binding:"omitempty,len=3"`
+	OpeningHours *Availability `json:"opening_hours"`
+}
+
+// VenueSearchQuery finds venues around a point, by default the user's own location
+type VenueSearchQuery struct {
+	Latitude  *float64 `form:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `form:"longitude" binding:"omitempty,min=-180,max=180"`
+	RadiusKm  float64  `form:"radius_km" binding:"omitempty,gt=0,max=200"`
+	Sport     string   `form:"sport"`
+	Indoor    *bool    `form:"indoor"`
+	Lights    *bool    `form:"lights"`
+	Limit     int      `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// VenueImportResult reports a bulk import; rows that failed are listed and skipped
+type VenueImportResult struct {
+	Created int                `json:"created"`
+	Updated int                `json:"updated"`
+	Errors  []VenueImportError `json:"errors"`
+}
+
+type VenueImportError struct {
+	Row   int    `json:"row"` // 1-based data row, or feature index for GeoJSON
+	Error string `json:"error"`
+}
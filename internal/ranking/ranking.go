@@ -0,0 +1,354 @@
+package ranking
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/schedule"
+)
+
+// Factor names used in the "why you match" breakdown
+const (
+	FactorSharedSports = "shared_sports"
+	FactorRating       = "rating"
+	FactorAvailability = "availability"
+	FactorDistance     = "distance"
+	FactorRecency      = "recency"
+	FactorRank         = "rank"
+	FactorVenues       = "venues"
+	FactorPartner      = "partner"
+)
+
+const (
+	// rankScale is the rank gap at which rank proximity drops to zero
+	rankScale = 400.0
+	// ntrpScale is the NTRP gap at which rating proximity drops to zero
+	ntrpScale = 2.0
+	// overlapTarget is the weekly shared free time that earns a full availability score
+	overlapTarget = 6 * time.Hour
+	// distanceScale is the distance at which the distance score drops to zero
+	distanceScale = 50.0
+	// recencyHalfLife is how quickly inactive users sink in the deck
+	recencyHalfLife = 7 * 24 * time.Hour
+	// maxReasons caps the breakdown shown on a profile
+	maxReasons = 3
+	// reasonThreshold is the minimum factor score worth mentioning
+	reasonThreshold = 0.5
+	// sameStyleFit is how well two partners with the same play style fit, next to 1 for
+	// complementary styles
+	sameStyleFit = 0.75
+	// clashingStyleFit is how well a serious and a relaxed player fit as partners
+	clashingStyleFit = 0.2
+)
+
+// complementaryStyles pairs the play styles that round each other out in a doubles partnership:
+// both partners take the game equally seriously without approaching it the same way
+var complementaryStyles = map[string]string{
+	"ranked":      "competitive",
+	"competitive": "ranked",
+	"fun":         "casual",
+	"casual":      "fun",
+}
+
+// Weights are the relative importance of each factor
+type Weights struct {
+	SharedSports float64
+	Rating       float64
+	Availability float64
+	Distance     float64
+	Recency      float64
+	Rank         float64
+	Venues       float64
+	Partner      float64
+}
+
+// RatingFunc returns a user's rating in a sport on a common scale, or false when unknown
+type RatingFunc func(profile models.UserProfile, sport models.Sport) (rating float64, scale float64, ok bool)
+
+type Scorer struct {
+	weights Weights
+	sports  map[string]models.Sport
+	rating  RatingFunc
+	venues  map[int64][]models.Venue
+	partner string
+	now     time.Time
+}
+
+func NewScorer(weights Weights, sports []models.Sport, now time.Time) *Scorer {
+	catalog := make(map[string]models.Sport, len(sports))
+	for _, sport := range sports {
+		catalog[sport.Slug] = sport
+	}
+	return &Scorer{weights: weights, sports: catalog, rating: DefaultRating, now: now}
+}
+
+// WithRatings replaces the per-sport rating lookup
+func (s *Scorer) WithRatings(rating RatingFunc) *Scorer {
+	s.rating = rating
+	return s
+}
+
+// WithVenues sets the favourite venues of the viewer and candidates, by user ID
+func (s *Scorer) WithVenues(favourites map[int64][]models.Venue) *Scorer {
+	s.venues = favourites
+	return s
+}
+
+// ForPartner ranks candidates as doubles partners in the sport rather than as opponents
+func (s *Scorer) ForPartner(sport string) *Scorer {
+	s.partner = sport
+	return s
+}
+
+// DefaultRating uses the NTRP rating for NTRP sports and the overall rank otherwise
+func DefaultRating(profile models.UserProfile, sport models.Sport) (float64, float64, bool) {
+	if sport.RatingSystem == models.RatingSystemNTRP {
+		if profile.NTRPRating == nil {
+			return 0, 0, false
+		}
+		return *profile.NTRPRating, ntrpScale, true
+	}
+	return float64(profile.Rank), rankScale, true
+}
+
+// Score rates a candidate for the viewer and returns the compatibility breakdown
+func (s *Scorer) Score(viewer models.UserProfile, candidate models.SwipeCandidate) models.Compatibility {
+	type factor struct {
+		name   string
+		weight float64
+		score  float64
+		detail string
+	}
+
+	var factors []factor
+	shared := sharedSports(viewer.SportPreferences, candidate.Profile.SportPreferences)
+
+	// Shared sports
+	if selected := len(selectedSports(viewer.SportPreferences)); selected > 0 {
+		score := float64(len(shared)) / float64(selected)
+		factors = append(factors, factor{FactorSharedSports, s.weights.SharedSports, score,
+			fmt.Sprintf("You both play %s", s.sportNames(shared))})
+	}
+
+	// Rating proximity, averaged over shared sports
+	if len(shared) > 0 {
+		var total float64
+		var count int
+		for _, slug := range shared {
+			sport, ok := s.sports[slug]
+			if !ok {
+				continue
+			}
+			mine, scale, okMine := s.rating(viewer, sport)
+			theirs, _, okTheirs := s.rating(candidate.Profile, sport)
+			if !okMine || !okTheirs {
+				continue
+			}
+			total += proximity(mine, theirs, scale)
+			count++
+		}
+		if count > 0 {
+			factors = append(factors, factor{FactorRating, s.weights.Rating, total / float64(count),
+				"Similar level in the sports you share"})
+		}
+	}
+
+	// Availability overlap over the coming week
+	overlaps := schedule.Overlap(viewer.Availability, candidate.Profile.Availability, s.now, s.now.Add(schedule.DefaultHorizon))
+	var overlap time.Duration
+	for _, r := range overlaps {
+		overlap += r.End.Sub(r.Start)
+	}
+	factors = append(factors, factor{FactorAvailability, s.weights.Availability,
+		math.Min(1, float64(overlap)/float64(overlapTarget)),
+		fmt.Sprintf("%.0f hours of shared free time this week", overlap.Hours())})
+
+	// Distance
+	if candidate.DistanceKm != nil {
+		factors = append(factors, factor{FactorDistance, s.weights.Distance,
+			clamp(1 - *candidate.DistanceKm/distanceScale),
+			fmt.Sprintf("About %.0f km away", math.Max(1, math.Round(*candidate.DistanceKm)))})
+	}
+
+	// Recency of activity
+	if candidate.LastActiveAt != nil {
+		idle := s.now.Sub(*candidate.LastActiveAt)
+		if idle < 0 {
+			idle = 0
+		}
+		factors = append(factors, factor{FactorRecency, s.weights.Recency,
+			math.Exp2(-float64(idle) / float64(recencyHalfLife)), "Recently active"})
+	}
+
+	// Favourite venues, when both players have saved some
+	mine, theirs := s.venues[viewer.ID], s.venues[candidate.Profile.ID]
+	if len(mine) > 0 && len(theirs) > 0 {
+		score, detail := 0.0, ""
+		if common := sharedVenues(mine, theirs); len(common) > 0 {
+			score, detail = 1, fmt.Sprintf("You both play at %s", strings.Join(common, ", "))
+		}
+		factors = append(factors, factor{FactorVenues, s.weights.Venues, score, detail})
+	}
+
+	// Fit as a doubles partner: complementary play styles and a similar level
+	if s.partner != "" {
+		if score, ok := s.partnerFit(viewer, candidate.Profile); ok {
+			factors = append(factors, factor{FactorPartner, s.weights.Partner, score,
+				fmt.Sprintf("Could make a good %s doubles team with you", s.sportNames([]string{s.partner}))})
+		}
+	}
+
+	// Overall rank proximity
+	factors = append(factors, factor{FactorRank, s.weights.Rank,
+		proximity(float64(viewer.Rank), float64(candidate.Profile.Rank), rankScale), "Close in overall rank"})
+
+	var weighted, totalWeight float64
+	for _, f := range factors {
+		weighted += f.weight * f.score
+		totalWeight += f.weight
+	}
+
+	compatibility := models.Compatibility{Reasons: []models.MatchReason{}}
+	if totalWeight > 0 {
+		compatibility.Score = math.Round(weighted/totalWeight*1000) / 10
+	}
+
+	// Explain the strongest contributions first
+	sort.SliceStable(factors, func(i, j int) bool {
+		return factors[i].weight*factors[i].score > factors[j].weight*factors[j].score
+	})
+	for _, f := range factors {
+		if len(compatibility.Reasons) == maxReasons {
+			break
+		}
+		if f.weight <= 0 || f.score < reasonThreshold {
+			continue
+		}
+		compatibility.Reasons = append(compatibility.Reasons, models.MatchReason{
+			Factor: f.name,
+			Score:  math.Round(f.score*100) / 100,
+			Detail: f.detail,
+		})
+	}
+
+	return compatibility
+}
+
+// Rank scores every candidate, attaches the breakdown to the profiles and returns them best
+// first. Profiles that super-liked the viewer come before everyone else.
+func (s *Scorer) Rank(viewer models.UserProfile, candidates []models.SwipeCandidate) []models.UserProfile {
+	profiles := make([]models.UserProfile, len(candidates))
+	for i, candidate := range candidates {
+		compatibility := s.Score(viewer, candidate)
+		profiles[i] = candidate.Profile
+		profiles[i].Compatibility = &compatibility
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		if profiles[i].SuperLiked != profiles[j].SuperLiked {
+			return profiles[i].SuperLiked
+		}
+		if profiles[i].Compatibility.Score != profiles[j].Compatibility.Score {
+			return profiles[i].Compatibility.Score > profiles[j].Compatibility.Score
+		}
+		return profiles[i].ID < profiles[j].ID
+	})
+
+	return profiles
+}
+
+// partnerFit averages how well the two play styles go together and how close the players'
+// levels are in the partner sport, over whichever of the two is known
+func (s *Scorer) partnerFit(viewer, candidate models.UserProfile) (float64, bool) {
+	var total float64
+	var count int
+
+	if viewer.PlayStyle != nil && candidate.PlayStyle != nil {
+		total += styleFit(*viewer.PlayStyle, *candidate.PlayStyle)
+		count++
+	}
+
+	if sport, ok := s.sports[s.partner]; ok {
+		mine, scale, okMine := s.rating(viewer, sport)
+		theirs, _, okTheirs := s.rating(candidate, sport)
+		if okMine && okTheirs {
+			total += proximity(mine, theirs, scale)
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0, false
+	}
+	return total / float64(count), true
+}
+
+func styleFit(a, b string) float64 {
+	switch {
+	case complementaryStyles[a] == b:
+		return 1
+	case a == b:
+		return sameStyleFit
+	default:
+		return clashingStyleFit
+	}
+}
+
+func (s *Scorer) sportNames(slugs []string) string {
+	names := make([]string, len(slugs))
+	for i, slug := range slugs {
+		names[i] = slug
+		if sport, ok := s.sports[slug]; ok {
+			names[i] = sport.Name
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func selectedSports(prefs models.SportPreferences) []string {
+	var selected []string
+	for sport, enabled := range prefs {
+		if enabled {
+			selected = append(selected, sport)
+		}
+	}
+	sort.Strings(selected)
+	return selected
+}
+
+func sharedSports(a, b models.SportPreferences) []string {
+	var shared []string
+	for _, sport := range selectedSports(a) {
+		if b[sport] {
+			shared = append(shared, sport)
+		}
+	}
+	return shared
+}
+
+// sharedVenues returns the names of the venues on both lists, in a's order
+func sharedVenues(a, b []models.Venue) []string {
+	ids := make(map[int64]bool, len(b))
+	for _, v := range b {
+		ids[v.ID] = true
+	}
+	var shared []string
+	for _, v := range a {
+		if ids[v.ID] {
+			shared = append(shared, v.Name)
+		}
+	}
+	return shared
+}
+
+func proximity(a, b, scale float64) float64 {
+	return clamp(1 - math.Abs(a-b)/scale)
+}
+
+func clamp(value float64) float64 {
+	return math.Max(0, math.Min(1, value))
+}
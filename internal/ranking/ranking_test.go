@@ -0,0 +1,137 @@
+package ranking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"swipe-sports-backend/internal/models"
+)
+
+var testSports = []models.Sport{
+	{Slug: "tennis", Name: "Tennis", RatingSystem: models.RatingSystemNTRP},
+	{Slug: "soccer", Name: "Soccer", RatingSystem: models.RatingSystemElo},
+}
+
+var testWeights = Weights{SharedSports: 3, Rating: 2, Availability: 2, Distance: 1.5, Recency: 1, Rank: 0.5}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestRank_PrefersCompatibleCandidates(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	evenings := models.Availability{TimeZone: "UTC", Windows: []models.AvailabilityWindow{{Day: "tuesday", Start: "18:00", End: "22:00"}}}
+	active := now.Add(-time.Hour)
+
+	viewer := models.UserProfile{ID: 1, Rank: 1000, NTRPRating: floatPtr(3.5),
+		SportPreferences: models.SportPreferences{"tennis": true}, Availability: evenings}
+
+	good := models.SwipeCandidate{
+		Profile: models.UserProfile{ID: 2, Rank: 1050, NTRPRating: floatPtr(3.5),
+			SportPreferences: models.SportPreferences{"tennis": true}, Availability: evenings},
+		DistanceKm:   floatPtr(3),
+		LastActiveAt: &active,
+	}
+	poor := models.SwipeCandidate{
+		Profile: models.UserProfile{ID: 3, Rank: 1600,
+			SportPreferences: models.SportPreferences{"soccer": true}},
+		DistanceKm: floatPtr(80),
+	}
+
+	ranked := NewScorer(testWeights, testSports, now).Rank(viewer, []models.SwipeCandidate{poor, good})
+
+	require.Len(t, ranked, 2)
+	assert.Equal(t, int64(2), ranked[0].ID)
+	assert.Greater(t, ranked[0].Compatibility.Score, ranked[1].Compatibility.Score)
+	assert.LessOrEqual(t, ranked[0].Compatibility.Score, 100.0)
+
+	require.NotEmpty(t, ranked[0].Compatibility.Reasons)
+	assert.Equal(t, FactorSharedSports, ranked[0].Compatibility.Reasons[0].Factor)
+	assert.Equal(t, "You both play Tennis", ranked[0].Compatibility.Reasons[0].Detail)
+	assert.Empty(t, ranked[1].Compatibility.Reasons)
+}
+
+func TestScore_ZeroWeightIgnoresFactor(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	viewer := models.UserProfile{ID: 1, Rank: 1000}
+	near := models.SwipeCandidate{Profile: models.UserProfile{ID: 2, Rank: 1000}, DistanceKm: floatPtr(1)}
+	far := models.SwipeCandidate{Profile: models.UserProfile{ID: 3, Rank: 1000}, DistanceKm: floatPtr(45)}
+
+	scorer := NewScorer(Weights{Distance: 0, Rank: 1}, testSports, now)
+	assert.Equal(t, scorer.Score(viewer, near).Score, scorer.Score(viewer, far).Score)
+
+	scorer = NewScorer(Weights{Distance: 1, Rank: 1}, testSports, now)
+	assert.Greater(t, scorer.Score(viewer, near).Score, scorer.Score(viewer, far).Score)
+}
+
+func TestRank_SuperLikesFirst(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	viewer := models.UserProfile{ID: 1, Rank: 1000}
+	nearby := models.SwipeCandidate{Profile: models.UserProfile{ID: 2, Rank: 1000}, DistanceKm: floatPtr(1)}
+	superLiker := models.SwipeCandidate{Profile: models.UserProfile{ID: 3, Rank: 1400, SuperLiked: true}, DistanceKm: floatPtr(40)}
+
+	ranked := NewScorer(testWeights, testSports, now).Rank(viewer, []models.SwipeCandidate{nearby, superLiker})
+
+	require.Len(t, ranked, 2)
+	assert.Equal(t, int64(3), ranked[0].ID)
+	assert.Less(t, ranked[0].Compatibility.Score, ranked[1].Compatibility.Score)
+}
+
+func TestScore_SharedFavouriteVenues(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	club := models.Venue{ID: 10, Name: "Riverside Club"}
+	park := models.Venue{ID: 11, Name: "Central Park Courts"}
+
+	viewer := models.UserProfile{ID: 1, Rank: 1000}
+	regular := models.SwipeCandidate{Profile: models.UserProfile{ID: 2, Rank: 1000}}
+	elsewhere := models.SwipeCandidate{Profile: models.UserProfile{ID: 3, Rank: 1000}}
+	unknown := models.SwipeCandidate{Profile: models.UserProfile{ID: 4, Rank: 1000}}
+
+	scorer := NewScorer(Weights{Venues: 1, Rank: 1}, testSports, now).WithVenues(map[int64][]models.Venue{
+		1: {club},
+		2: {park, club},
+		3: {park},
+	})
+
+	shared := scorer.Score(viewer, regular)
+	assert.Equal(t, 100.0, shared.Score)
+	require.NotEmpty(t, shared.Reasons)
+	assert.Equal(t, FactorVenues, shared.Reasons[0].Factor)
+	assert.Equal(t, "You both play at Riverside Club", shared.Reasons[0].Detail)
+
+	assert.Equal(t, 50.0, scorer.Score(viewer, elsewhere).Score)
+	// Candidates without favourites are not penalised
+	assert.Equal(t, 100.0, scorer.Score(viewer, unknown).Score)
+}
+
+func stringPtr(v string) *string { return &v }
+
+func TestScore_PartnerFit(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	viewer := models.UserProfile{ID: 1, Rank: 1000, NTRPRating: floatPtr(3.5), PlayStyle: stringPtr("ranked")}
+	complementary := models.SwipeCandidate{Profile: models.UserProfile{ID: 2, Rank: 1000,
+		NTRPRating: floatPtr(3.5), PlayStyle: stringPtr("competitive")}}
+	same := models.SwipeCandidate{Profile: models.UserProfile{ID: 3, Rank: 1000,
+		NTRPRating: floatPtr(3.5), PlayStyle: stringPtr("ranked")}}
+	clashing := models.SwipeCandidate{Profile: models.UserProfile{ID: 4, Rank: 1000,
+		NTRPRating: floatPtr(3.5), PlayStyle: stringPtr("fun")}}
+	weaker := models.SwipeCandidate{Profile: models.UserProfile{ID: 5, Rank: 1000,
+		NTRPRating: floatPtr(2.5), PlayStyle: stringPtr("competitive")}}
+
+	scorer := NewScorer(Weights{Partner: 1, Rank: 1}, testSports, now).ForPartner("tennis")
+
+	best := scorer.Score(viewer, complementary)
+	assert.Equal(t, 100.0, best.Score)
+	require.NotEmpty(t, best.Reasons)
+	assert.Equal(t, FactorPartner, best.Reasons[0].Factor)
+	assert.Equal(t, "Could make a good Tennis doubles team with you", best.Reasons[0].Detail)
+
+	assert.Greater(t, best.Score, scorer.Score(viewer, same).Score)
+	assert.Greater(t, scorer.Score(viewer, same).Score, scorer.Score(viewer, clashing).Score)
+	assert.Greater(t, best.Score, scorer.Score(viewer, weaker).Score)
+
+	// Outside partner mode the factor is left out
+	plain := NewScorer(Weights{Partner: 1, Rank: 1}, testSports, now)
+	assert.Equal(t, plain.Score(viewer, complementary).Score, plain.Score(viewer, clashing).Score)
+}
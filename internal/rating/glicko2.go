@@ -0,0 +1,180 @@
+// Package rating implements the Glicko-2 rating system
+// (http://www.glicko.net/glicko/glicko2.pdf).
+//
+// Ratings are updated one game at a time, treating each game as its own rating period. Time
+// away from the game is accounted for by widening the deviation with Idle before the update.
+package rating
+
+import "math"
+
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+	DefaultTau        = 0.5
+
+	// MaxDeviation is the deviation of a player we know nothing about
+	MaxDeviation = DefaultDeviation
+	// ProvisionalDeviation is the deviation above which a rating is too uncertain to rank
+	ProvisionalDeviation = 110.0
+
+	// scale converts between the Glicko and Glicko-2 scales
+	scale = 173.7178
+	// convergence tolerance of the volatility iteration
+	epsilon = 0.000001
+)
+
+// Rating is a player's (or a team's) strength estimate
+type Rating struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+}
+
+// Default is the rating of a new player
+func Default() Rating {
+	return Rating{Rating: DefaultRating, Deviation: DefaultDeviation, Volatility: DefaultVolatility}
+}
+
+// Result is the outcome of one game against an opponent: 1 for a win, 0.5 for a draw, 0 for a loss
+type Result struct {
+	Opponent Rating
+	Score    float64
+}
+
+// Provisional reports whether the rating is still too uncertain to rank
+func (r Rating) Provisional() bool {
+	return r.Deviation > ProvisionalDeviation
+}
+
+// Confidence maps the deviation onto 0 (unknown) to 1 (certain)
+func (r Rating) Confidence() float64 {
+	return math.Max(0, math.Min(1, 1-r.Deviation/MaxDeviation))
+}
+
+// Idle widens the deviation for rating periods spent without playing, up to MaxDeviation
+func Idle(r Rating, periods float64) Rating {
+	if periods <= 0 {
+		return r
+	}
+	phi := r.Deviation / scale
+	phi = math.Sqrt(phi*phi + periods*r.Volatility*r.Volatility)
+	r.Deviation = math.Min(phi*scale, MaxDeviation)
+	return r
+}
+
+// Update returns the player's rating after the given results, with system constant tau
+func Update(player Rating, results []Result, tau float64) Rating {
+	if len(results) == 0 {
+		return Idle(player, 1)
+	}
+
+	mu := (player.Rating - DefaultRating) / scale
+	phi := player.Deviation / scale
+	sigma := player.Volatility
+
+	var vInv, improvement float64
+	for _, result := range results {
+		muJ := (result.Opponent.Rating - DefaultRating) / scale
+		phiJ := result.Opponent.Deviation / scale
+		g := g(phiJ)
+		e := expected(mu, muJ, phiJ)
+		vInv += g * g * e * (1 - e)
+		improvement += g * (result.Score - e)
+	}
+	v := 1 / vInv
+	delta := v * improvement
+
+	sigma = volatility(phi, sigma, v, delta, tau)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * improvement
+
+	return Rating{
+		Rating:     mu*scale + DefaultRating,
+		Deviation:  math.Min(phi*scale, MaxDeviation),
+		Volatility: sigma,
+	}
+}
+
+// ExpectedScore is the probability that a beats b, counting a draw as half a win
+func ExpectedScore(a, b Rating) float64 {
+	muA := (a.Rating - DefaultRating) / scale
+	muB := (b.Rating - DefaultRating) / scale
+	phi := math.Hypot(a.Deviation, b.Deviation) / scale
+	return expected(muA, muB, phi)
+}
+
+// Team combines the ratings of teammates into one: the mean rating, with the root mean
+// square deviation and volatility
+func Team(players []Rating) Rating {
+	if len(players) == 0 {
+		return Default()
+	}
+
+	var team Rating
+	for _, p := range players {
+		team.Rating += p.Rating
+		team.Deviation += p.Deviation * p.Deviation
+		team.Volatility += p.Volatility * p.Volatility
+	}
+	n := float64(len(players))
+	team.Rating /= n
+	team.Deviation = math.Sqrt(team.Deviation / n)
+	team.Volatility = math.Sqrt(team.Volatility / n)
+	return team
+}
+
+// TeamResult is the result a player records for a game played in a team. The opposing team is
+// shifted by the player's distance from their own team's mean, so that each teammate's expected
+// score equals the team's and the team's result is shared fairly between them.
+func TeamResult(player, team, opponents Rating, score float64) Result {
+	opponent := opponents
+	opponent.Rating = opponents.Rating + (player.Rating - team.Rating)
+	return Result{Opponent: opponent, Score: score}
+}
+
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func expected(mu, muJ, phiJ float64) float64 {
+	return 1 / (1 + math.Exp(-g(phiJ)*(mu-muJ)))
+}
+
+// volatility solves for the new volatility with the Illinois algorithm (step 5 of the paper)
+func volatility(phi, sigma, v, delta, tau float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
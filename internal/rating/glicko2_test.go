@@ -0,0 +1,63 @@
+package rating
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The worked example from Glickman's paper
+func TestUpdate_PaperExample(t *testing.T) {
+	player := Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	results := []Result{
+		{Opponent: Rating{Rating: 1400, Deviation: 30, Volatility: 0.06}, Score: 1},
+		{Opponent: Rating{Rating: 1550, Deviation: 100, Volatility: 0.06}, Score: 0},
+		{Opponent: Rating{Rating: 1700, Deviation: 300, Volatility: 0.06}, Score: 0},
+	}
+
+	updated := Update(player, results, DefaultTau)
+
+	assert.InDelta(t, 1464.06, updated.Rating, 0.01)
+	assert.InDelta(t, 151.52, updated.Deviation, 0.01)
+	assert.InDelta(t, 0.05999, updated.Volatility, 0.00001)
+}
+
+func TestUpdate_WinnerGainsLoserLoses(t *testing.T) {
+	a, b := Default(), Default()
+
+	winner := Update(a, []Result{{Opponent: b, Score: 1}}, DefaultTau)
+	loser := Update(b, []Result{{Opponent: a, Score: 0}}, DefaultTau)
+
+	assert.Greater(t, winner.Rating, DefaultRating)
+	assert.Less(t, loser.Rating, DefaultRating)
+	assert.InDelta(t, winner.Rating-DefaultRating, DefaultRating-loser.Rating, 0.001)
+	assert.Less(t, winner.Deviation, DefaultDeviation)
+}
+
+func TestIdle(t *testing.T) {
+	r := Rating{Rating: 1600, Deviation: 50, Volatility: 0.06}
+
+	assert.Equal(t, r, Idle(r, 0))
+	assert.Greater(t, Idle(r, 10).Deviation, r.Deviation)
+	assert.Equal(t, MaxDeviation, Idle(r, 1e6).Deviation)
+}
+
+func TestTeamResult_SharesTeamExpectation(t *testing.T) {
+	strong := Rating{Rating: 1800, Deviation: 80, Volatility: 0.06}
+	weak := Rating{Rating: 1400, Deviation: 80, Volatility: 0.06}
+	team := Team([]Rating{strong, weak})
+	opponents := Team([]Rating{Default(), Default()})
+
+	assert.Equal(t, 1600.0, team.Rating)
+	teamExpected := ExpectedScore(team, opponents)
+	for _, player := range []Rating{strong, weak} {
+		result := TeamResult(player, team, opponents, 1)
+		assert.InDelta(t, teamExpected, ExpectedScore(player, result.Opponent), 0.02)
+	}
+}
+
+func TestConfidence(t *testing.T) {
+	assert.Equal(t, 0.0, Default().Confidence())
+	assert.True(t, Default().Provisional())
+	assert.False(t, Rating{Rating: 1500, Deviation: 60}.Provisional())
+}
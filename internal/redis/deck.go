@@ -0,0 +1,160 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// A swipe deck is stored as three keys per user:
+//   - ProfileCacheKey: sorted set of profile IDs scored by their position in the deck
+//   - SwipeDeckCardsKey: hash of profile ID -> profile JSON
+//   - SwipeDeckMetaKey: hash with the deck version and the filter it was built with
+//
+// Positions never change within a version, which keeps cursors stable while the user
+// swipes cards out of the deck.
+
+// SwipeDeckCard is one ranked profile in a deck
+type SwipeDeckCard struct {
+	ProfileID int64
+	Position  int64
+	Data      []byte
+}
+
+// SwipeDeckMeta describes a stored deck
+type SwipeDeckMeta struct {
+	Version string
+	Filter  string
+}
+
+// StoreSwipeDeck replaces the user's deck
+func StoreSwipeDeck(userID int64, meta SwipeDeckMeta, cards []SwipeDeckCard) error {
+	ctx := context.Background()
+	deckKey := fmt.Sprintf(ProfileCacheKey, userID)
+	cardsKey := fmt.Sprintf(SwipeDeckCardsKey, userID)
+	metaKey := fmt.Sprintf(SwipeDeckMetaKey, userID)
+	expiry := ProfileCacheExpiry * time.Second
+
+	_, err := Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, deckKey, cardsKey, metaKey)
+		if len(cards) > 0 {
+			members := make([]redis.Z, len(cards))
+			fields := make(map[string]interface{}, len(cards))
+			for i, card := range cards {
+				id := strconv.FormatInt(card.ProfileID, 10)
+				members[i] = redis.Z{Score: float64(card.Position), Member: id}
+				fields[id] = card.Data
+			}
+			pipe.ZAdd(ctx, deckKey, members...)
+			pipe.HSet(ctx, cardsKey, fields)
+			pipe.Expire(ctx, deckKey, expiry)
+			pipe.Expire(ctx, cardsKey, expiry)
+		}
+		pipe.HSet(ctx, metaKey, "version", meta.Version, "filter", meta.Filter)
+		pipe.Expire(ctx, metaKey, expiry)
+		return nil
+	})
+	return err
+}
+
+// GetSwipeDeckMeta returns the stored deck's metadata, or redis.Nil when there is no deck
+func GetSwipeDeckMeta(userID int64) (*SwipeDeckMeta, error) {
+	ctx := context.Background()
+	values, err := Client.HGetAll(ctx, fmt.Sprintf(SwipeDeckMetaKey, userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, redis.Nil
+	}
+	return &SwipeDeckMeta{Version: values["version"], Filter: values["filter"]}, nil
+}
+
+// GetSwipeDeckPage returns up to limit cards positioned after the given position and how
+// many cards remain after them. Reading a page extends the deck's expiry.
+func GetSwipeDeckPage(userID int64, after int64, limit int) ([]SwipeDeckCard, int64, error) {
+	ctx := context.Background()
+	deckKey := fmt.Sprintf(ProfileCacheKey, userID)
+	cardsKey := fmt.Sprintf(SwipeDeckCardsKey, userID)
+	metaKey := fmt.Sprintf(SwipeDeckMetaKey, userID)
+
+	members, err := Client.ZRangeByScoreWithScores(ctx, deckKey, &redis.ZRangeBy{
+		Min:   "(" + strconv.FormatInt(after, 10),
+		Max:   "+inf",
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cards := make([]SwipeDeckCard, 0, len(members))
+	if len(members) > 0 {
+		fields := make([]string, len(members))
+		for i, member := range members {
+			fields[i] = member.Member
+		}
+		values, err := Client.HMGet(ctx, cardsKey, fields...).Result()
+		if err != nil {
+			return nil, 0, err
+		}
+		for i, member := range members {
+			data, ok := values[i].(string)
+			if !ok {
+				continue
+			}
+			profileID, _ := strconv.ParseInt(fields[i], 10, 64)
+			cards = append(cards, SwipeDeckCard{ProfileID: profileID, Position: int64(member.Score), Data: []byte(data)})
+		}
+	}
+
+	last := after
+	if len(members) > 0 {
+		last = int64(members[len(members)-1].Score)
+	}
+	remaining, err := Client.ZCount(ctx, deckKey, "("+strconv.FormatInt(last, 10), "+inf").Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	expiry := ProfileCacheExpiry * time.Second
+	Client.Expire(ctx, deckKey, expiry)
+	Client.Expire(ctx, cardsKey, expiry)
+	Client.Expire(ctx, metaKey, expiry)
+
+	return cards, remaining, nil
+}
+
+// RemoveFromSwipeDeck drops a profile from the deck and returns how many cards are left
+func RemoveFromSwipeDeck(userID, profileID int64) (int64, error) {
+	ctx := context.Background()
+	deckKey := fmt.Sprintf(ProfileCacheKey, userID)
+
+	pipe := Client.TxPipeline()
+	pipe.ZRem(ctx, deckKey, profileID)
+	pipe.HDel(ctx, fmt.Sprintf(SwipeDeckCardsKey, userID), strconv.FormatInt(profileID, 10))
+	remaining := pipe.ZCard(ctx, deckKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return remaining.Val(), nil
+}
+
+// DeleteSwipeDeck invalidates the user's deck so the next request rebuilds it
+func DeleteSwipeDeck(userID int64) error {
+	ctx := context.Background()
+	return Client.Del(ctx,
+		fmt.Sprintf(ProfileCacheKey, userID),
+		fmt.Sprintf(SwipeDeckCardsKey, userID),
+		fmt.Sprintf(SwipeDeckMetaKey, userID),
+	).Err()
+}
+
+// AcquireSwipeDeckLock claims the right to rebuild the user's deck. The lock is left to
+// expire rather than released, so it also rate limits rebuilds.
+func AcquireSwipeDeckLock(userID int64, ttl time.Duration) (bool, error) {
+	ctx := context.Background()
+	return Client.SetNX(ctx, fmt.Sprintf(SwipeDeckLockKey, userID), 1, ttl).Result()
+}
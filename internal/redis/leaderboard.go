@@ -0,0 +1,147 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Leaderboards are sorted sets of user IDs scored by rating, one per sport, format and scope.
+// The scope is either LeaderboardGlobal or a region. Every entry is rewritten by the periodic
+// rebuild, which is what removes players who went inactive.
+
+// LeaderboardGlobal is the scope of the leaderboard covering every region
+const LeaderboardGlobal = "global"
+
+// LeaderboardMember is one ranked player
+type LeaderboardMember struct {
+	UserID int64
+	Score  float64
+}
+
+// LeaderboardKeyFor returns the key of a leaderboard; an empty region is the global board
+func LeaderboardKeyFor(sport, format, region string) string {
+	scope := LeaderboardGlobal
+	if region != "" {
+		scope = "region:" + region
+	}
+	return fmt.Sprintf(LeaderboardKey, sport, format, scope)
+}
+
+// SetLeaderboardScore adds or moves a player on each of the given leaderboards
+func SetLeaderboardScore(keys []string, userID int64, score float64) error {
+	ctx := context.Background()
+	member := redis.Z{Score: score, Member: strconv.FormatInt(userID, 10)}
+
+	_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.ZAdd(ctx, key, member)
+		}
+		return nil
+	})
+	return err
+}
+
+// RemoveFromLeaderboards takes a player off each of the given leaderboards
+func RemoveFromLeaderboards(keys []string, userID int64) error {
+	ctx := context.Background()
+	member := strconv.FormatInt(userID, 10)
+
+	_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.ZRem(ctx, key, member)
+		}
+		return nil
+	})
+	return err
+}
+
+// GetLeaderboardRange returns the players ranked start to stop (0-based, inclusive), best first,
+// along with the size of the leaderboard
+func GetLeaderboardRange(key string, start, stop int64) ([]LeaderboardMember, int64, error) {
+	ctx := context.Background()
+
+	pipe := Client.Pipeline()
+	entries := pipe.ZRevRangeWithScores(ctx, key, start, stop)
+	size := pipe.ZCard(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	members := make([]LeaderboardMember, 0, len(entries.Val()))
+	for _, z := range entries.Val() {
+		id, err := strconv.ParseInt(fmt.Sprint(z.Member), 10, 64)
+		if err != nil {
+			continue
+		}
+		members = append(members, LeaderboardMember{UserID: id, Score: z.Score})
+	}
+	return members, size.Val(), nil
+}
+
+// GetLeaderboardRank returns the player's 0-based rank, best first, and whether they are on
+// the leaderboard at all
+func GetLeaderboardRank(key string, userID int64) (int64, bool, error) {
+	ctx := context.Background()
+	rank, err := Client.ZRevRank(ctx, key, strconv.FormatInt(userID, 10)).Result()
+	if err == redis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return rank, true, nil
+}
+
+// GetLeaderboardSize returns the number of players on a leaderboard
+func GetLeaderboardSize(key string) (int64, error) {
+	ctx := context.Background()
+	return Client.ZCard(ctx, key).Result()
+}
+
+// ReplaceLeaderboards swaps in freshly built leaderboards and deletes any existing leaderboard
+// that is not among them or is empty. Each board is written to a temporary key and renamed over
+// the old one, so readers never see a half-built board.
+func ReplaceLeaderboards(boards map[string][]LeaderboardMember) error {
+	ctx := context.Background()
+
+	for key, members := range boards {
+		if len(members) == 0 {
+			continue
+		}
+		tmpKey := key + ":rebuild"
+		zs := make([]redis.Z, len(members))
+		for i, m := range members {
+			zs[i] = redis.Z{Score: m.Score, Member: strconv.FormatInt(m.UserID, 10)}
+		}
+
+		_, err := Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, tmpKey)
+			for start := 0; start < len(zs); start += 1000 {
+				end := start + 1000
+				if end > len(zs) {
+					end = len(zs)
+				}
+				pipe.ZAdd(ctx, tmpKey, zs[start:end]...)
+			}
+			pipe.Rename(ctx, tmpKey, key)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to rebuild leaderboard %s: %w", key, err)
+		}
+	}
+
+	iter := Client.Scan(ctx, 0, fmt.Sprintf(LeaderboardKey, "*", "*", "*"), 1000).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if len(boards[key]) == 0 {
+			if err := Client.Del(ctx, key).Err(); err != nil {
+				return fmt.Errorf("failed to delete stale leaderboard %s: %w", key, err)
+			}
+		}
+	}
+	return iter.Err()
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -34,13 +36,23 @@ func Init() (*redis.Client, error) {
 
 // Cache keys
 const (
-	UserProfileKey     = "user:profile:%d"
-	UserMatchesKey     = "user:matches:%d"
-	MatchMessagesKey   = "match:messages:%d"
-	OnlineUsersKey     = "online:users"
-	RateLimitKey       = "rate_limit:%s"
-	ProfileCacheKey    = "profiles:swipe:%d"
-	ProfileCacheExpiry = 300 // 5 minutes
+	UserProfileKey      = "user:profile:%d"
+	UserMatchesKey      = "user:matches:%d"
+	MatchMessagesKey    = "match:messages:%d"
+	OnlineUsersKey      = "online:users"
+	RateLimitKey        = "rate_limit:%s"
+	ProfileCacheKey     = "profiles:swipe:%d"
+	ProfileCacheExpiry  = 300 // 5 minutes
+	SwipeDeckCardsKey   = "profiles:swipe:%d:cards"
+	SwipeDeckMetaKey    = "profiles:swipe:%d:meta"
+	SwipeDeckLockKey    = "profiles:swipe:%d:lock"
+	SportsCatalogKey    = "sports:catalog"
+	SportsCatalogExpiry = 600 // 10 minutes
+	DailyQuotaKey       = "quota:%s:%d:%s" // kind, user ID, local date
+	DailyQuotaExpiry    = 48 * 3600        // outlives the day in every time zone
+	UserEventsChannel   = "events:user:%d"
+	UserEventsPattern   = "events:user:*"
+	LeaderboardKey      = "leaderboard:%s:%s:%s" // sport, format, scope
 )
 
 // Cache helper functions
@@ -98,6 +110,82 @@ func DeleteMatchMessages(matchID int64) error {
 	return Client.Del(ctx, key).Err()
 }
 
+func SetSportsCatalog(data []byte) error {
+	ctx := context.Background()
+	return Client.Set(ctx, SportsCatalogKey, data, SportsCatalogExpiry*time.Second).Err()
+}
+
+func GetSportsCatalog() ([]byte, error) {
+	ctx := context.Background()
+	return Client.Get(ctx, SportsCatalogKey).Bytes()
+}
+
+func DeleteSportsCatalog() error {
+	ctx := context.Background()
+	return Client.Del(ctx, SportsCatalogKey).Err()
+}
+
+// ConsumeDailyQuota counts one use of a daily allowance and reports whether it was within the
+// limit, along with the uses so far. Day is the user's local date, so the count resets at
+// their midnight. Uses over the limit are not counted.
+func ConsumeDailyQuota(kind string, userID int64, day string, limit int) (bool, int64, error) {
+	ctx := context.Background()
+	key := fmt.Sprintf(DailyQuotaKey, kind, userID, day)
+
+	pipe := Client.TxPipeline()
+	used := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, DailyQuotaExpiry*time.Second)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, 0, err
+	}
+
+	if used.Val() > int64(limit) {
+		Client.Decr(ctx, key)
+		return false, int64(limit), nil
+	}
+	return true, used.Val(), nil
+}
+
+// GetDailyQuotaUsage returns how many uses of each kind the user has made on the given day
+func GetDailyQuotaUsage(userID int64, day string, kinds []string) ([]int64, error) {
+	ctx := context.Background()
+	keys := make([]string, len(kinds))
+	for i, kind := range kinds {
+		keys[i] = fmt.Sprintf(DailyQuotaKey, kind, userID, day)
+	}
+
+	values, err := Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make([]int64, len(kinds))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			usage[i], _ = strconv.ParseInt(s, 10, 64)
+		}
+	}
+	return usage, nil
+}
+
+// RefundDailyQuota gives back a use when the action it was consumed for did not happen
+func RefundDailyQuota(kind string, userID int64, day string) error {
+	ctx := context.Background()
+	return Client.Decr(ctx, fmt.Sprintf(DailyQuotaKey, kind, userID, day)).Err()
+}
+
+// PublishUserEvent sends a WebSocket event to the user through whichever server holds their connections
+func PublishUserEvent(userID int64, data []byte) error {
+	ctx := context.Background()
+	return Client.Publish(ctx, fmt.Sprintf(UserEventsChannel, userID), data).Err()
+}
+
+// SubscribeUserEvents receives the events published for every user
+func SubscribeUserEvents() *redis.PubSub {
+	ctx := context.Background()
+	return Client.PSubscribe(ctx, UserEventsPattern)
+}
+
 func AddOnlineUser(userID int64) error {
 	ctx := context.Background()
 	return Client.SAdd(ctx, OnlineUsersKey, userID).Err()
@@ -118,19 +206,61 @@ func IsUserOnline(userID int64) (bool, error) {
 	return Client.SIsMember(ctx, OnlineUsersKey, userID).Result()
 }
 
-// Rate limiting
-func CheckRateLimit(identifier string, limit int, window int) (bool, error) {
+// slidingWindowScript counts requests in the trailing window with a sorted set of request
+// timestamps. It runs atomically, so concurrent requests cannot slip past the limit. It returns
+// whether the request is allowed, the requests left and the milliseconds until a slot frees up.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local reset = 0
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, limit - count, reset}
+`)
+
+// RateLimitResult is the outcome of a rate limit check
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the oldest counted request leaves the window
+	Reset time.Duration
+}
+
+var rateLimitSeq uint64
+
+// CheckRateLimit counts a request against a sliding window limit for the identifier
+func CheckRateLimit(identifier string, limit int, window time.Duration) (*RateLimitResult, error) {
 	ctx := context.Background()
 	key := fmt.Sprintf(RateLimitKey, identifier)
-	
-	count, err := Client.Incr(ctx, key).Result()
+	now := time.Now()
+	member := fmt.Sprintf("%d-%d", now.UnixNano(), atomic.AddUint64(&rateLimitSeq, 1))
+
+	values, err := slidingWindowScript.Run(ctx, Client, []string{key},
+		now.UnixMilli(), window.Milliseconds(), limit, member).Int64Slice()
 	if err != nil {
-		return false, err
-	}
-	
-	if count == 1 {
-		Client.Expire(ctx, key, time.Duration(window)*time.Second)
+		return nil, err
 	}
-	
-	return count <= int64(limit), nil
-} 
\ No newline at end of file
+
+	return &RateLimitResult{
+		Allowed:   values[0] == 1,
+		Limit:     limit,
+		Remaining: int(values[1]),
+		Reset:     time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
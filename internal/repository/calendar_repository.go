@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/database"
+)
+
+type CalendarRepository struct {
+	db *sql.DB
+}
+
+func NewCalendarRepository() *CalendarRepository {
+	return &CalendarRepository{db: database.DB}
+}
+
+// GetFeedToken returns the user's calendar feed token and when it was issued, or an empty
+// token if the user has none
+func (r *CalendarRepository) GetFeedToken(userID int64) (string, time.Time, error) {
+	var token string
+	var createdAt time.Time
+	err := r.db.QueryRow(`SELECT token, created_at FROM calendar_feeds WHERE user_id = ?`, userID).Scan(&token, &createdAt)
+	if err == sql.ErrNoRows {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+	return token, createdAt, nil
+}
+
+// SetFeedToken issues the user a feed token, replacing any previous one
+func (r *CalendarRepository) SetFeedToken(userID int64, token string) error {
+	query := `
+		INSERT INTO calendar_feeds (user_id, token) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE token = VALUES(token), created_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := r.db.Exec(query, userID, token); err != nil {
+		return fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+	return nil
+}
+
+// GetUserIDByFeedToken returns the owner of a feed token, or 0 if no user has it
+func (r *CalendarRepository) GetUserIDByFeedToken(token string) (int64, error) {
+	var userID int64
+	err := r.db.QueryRow(`SELECT user_id FROM calendar_feeds WHERE token = ?`, token).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+	return userID, nil
+}
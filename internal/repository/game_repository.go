@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/models"
+)
+
+type GameRepository struct {
+	db *sql.DB
+}
+
+func NewGameRepository() *GameRepository {
+	return &GameRepository{db: database.DB}
+}
+
+const gameColumns = `g.id, g.match_id, g.challenge_id, g.sport, g.played_at, g.score, g.winner_team, g.status, g.reported_by,
+	g.confirm_deadline, g.confirmed_at, g.disputed_by, g.dispute_reason, g.resolved_by, g.resolution_note,
+	g.created_at, g.updated_at`
+
+func scanGame(row rowScanner) (*models.Game, error) {
+	var game models.Game
+	err := row.Scan(
+		&game.ID, &game.MatchID, &game.ChallengeID, &game.Sport, &game.PlayedAt, &game.Score, &game.WinnerTeam, &game.Status,
+		&game.ReportedBy, &game.ConfirmDeadline, &game.ConfirmedAt, &game.DisputedBy, &game.DisputeReason,
+		&game.ResolvedBy, &game.ResolutionNote, &game.CreatedAt, &game.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+// Create stores a game together with its participants
+func (r *GameRepository) Create(game *models.Game) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO games (match_id, challenge_id, sport, played_at, score, winner_team, status, reported_by, confirm_deadline)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := tx.Exec(query,
+		game.MatchID, game.ChallengeID, game.Sport, game.PlayedAt, game.Score, game.WinnerTeam, game.Status, game.ReportedBy,
+		game.ConfirmDeadline,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create game: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	for _, p := range game.Participants {
+		if _, err := tx.Exec(`INSERT INTO game_participants (game_id, user_id, team) VALUES (?, ?, ?)`, id, p.UserID, p.Team); err != nil {
+			return fmt.Errorf("failed to add game participant: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit game: %w", err)
+	}
+
+	game.ID = id
+	return nil
+}
+
+func (r *GameRepository) GetByID(id int64) (*models.Game, error) {
+	return getGame(r.db, id)
+}
+
+func getGame(q queryer, id int64) (*models.Game, error) {
+	query := `SELECT ` + gameColumns + ` FROM games g WHERE g.id = ?`
+
+	game, err := scanGame(q.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get game: %w", err)
+	}
+
+	if err := loadParticipants(q, []*models.Game{game}); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// GetForUser returns the games the user played in, most recent first
+func (r *GameRepository) GetForUser(userID int64, limit int) ([]models.Game, error) {
+	query := `
+		SELECT ` + gameColumns + `
+		FROM games g
+		JOIN game_participants gp ON gp.game_id = g.id
+		WHERE gp.user_id = ?
+		ORDER BY g.played_at DESC, g.id DESC
+		LIMIT ?
+	`
+	return r.queryGames(query, userID, limit)
+}
+
+// GetByStatus returns games in the given status, oldest first, as a work queue
+func (r *GameRepository) GetByStatus(status models.GameStatus, limit int) ([]models.Game, error) {
+	query := `SELECT ` + gameColumns + ` FROM games g WHERE g.status = ? ORDER BY g.updated_at, g.id LIMIT ?`
+	return r.queryGames(query, status, limit)
+}
+
+// GetExpiredPendingIDs returns pending games whose confirmation window closed before now
+func (r *GameRepository) GetExpiredPendingIDs(now time.Time, limit int) ([]int64, error) {
+	query := `SELECT id FROM games WHERE status = 'pending' AND confirm_deadline <= ? ORDER BY confirm_deadline LIMIT ?`
+
+	rows, err := r.db.Query(query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired games: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan game id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// RateFunc computes the participants' new ratings for a confirmed game from their current
+// ratings in its sport and format. Participants without a rating are missing from current.
+type RateFunc func(game *models.Game, current map[int64]models.SportRating) []models.SportRating
+
+// PartnershipRateFunc computes the new ratings of the partnerships that played a confirmed
+// doubles game together. pairs holds each such partnership by team; current holds the players'
+// ratings from before the game, as given to RateFunc.
+type PartnershipRateFunc func(game *models.Game, current map[int64]models.SportRating, pairs map[int]models.Partnership) []models.Partnership
+
+// Confirm marks a game confirmed if it is still in the expected status and applies its rating
+// changes, the partnerships' included, in the same transaction. It reports false when someone
+// else got there first.
+func (r *GameRepository) Confirm(gameID int64, from models.GameStatus, rate RateFunc, ratePairs PartnershipRateFunc) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE games SET status = 'confirmed', confirmed_at = CURRENT_TIMESTAMP WHERE id = ? AND status = ?`
+	result, err := tx.Exec(query, gameID, from)
+	if err != nil {
+		return false, fmt.Errorf("failed to confirm game: %w", err)
+	}
+	if confirmed, err := rowsChanged(result); err != nil || !confirmed {
+		return false, err
+	}
+
+	game, err := getGame(tx, gameID)
+	if err != nil {
+		return false, err
+	}
+
+	userIDs := make([]int64, len(game.Participants))
+	for i, p := range game.Participants {
+		userIDs[i] = p.UserID
+	}
+	current, err := lockRatings(tx, game.Sport, game.Format(), userIDs)
+	if err != nil {
+		return false, err
+	}
+	for _, updated := range rate(game, current) {
+		if err := saveRating(tx, &updated); err != nil {
+			return false, err
+		}
+	}
+
+	if game.Format() == models.RatingFormatDoubles {
+		teams := make(map[int][]int64, 2)
+		for _, p := range game.Participants {
+			teams[p.Team] = append(teams[p.Team], p.UserID)
+		}
+		pairs := make(map[int]models.Partnership, 2)
+		for team := 1; team <= 2; team++ {
+			partnership, err := lockPartnership(tx, game.Sport, teams[team][0], teams[team][1])
+			if err != nil {
+				return false, err
+			}
+			if partnership != nil {
+				pairs[team] = *partnership
+			}
+		}
+		if len(pairs) > 0 {
+			for _, updated := range ratePairs(game, current, pairs) {
+				if err := savePartnershipRating(tx, &updated); err != nil {
+					return false, err
+				}
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit game confirmation: %w", err)
+	}
+
+	return true, nil
+}
+
+// Dispute moves a pending game to the admin queue while its confirmation window is open
+func (r *GameRepository) Dispute(gameID, userID int64, reason string, now time.Time) (bool, error) {
+	query := `
+		UPDATE games SET status = 'disputed', disputed_by = ?, dispute_reason = ?
+		WHERE id = ? AND status = 'pending' AND confirm_deadline > ?
+	`
+
+	result, err := r.db.Exec(query, userID, reason, gameID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to dispute game: %w", err)
+	}
+	return rowsChanged(result)
+}
+
+// Resolve records an admin's decision on a disputed game, including a corrected score. The game
+// stays disputed until it is confirmed or voided.
+func (r *GameRepository) Resolve(gameID, adminID int64, score models.GameScore, note *string) error {
+	query := `
+		UPDATE games SET score = ?, winner_team = ?, resolved_by = ?, resolution_note = ?
+		WHERE id = ? AND status = 'disputed'
+	`
+
+	if _, err := r.db.Exec(query, score, score.Winner(), adminID, note, gameID); err != nil {
+		return fmt.Errorf("failed to resolve game: %w", err)
+	}
+	return nil
+}
+
+// Void throws out a disputed game
+func (r *GameRepository) Void(gameID int64) (bool, error) {
+	result, err := r.db.Exec(`UPDATE games SET status = 'voided' WHERE id = ? AND status = 'disputed'`, gameID)
+	if err != nil {
+		return false, fmt.Errorf("failed to void game: %w", err)
+	}
+	return rowsChanged(result)
+}
+
+// ForEachConfirmed calls fn for every confirmed game with its participants, in the order the
+// games were played
+func (r *GameRepository) ForEachConfirmed(fn func(game *models.Game) error) error {
+	query := `
+		SELECT g.id, g.sport, g.played_at, g.winner_team, g.confirmed_at, gp.user_id, gp.team
+		FROM games g
+		JOIN game_participants gp ON gp.game_id = g.id
+		WHERE g.status = 'confirmed'
+		ORDER BY g.played_at, g.id, gp.team, gp.user_id
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return fmt.Errorf("failed to get confirmed games: %w", err)
+	}
+	defer rows.Close()
+
+	var game *models.Game
+	for rows.Next() {
+		var row models.Game
+		var p models.GameParticipant
+		if err := rows.Scan(&row.ID, &row.Sport, &row.PlayedAt, &row.WinnerTeam, &row.ConfirmedAt, &p.UserID, &p.Team); err != nil {
+			return fmt.Errorf("failed to scan confirmed game: %w", err)
+		}
+
+		if game == nil || game.ID != row.ID {
+			if game != nil {
+				if err := fn(game); err != nil {
+					return err
+				}
+			}
+			row.Status = models.GameStatusConfirmed
+			game = &row
+		}
+		game.Participants = append(game.Participants, p)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get confirmed games: %w", err)
+	}
+
+	if game != nil {
+		return fn(game)
+	}
+	return nil
+}
+
+func (r *GameRepository) queryGames(query string, args ...interface{}) ([]models.Game, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get games: %w", err)
+	}
+	defer rows.Close()
+
+	var ptrs []*models.Game
+	for rows.Next() {
+		game, err := scanGame(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan game: %w", err)
+		}
+		ptrs = append(ptrs, game)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get games: %w", err)
+	}
+
+	if err := loadParticipants(r.db, ptrs); err != nil {
+		return nil, err
+	}
+
+	games := make([]models.Game, len(ptrs))
+	for i, game := range ptrs {
+		games[i] = *game
+	}
+	return games, nil
+}
+
+func loadParticipants(q queryer, games []*models.Game) error {
+	if len(games) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*models.Game, len(games))
+	args := make([]interface{}, len(games))
+	for i, game := range games {
+		byID[game.ID] = game
+		game.Participants = []models.GameParticipant{}
+		args[i] = game.ID
+	}
+
+	query := `SELECT game_id, user_id, team FROM game_participants WHERE game_id IN (` + placeholders(len(games)) + `) ORDER BY team, user_id`
+
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to get game participants: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var gameID int64
+		var p models.GameParticipant
+		if err := rows.Scan(&gameID, &p.UserID, &p.Team); err != nil {
+			return fmt.Errorf("failed to scan game participant: %w", err)
+		}
+		if game, ok := byID[gameID]; ok {
+			game.Participants = append(game.Participants, p)
+		}
+	}
+
+	return rows.Err()
+}
+
+func rowsChanged(result sql.Result) (bool, error) {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return affected > 0, nil
+}
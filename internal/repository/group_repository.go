@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/models"
+)
+
+// GroupRepository stores group matches and the participants of every match
+type GroupRepository struct {
+	db *sql.DB
+}
+
+func NewGroupRepository() *GroupRepository {
+	return &GroupRepository{db: database.DB}
+}
+
+// insertParticipants adds active players to a match with the given role. Players who had left
+// or been removed rejoin.
+func insertParticipants(tx *sql.Tx, matchID int64, role models.ParticipantRole, userIDs ...int64) error {
+	query := `
+		INSERT INTO match_participants (match_id, user_id, role) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE role = VALUES(role), status = 'active', joined_at = CURRENT_TIMESTAMP, left_at = NULL
+	`
+	for _, userID := range userIDs {
+		if _, err := tx.Exec(query, matchID, userID, role); err != nil {
+			return fmt.Errorf("failed to add participant: %w", err)
+		}
+	}
+	return nil
+}
+
+// Create stores a group with its creator as host and the given members
+func (r *GroupRepository) Create(group *models.Match, memberIDs []int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO matches (kind, user1_id, title, sport) VALUES ('group', ?, ?, ?)`
+	result, err := tx.Exec(query, group.User1ID, group.Title, group.Sport)
+	if err != nil {
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	if err := insertParticipants(tx, id, models.ParticipantRoleHost, group.User1ID); err != nil {
+		return err
+	}
+	if err := insertParticipants(tx, id, models.ParticipantRoleMember, memberIDs...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit group: %w", err)
+	}
+
+	group.ID = id
+	return nil
+}
+
+// GetForUser returns the active groups the user takes part in, newest first
+func (r *GroupRepository) GetForUser(userID int64) ([]models.Match, error) {
+	query := `
+		SELECT ` + matchColumns + `
+		FROM matches
+		WHERE kind = 'group' AND status = 'active'
+			AND id IN (SELECT match_id FROM match_participants WHERE user_id = ? AND status = 'active')
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []models.Match{}
+	for rows.Next() {
+		group, err := scanMatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		groups = append(groups, *group)
+	}
+
+	return groups, nil
+}
+
+// GetParticipants returns everyone who has taken part in the match with their profiles, the
+// active ones first and each in the order they joined
+func (r *GroupRepository) GetParticipants(matchID int64) ([]models.Participant, error) {
+	query := fmt.Sprintf(`
+		SELECT %s, p.match_id, p.user_id, p.role, p.status, p.joined_at, p.left_at
+		FROM match_participants p
+		JOIN users u ON u.id = p.user_id
+		WHERE p.match_id = ?
+		ORDER BY p.status = 'active' DESC, p.joined_at, p.user_id
+	`, profileColumns)
+
+	rows, err := r.db.Query(query, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+	defer rows.Close()
+
+	participants := []models.Participant{}
+	for rows.Next() {
+		var p models.Participant
+		profile, err := scanProfile(rows, &p.MatchID, &p.UserID, &p.Role, &p.Status, &p.JoinedAt, &p.LeftAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan participant: %w", err)
+		}
+		p.User = profile
+		participants = append(participants, p)
+	}
+
+	return participants, nil
+}
+
+// GetActiveParticipantIDs returns the users currently in the match
+func (r *GroupRepository) GetActiveParticipantIDs(matchID int64) ([]int64, error) {
+	rows, err := r.db.Query(`SELECT user_id FROM match_participants WHERE match_id = ? AND status = 'active'`, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan participant: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, nil
+}
+
+// AddParticipants adds members to an active group. It returns false, adding nobody, if the
+// group has ended or would grow beyond maxSize active participants.
+func (r *GroupRepository) AddParticipants(matchID int64, userIDs []int64, maxSize int) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status models.MatchStatus
+	if err := tx.QueryRow(`SELECT status FROM matches WHERE id = ? FOR UPDATE`, matchID).Scan(&status); err != nil {
+		return false, fmt.Errorf("failed to lock group: %w", err)
+	}
+	if status != models.MatchStatusActive {
+		return false, nil
+	}
+
+	var active int
+	query := `SELECT COUNT(*) FROM match_participants WHERE match_id = ? AND status = 'active'`
+	if err := tx.QueryRow(query, matchID).Scan(&active); err != nil {
+		return false, fmt.Errorf("failed to count participants: %w", err)
+	}
+	if active+len(userIDs) > maxSize {
+		return false, nil
+	}
+
+	if err := insertParticipants(tx, matchID, models.ParticipantRoleMember, userIDs...); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit participants: %w", err)
+	}
+	return true, nil
+}
+
+// RemoveParticipant marks an active participant as left or removed. A departing host hands
+// over to the longest-standing member, and a group nobody is left in ends. It returns false
+// if the user was not an active participant of an active group.
+func (r *GroupRepository) RemoveParticipant(matchID, userID int64, status models.ParticipantStatus) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the group first so concurrent departures see each other
+	var matchStatus models.MatchStatus
+	if err := tx.QueryRow(`SELECT status FROM matches WHERE id = ? FOR UPDATE`, matchID).Scan(&matchStatus); err != nil {
+		return false, fmt.Errorf("failed to lock group: %w", err)
+	}
+	if matchStatus != models.MatchStatusActive {
+		return false, nil
+	}
+
+	var role models.ParticipantRole
+	query := `SELECT role FROM match_participants WHERE match_id = ? AND user_id = ? AND status = 'active'`
+	err = tx.QueryRow(query, matchID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get participant: %w", err)
+	}
+
+	query = `
+		UPDATE match_participants SET status = ?, role = 'member', left_at = CURRENT_TIMESTAMP
+		WHERE match_id = ? AND user_id = ?
+	`
+	if _, err := tx.Exec(query, status, matchID, userID); err != nil {
+		return false, fmt.Errorf("failed to remove participant: %w", err)
+	}
+
+	if role == models.ParticipantRoleHost {
+		query = `
+			UPDATE match_participants SET role = 'host'
+			WHERE match_id = ? AND status = 'active'
+			ORDER BY joined_at, user_id
+			LIMIT 1
+		`
+		result, err := tx.Exec(query, matchID)
+		if err != nil {
+			return false, fmt.Errorf("failed to hand over host: %w", err)
+		}
+		promoted, err := rowsChanged(result)
+		if err != nil {
+			return false, err
+		}
+		if !promoted {
+			query = `UPDATE matches SET status = 'ended', ended_by = ?, ended_at = CURRENT_TIMESTAMP WHERE id = ?`
+			if _, err := tx.Exec(query, userID, matchID); err != nil {
+				return false, fmt.Errorf("failed to end group: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit participant removal: %w", err)
+	}
+	return true, nil
+}
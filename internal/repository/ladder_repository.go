@@ -0,0 +1,599 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/models"
+)
+
+type LadderRepository struct {
+	db *sql.DB
+}
+
+func NewLadderRepository() *LadderRepository {
+	return &LadderRepository{db: database.DB}
+}
+
+// ladderColumns selects ladders aliased as l, with their number of players
+const ladderColumns = `l.id, l.organizer_id, l.group_id, l.name, l.sport, l.challenge_range, l.accept_hours,
+	l.play_hours, l.status, l.created_at, l.updated_at,
+	(SELECT COUNT(*) FROM ladder_players p WHERE p.ladder_id = l.id)`
+
+func scanLadder(row rowScanner) (*models.Ladder, error) {
+	var l models.Ladder
+	err := row.Scan(
+		&l.ID, &l.OrganizerID, &l.GroupID, &l.Name, &l.Sport, &l.ChallengeRange, &l.AcceptHours,
+		&l.PlayHours, &l.Status, &l.CreatedAt, &l.UpdatedAt, &l.Players,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+const challengeColumns = `c.id, c.ladder_id, c.challenger_id, c.defender_id, c.status, c.accept_deadline,
+	c.play_deadline, c.game_id, c.winner_id, c.forfeited_by, c.resolved_at, c.created_at, c.updated_at`
+
+func scanChallenge(row rowScanner) (*models.LadderChallenge, error) {
+	var c models.LadderChallenge
+	err := row.Scan(
+		&c.ID, &c.LadderID, &c.ChallengerID, &c.DefenderID, &c.Status, &c.AcceptDeadline,
+		&c.PlayDeadline, &c.GameID, &c.WinnerID, &c.ForfeitedBy, &c.ResolvedAt, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *LadderRepository) Create(l *models.Ladder) error {
+	query := `
+		INSERT INTO ladders (organizer_id, group_id, name, sport, challenge_range, accept_hours, play_hours, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query,
+		l.OrganizerID, l.GroupID, l.Name, l.Sport, l.ChallengeRange, l.AcceptHours, l.PlayHours, l.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create ladder: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	l.ID = id
+	return nil
+}
+
+func (r *LadderRepository) GetByID(id int64) (*models.Ladder, error) {
+	query := `SELECT ` + ladderColumns + ` FROM ladders l WHERE l.id = ?`
+
+	l, err := scanLadder(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ladder: %w", err)
+	}
+
+	return l, nil
+}
+
+// List returns the active ladders, newest first: a group's when groupID is set, otherwise the
+// public ones. An empty sport means any.
+func (r *LadderRepository) List(sport string, groupID *int64, limit int) ([]models.Ladder, error) {
+	query := `SELECT ` + ladderColumns + ` FROM ladders l
+		WHERE l.status = 'active' AND (? = '' OR l.sport = ?)`
+	args := []interface{}{sport, sport}
+	if groupID != nil {
+		query += ` AND l.group_id = ?`
+		args = append(args, *groupID)
+	} else {
+		query += ` AND l.group_id IS NULL`
+	}
+	query += ` ORDER BY l.created_at DESC, l.id DESC LIMIT ?`
+	args = append(args, limit)
+
+	return r.query(query, args...)
+}
+
+// GetForUser returns the ladders the user organizes or plays on, newest first
+func (r *LadderRepository) GetForUser(userID int64) ([]models.Ladder, error) {
+	query := `SELECT ` + ladderColumns + ` FROM ladders l
+		WHERE l.organizer_id = ? OR l.id IN (SELECT ladder_id FROM ladder_players WHERE user_id = ?)
+		ORDER BY l.created_at DESC, l.id DESC`
+
+	return r.query(query, userID, userID)
+}
+
+func (r *LadderRepository) query(query string, args ...interface{}) ([]models.Ladder, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ladders: %w", err)
+	}
+	defer rows.Close()
+
+	ladders := []models.Ladder{}
+	for rows.Next() {
+		l, err := scanLadder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ladder: %w", err)
+		}
+		ladders = append(ladders, *l)
+	}
+
+	return ladders, rows.Err()
+}
+
+// GetPlayers returns the ladder's players with their profiles, top of the ladder first
+func (r *LadderRepository) GetPlayers(ladderID int64) ([]models.LadderPlayer, error) {
+	query := fmt.Sprintf(`
+		SELECT %s, p.ladder_id, p.user_id, p.position, p.wins, p.losses, p.joined_at
+		FROM ladder_players p
+		JOIN users u ON u.id = p.user_id
+		WHERE p.ladder_id = ?
+		ORDER BY p.position
+	`, profileColumns)
+
+	rows, err := r.db.Query(query, ladderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ladder players: %w", err)
+	}
+	defer rows.Close()
+
+	players := []models.LadderPlayer{}
+	for rows.Next() {
+		var p models.LadderPlayer
+		profile, err := scanProfile(rows, &p.LadderID, &p.UserID, &p.Position, &p.Wins, &p.Losses, &p.JoinedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ladder player: %w", err)
+		}
+		p.User = profile
+		players = append(players, p)
+	}
+
+	return players, rows.Err()
+}
+
+// Join puts the user at the bottom of an active ladder. It reports false if the ladder is
+// closed or the user is already on it.
+func (r *LadderRepository) Join(ladderID, userID int64) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ladder, err := lockLadder(tx, ladderID)
+	if err != nil || ladder == nil || ladder.Status != models.LadderStatusActive {
+		return false, err
+	}
+
+	query := `
+		INSERT IGNORE INTO ladder_players (ladder_id, user_id, position)
+		SELECT ?, ?, COALESCE(MAX(position), 0) + 1 FROM ladder_players WHERE ladder_id = ?
+	`
+	result, err := tx.Exec(query, ladderID, userID, ladderID)
+	if err != nil {
+		return false, fmt.Errorf("failed to join ladder: %w", err)
+	}
+	if joined, err := rowsChanged(result); err != nil || !joined {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit ladder join: %w", err)
+	}
+
+	return true, nil
+}
+
+// Leave takes the user off the ladder, moves everyone below up a place and cancels the user's
+// open challenges. It reports false if the user was not on the ladder.
+func (r *LadderRepository) Leave(ladderID, userID int64) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ladder, err := lockLadder(tx, ladderID)
+	if err != nil || ladder == nil {
+		return false, err
+	}
+
+	var position int
+	query := `SELECT position FROM ladder_players WHERE ladder_id = ? AND user_id = ?`
+	if err := tx.QueryRow(query, ladderID, userID).Scan(&position); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get ladder position: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM ladder_players WHERE ladder_id = ? AND user_id = ?`, ladderID, userID); err != nil {
+		return false, fmt.Errorf("failed to leave ladder: %w", err)
+	}
+	query = `UPDATE ladder_players SET position = position - 1 WHERE ladder_id = ? AND position > ?`
+	if _, err := tx.Exec(query, ladderID, position); err != nil {
+		return false, fmt.Errorf("failed to move players up: %w", err)
+	}
+
+	query = `
+		UPDATE ladder_challenges SET status = 'cancelled', resolved_at = CURRENT_TIMESTAMP
+		WHERE ladder_id = ? AND status IN ('pending', 'accepted') AND (challenger_id = ? OR defender_id = ?)
+	`
+	if _, err := tx.Exec(query, ladderID, userID, userID); err != nil {
+		return false, fmt.Errorf("failed to cancel challenges: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit ladder leave: %w", err)
+	}
+
+	return true, nil
+}
+
+// Close stops an active ladder and cancels its open challenges. It reports false if the
+// ladder was already closed.
+func (r *LadderRepository) Close(ladderID int64) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE ladders SET status = 'closed' WHERE id = ? AND status = 'active'`, ladderID)
+	if err != nil {
+		return false, fmt.Errorf("failed to close ladder: %w", err)
+	}
+	if closed, err := rowsChanged(result); err != nil || !closed {
+		return false, err
+	}
+
+	query := `
+		UPDATE ladder_challenges SET status = 'cancelled', resolved_at = CURRENT_TIMESTAMP
+		WHERE ladder_id = ? AND status IN ('pending', 'accepted')
+	`
+	if _, err := tx.Exec(query, ladderID); err != nil {
+		return false, fmt.Errorf("failed to cancel challenges: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit ladder close: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *LadderRepository) GetChallenge(id int64) (*models.LadderChallenge, error) {
+	return getChallenge(r.db, id, false)
+}
+
+func getChallenge(q queryer, id int64, lock bool) (*models.LadderChallenge, error) {
+	query := `SELECT ` + challengeColumns + ` FROM ladder_challenges c WHERE c.id = ?`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+
+	c, err := scanChallenge(q.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+
+	return c, nil
+}
+
+// GetChallenges returns the ladder's open challenges, then its most recently decided ones
+func (r *LadderRepository) GetChallenges(ladderID int64, limit int) ([]models.LadderChallenge, error) {
+	query := `SELECT ` + challengeColumns + ` FROM ladder_challenges c
+		WHERE c.ladder_id = ?
+		ORDER BY c.status IN ('pending', 'accepted') DESC, COALESCE(c.resolved_at, c.created_at) DESC, c.id DESC
+		LIMIT ?`
+
+	rows, err := r.db.Query(query, ladderID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenges: %w", err)
+	}
+	defer rows.Close()
+
+	challenges := []models.LadderChallenge{}
+	for rows.Next() {
+		c, err := scanChallenge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan challenge: %w", err)
+		}
+		challenges = append(challenges, *c)
+	}
+
+	return challenges, rows.Err()
+}
+
+// ChallengeFunc decides whether a challenge may be made, given the ladder and both players'
+// places as they stand with the ladder locked. A player not on the ladder is nil; busy reports
+// whether either player already has an open challenge.
+type ChallengeFunc func(ladder *models.Ladder, challenger, defender *models.LadderPlayer, busy bool) error
+
+// CreateChallenge stores a challenge if check allows it. It reports false if the ladder does
+// not exist or is closed; errors returned by check are passed back unchanged.
+func (r *LadderRepository) CreateChallenge(c *models.LadderChallenge, check ChallengeFunc) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ladder, err := lockLadder(tx, c.LadderID)
+	if err != nil || ladder == nil || ladder.Status != models.LadderStatusActive {
+		return false, err
+	}
+
+	challenger, err := getLadderPlayer(tx, c.LadderID, c.ChallengerID)
+	if err != nil {
+		return false, err
+	}
+	defender, err := getLadderPlayer(tx, c.LadderID, c.DefenderID)
+	if err != nil {
+		return false, err
+	}
+
+	var busy bool
+	query := `
+		SELECT EXISTS (SELECT 1 FROM ladder_challenges
+			WHERE ladder_id = ? AND status IN ('pending', 'accepted')
+				AND (challenger_id IN (?, ?) OR defender_id IN (?, ?)))
+	`
+	if err := tx.QueryRow(query, c.LadderID, c.ChallengerID, c.DefenderID, c.ChallengerID, c.DefenderID).Scan(&busy); err != nil {
+		return false, fmt.Errorf("failed to check open challenges: %w", err)
+	}
+
+	if err := check(ladder, challenger, defender, busy); err != nil {
+		return false, err
+	}
+
+	query = `
+		INSERT INTO ladder_challenges (ladder_id, challenger_id, defender_id, status, accept_deadline)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	result, err := tx.Exec(query, c.LadderID, c.ChallengerID, c.DefenderID, c.Status, c.AcceptDeadline)
+	if err != nil {
+		return false, fmt.Errorf("failed to create challenge: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit challenge: %w", err)
+	}
+
+	c.ID = id
+	return true, nil
+}
+
+// Accept lets the defender take up a pending challenge before its deadline. It reports false
+// if the challenge is not theirs to accept.
+func (r *LadderRepository) Accept(challengeID, defenderID int64, now, playDeadline time.Time) (bool, error) {
+	query := `
+		UPDATE ladder_challenges SET status = 'accepted', play_deadline = ?
+		WHERE id = ? AND defender_id = ? AND status = 'pending' AND accept_deadline > ?
+	`
+
+	result, err := r.db.Exec(query, playDeadline, challengeID, defenderID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to accept challenge: %w", err)
+	}
+	return rowsChanged(result)
+}
+
+// HasGame reports whether a game that has not been voided was reported for the challenge
+func (r *LadderRepository) HasGame(challengeID int64) (bool, error) {
+	return hasChallengeGame(r.db, challengeID)
+}
+
+func hasChallengeGame(q queryer, challengeID int64) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM games WHERE challenge_id = ? AND status <> 'voided')`
+	if err := q.QueryRow(query, challengeID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check challenge games: %w", err)
+	}
+	return exists, nil
+}
+
+// Forfeit concedes an open challenge on the user's behalf; the other player wins it. It
+// reports false if the user is not in the challenge, it was already decided, or a game reported
+// for it is left to decide it.
+func (r *LadderRepository) Forfeit(challengeID, userID int64) (bool, error) {
+	return r.resolve(challengeID, func(c *models.LadderChallenge, tx *sql.Tx) (models.ChallengeStatus, int64, error) {
+		if !c.Open() || c.Other(userID) == 0 {
+			return "", 0, nil
+		}
+		played, err := hasChallengeGame(tx, c.ID)
+		if err != nil || played {
+			return "", 0, err
+		}
+		c.ForfeitedBy = &userID
+		return models.ChallengeStatusForfeited, c.Other(userID), nil
+	})
+}
+
+// Settle decides an accepted challenge by the confirmed game reported for it; a winnerID of 0
+// is a draw, which leaves the defender in place. It reports false if the challenge was no
+// longer waiting for a game.
+func (r *LadderRepository) Settle(challengeID, gameID, winnerID int64) (bool, error) {
+	return r.resolve(challengeID, func(c *models.LadderChallenge, tx *sql.Tx) (models.ChallengeStatus, int64, error) {
+		if c.Status != models.ChallengeStatusAccepted {
+			return "", 0, nil
+		}
+		c.GameID = &gameID
+		return models.ChallengeStatusCompleted, winnerID, nil
+	})
+}
+
+// GetOverdueIDs returns challenges past their acceptance deadline, or accepted and past their
+// play deadline, as of now. Challenges with a game reported for them are left out: the game
+// decides them, however long its confirmation takes.
+func (r *LadderRepository) GetOverdueIDs(now time.Time, limit int) ([]int64, error) {
+	query := `
+		SELECT c.id FROM ladder_challenges c
+		WHERE (c.status = 'pending' AND c.accept_deadline <= ?)
+			OR (c.status = 'accepted' AND c.play_deadline <= ?
+				AND NOT EXISTS (SELECT 1 FROM games g WHERE g.challenge_id = c.id AND g.status <> 'voided'))
+		ORDER BY c.id
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, now, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overdue challenges: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan challenge id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// ResolveOverdue applies a challenge's deadlines as of now: a challenge not accepted in time is
+// forfeited by the defender, and one accepted but not played in time expires with nobody
+// moving. A challenge with a game reported for it is left for the game to decide. It reports
+// false if nothing was due.
+func (r *LadderRepository) ResolveOverdue(challengeID int64, now time.Time) (bool, error) {
+	return r.resolve(challengeID, func(c *models.LadderChallenge, tx *sql.Tx) (models.ChallengeStatus, int64, error) {
+		switch {
+		case c.Status == models.ChallengeStatusPending && !c.AcceptDeadline.After(now):
+			c.ForfeitedBy = &c.DefenderID
+			return models.ChallengeStatusForfeited, c.ChallengerID, nil
+		case c.Status == models.ChallengeStatusAccepted && c.PlayDeadline != nil && !c.PlayDeadline.After(now):
+			played, err := hasChallengeGame(tx, c.ID)
+			if err != nil || played {
+				return "", 0, err
+			}
+			return models.ChallengeStatusExpired, 0, nil
+		}
+		return "", 0, nil
+	})
+}
+
+// outcomeFunc decides a locked challenge: its new status and winner, or an empty status to
+// leave it alone
+type outcomeFunc func(c *models.LadderChallenge, tx *sql.Tx) (models.ChallengeStatus, int64, error)
+
+// resolve decides a challenge with its ladder locked. When the challenger wins they swap places
+// with the defender, provided they are still below them; wins and losses are counted for both.
+func (r *LadderRepository) resolve(challengeID int64, outcome outcomeFunc) (bool, error) {
+	c, err := r.GetChallenge(challengeID)
+	if err != nil || c == nil {
+		return false, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the ladder before the challenge, in the same order as every other ladder change
+	if _, err := lockLadder(tx, c.LadderID); err != nil {
+		return false, err
+	}
+	if c, err = getChallenge(tx, challengeID, true); err != nil || c == nil {
+		return false, err
+	}
+
+	status, winnerID, err := outcome(c, tx)
+	if err != nil || status == "" {
+		return false, err
+	}
+
+	var winner *int64
+	if winnerID != 0 {
+		winner = &winnerID
+	}
+	query := `
+		UPDATE ladder_challenges SET status = ?, winner_id = ?, game_id = ?, forfeited_by = ?, resolved_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+	if _, err := tx.Exec(query, status, winner, c.GameID, c.ForfeitedBy, challengeID); err != nil {
+		return false, fmt.Errorf("failed to resolve challenge: %w", err)
+	}
+
+	if winnerID != 0 {
+		loserID := c.Other(winnerID)
+		query = `UPDATE ladder_players SET wins = wins + IF(user_id = ?, 1, 0), losses = losses + IF(user_id = ?, 1, 0)
+			WHERE ladder_id = ? AND user_id IN (?, ?)`
+		if _, err := tx.Exec(query, winnerID, loserID, c.LadderID, winnerID, loserID); err != nil {
+			return false, fmt.Errorf("failed to record challenge result: %w", err)
+		}
+	}
+
+	if winnerID == c.ChallengerID {
+		challenger, err := getLadderPlayer(tx, c.LadderID, c.ChallengerID)
+		if err != nil {
+			return false, err
+		}
+		defender, err := getLadderPlayer(tx, c.LadderID, c.DefenderID)
+		if err != nil {
+			return false, err
+		}
+		if challenger != nil && defender != nil && challenger.Position > defender.Position {
+			query = `UPDATE ladder_players SET position = IF(user_id = ?, ?, ?) WHERE ladder_id = ? AND user_id IN (?, ?)`
+			if _, err := tx.Exec(query,
+				c.ChallengerID, defender.Position, challenger.Position, c.LadderID, c.ChallengerID, c.DefenderID,
+			); err != nil {
+				return false, fmt.Errorf("failed to swap positions: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit challenge result: %w", err)
+	}
+
+	return true, nil
+}
+
+// lockLadder locks a ladder row for the rest of the transaction; every change to a ladder's
+// players or challenges takes this lock first
+func lockLadder(tx *sql.Tx, ladderID int64) (*models.Ladder, error) {
+	query := `SELECT ` + ladderColumns + ` FROM ladders l WHERE l.id = ? FOR UPDATE`
+
+	l, err := scanLadder(tx.QueryRow(query, ladderID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to lock ladder: %w", err)
+	}
+	return l, nil
+}
+
+func getLadderPlayer(q queryer, ladderID, userID int64) (*models.LadderPlayer, error) {
+	var p models.LadderPlayer
+	query := `SELECT ladder_id, user_id, position, wins, losses, joined_at FROM ladder_players WHERE ladder_id = ? AND user_id = ?`
+	err := q.QueryRow(query, ladderID, userID).Scan(&p.LadderID, &p.UserID, &p.Position, &p.Wins, &p.Losses, &p.JoinedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ladder player: %w", err)
+	}
+	return &p, nil
+}
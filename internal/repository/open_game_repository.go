@@ -0,0 +1,493 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/models"
+)
+
+type OpenGameRepository struct {
+	db *sql.DB
+}
+
+func NewOpenGameRepository() *OpenGameRepository {
+	return &OpenGameRepository{db: database.DB}
+}
+
+// openGameColumns selects open games aliased as g, with their approved and waitlisted counts
+const openGameColumns = `g.id, g.host_id, g.sport, g.starts_at, g.duration_minutes, g.venue_id, g.venue,
+	g.latitude, g.longitude, g.skill_levels, g.spots, g.note, g.status, g.created_at, g.updated_at,
+	(SELECT COUNT(*) FROM open_game_requests c WHERE c.game_id = g.id AND c.status = 'approved'),
+	(SELECT COUNT(*) FROM open_game_requests c WHERE c.game_id = g.id AND c.status = 'waitlisted')`
+
+const joinRequestColumns = `r.id, r.game_id, r.user_id, r.status, r.message, r.responded_at, r.created_at, r.updated_at`
+
+// scanOpenGame reads openGameColumns followed by any extra columns
+func scanOpenGame(row rowScanner, extra ...interface{}) (*models.OpenGame, error) {
+	var g models.OpenGame
+	dest := []interface{}{
+		&g.ID, &g.HostID, &g.Sport, &g.StartsAt, &g.DurationMinutes, &g.VenueID, &g.Venue,
+		&g.Latitude, &g.Longitude, &g.SkillLevels, &g.Spots, &g.Note, &g.Status, &g.CreatedAt, &g.UpdatedAt,
+		&g.Filled, &g.Waitlisted,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func scanJoinRequest(row rowScanner) (*models.JoinRequest, error) {
+	var r models.JoinRequest
+	err := row.Scan(&r.ID, &r.GameID, &r.UserID, &r.Status, &r.Message, &r.RespondedAt, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (r *OpenGameRepository) Create(game *models.OpenGame) error {
+	query := `
+		INSERT INTO open_games (host_id, sport, starts_at, duration_minutes, venue_id, venue, latitude, longitude,
+			skill_levels, spots, note, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query,
+		game.HostID, game.Sport, game.StartsAt, game.DurationMinutes, game.VenueID, game.Venue, game.Latitude,
+		game.Longitude, game.SkillLevels, game.Spots, game.Note, game.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create open game: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	game.ID = id
+	return nil
+}
+
+func (r *OpenGameRepository) GetByID(id int64) (*models.OpenGame, error) {
+	query := `SELECT ` + openGameColumns + ` FROM open_games g WHERE g.id = ?`
+
+	game, err := scanOpenGame(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get open game: %w", err)
+	}
+
+	return game, nil
+}
+
+// Cancel reports false if the game was not open
+func (r *OpenGameRepository) Cancel(id int64) (bool, error) {
+	result, err := r.db.Exec(`UPDATE open_games SET status = 'cancelled' WHERE id = ? AND status = 'open'`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel open game: %w", err)
+	}
+	return rowsChanged(result)
+}
+
+// GetFeed returns the open games starting in [from, to) that the viewer may join, soonest
+// first, with the distance from the filter's centre (or the viewer) when known. Hosts are
+// matched with the same discovery rules as the swipe deck, distances are measured to the game,
+// and the game's skill band must include the viewer's level. A non-zero gameID restricts the
+// feed to that game, to check whether the viewer can see it.
+func (r *OpenGameRepository) GetFeed(viewer *models.User, filter models.ProfileFilter, from, to time.Time, limit int, gameID int64) ([]models.OpenGame, error) {
+	conditions := []string{
+		"g.status = 'open'",
+		"g.starts_at >= ?",
+		"g.starts_at < ?",
+		"g.host_id != ?",
+		"(JSON_LENGTH(g.skill_levels) = 0 OR JSON_CONTAINS(g.skill_levels, JSON_QUOTE(?)))",
+	}
+	args := []interface{}{from, to, viewer.ID, viewer.SkillLevel}
+
+	if gameID != 0 {
+		conditions = append(conditions, "g.id = ?")
+		args = append(args, gameID)
+	}
+
+	// The sports filter also applies to the game itself, not just to what the host plays
+	if len(filter.Sports) > 0 {
+		conditions = append(conditions, "g.sport IN ("+placeholders(len(filter.Sports))+")")
+		for _, sport := range filter.Sports {
+			args = append(args, sport)
+		}
+	}
+
+	discovery, discoveryArgs := discoveryConditions(viewer, filter, "g")
+	conditions = append(conditions, discovery...)
+	args = append(args, discoveryArgs...)
+
+	latitude, longitude := filter.Latitude, filter.Longitude
+	if latitude == nil || longitude == nil {
+		latitude, longitude = viewer.Latitude, viewer.Longitude
+	}
+	distanceColumn := "NULL"
+	var selectArgs []interface{}
+	if latitude != nil && longitude != nil {
+		distanceColumn = distanceTo("g")
+		selectArgs = []interface{}{*latitude, *latitude, *longitude}
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, %s AS distance_km
+		FROM open_games g
+		JOIN users u ON u.id = g.host_id
+		LEFT JOIN discovery_preferences dp ON dp.user_id = u.id
+		WHERE %s
+		ORDER BY g.starts_at, g.id
+		LIMIT ?
+	`, openGameColumns, distanceColumn, strings.Join(conditions, " AND "))
+
+	args = append(append(selectArgs, args...), limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open games: %w", err)
+	}
+	defer rows.Close()
+
+	games := []models.OpenGame{}
+	for rows.Next() {
+		var distance *float64
+		game, err := scanOpenGame(rows, &distance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan open game: %w", err)
+		}
+		game.DistanceKm = distance
+		games = append(games, *game)
+	}
+
+	return games, nil
+}
+
+// GetForUser returns the games ending after since that the user hosts or has an active
+// request for, soonest first
+func (r *OpenGameRepository) GetForUser(userID int64, since time.Time) ([]models.OpenGame, error) {
+	query := `
+		SELECT ` + openGameColumns + `
+		FROM open_games g
+		WHERE (g.host_id = ? OR g.id IN (
+			SELECT game_id FROM open_game_requests WHERE user_id = ? AND status IN ('pending', 'approved', 'waitlisted')
+		))
+		AND g.starts_at + INTERVAL g.duration_minutes MINUTE > ?
+		ORDER BY g.starts_at, g.id
+	`
+
+	rows, err := r.db.Query(query, userID, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open games: %w", err)
+	}
+	defer rows.Close()
+
+	games := []models.OpenGame{}
+	for rows.Next() {
+		game, err := scanOpenGame(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan open game: %w", err)
+		}
+		games = append(games, *game)
+	}
+
+	return games, nil
+}
+
+// CreateRequest asks for a spot, reopening a request the user had withdrawn. It reports false
+// if the user already has a request that was not withdrawn.
+func (r *OpenGameRepository) CreateRequest(request *models.JoinRequest) (bool, error) {
+	query := `
+		INSERT INTO open_game_requests (game_id, user_id, status, message)
+		VALUES (?, ?, 'pending', ?)
+		ON DUPLICATE KEY UPDATE
+			message = IF(status = 'withdrawn', VALUES(message), message),
+			responded_at = IF(status = 'withdrawn', NULL, responded_at),
+			status = IF(status = 'withdrawn', 'pending', status)
+	`
+
+	result, err := r.db.Exec(query, request.GameID, request.UserID, request.Message)
+	if err != nil {
+		return false, fmt.Errorf("failed to create join request: %w", err)
+	}
+	// MySQL reports 1 row for an insert, 2 for an update and 0 when nothing changed
+	return rowsChanged(result)
+}
+
+func (r *OpenGameRepository) GetRequestByID(id int64) (*models.JoinRequest, error) {
+	query := `SELECT ` + joinRequestColumns + ` FROM open_game_requests r WHERE r.id = ?`
+
+	request, err := scanJoinRequest(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get join request: %w", err)
+	}
+
+	return request, nil
+}
+
+func (r *OpenGameRepository) GetRequest(gameID, userID int64) (*models.JoinRequest, error) {
+	query := `SELECT ` + joinRequestColumns + ` FROM open_game_requests r WHERE r.game_id = ? AND r.user_id = ?`
+
+	request, err := scanJoinRequest(r.db.QueryRow(query, gameID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get join request: %w", err)
+	}
+
+	return request, nil
+}
+
+// GetRequests returns a game's requests in the order they were made
+func (r *OpenGameRepository) GetRequests(gameID int64) ([]models.JoinRequest, error) {
+	query := `SELECT ` + joinRequestColumns + ` FROM open_game_requests r WHERE r.game_id = ? ORDER BY r.created_at, r.id`
+
+	rows, err := r.db.Query(query, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get join requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []models.JoinRequest{}
+	for rows.Next() {
+		request, err := scanJoinRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan join request: %w", err)
+		}
+		requests = append(requests, *request)
+	}
+
+	return requests, nil
+}
+
+// GetUserRequests returns the user's requests for the given games, by game ID
+func (r *OpenGameRepository) GetUserRequests(userID int64, gameIDs []int64) (map[int64]models.JoinRequest, error) {
+	requests := make(map[int64]models.JoinRequest)
+	if len(gameIDs) == 0 {
+		return requests, nil
+	}
+
+	query := `SELECT ` + joinRequestColumns + ` FROM open_game_requests r WHERE r.user_id = ? AND r.game_id IN (` +
+		placeholders(len(gameIDs)) + `)`
+	args := []interface{}{userID}
+	for _, id := range gameIDs {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get join requests: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		request, err := scanJoinRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan join request: %w", err)
+		}
+		requests[request.GameID] = *request
+	}
+
+	return requests, nil
+}
+
+// Approve gives a pending or waitlisted request a spot, or puts it on the waitlist when the
+// game is full. It returns the request's new status, or "" if it could not be approved.
+func (r *OpenGameRepository) Approve(requestID int64) (models.JoinRequestStatus, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	gameID, status, err := lockRequest(tx, requestID)
+	if err != nil || gameID == 0 {
+		return "", err
+	}
+	spotsLeft, err := lockSpots(tx, gameID)
+	if err != nil {
+		return "", err
+	}
+
+	next := models.JoinRequestWaitlisted
+	if spotsLeft > 0 {
+		next = models.JoinRequestApproved
+	}
+	switch {
+	case status == models.JoinRequestPending:
+	case status == models.JoinRequestWaitlisted && next == models.JoinRequestApproved:
+	default:
+		return "", nil
+	}
+
+	// The waitlist is ordered by the first response, so moving up keeps the original time
+	query := `UPDATE open_game_requests SET status = ?, responded_at = COALESCE(responded_at, CURRENT_TIMESTAMP) WHERE id = ?`
+	if _, err := tx.Exec(query, next, requestID); err != nil {
+		return "", fmt.Errorf("failed to approve join request: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit approval: %w", err)
+	}
+	return next, nil
+}
+
+// Release rejects or withdraws a request currently in one of the from statuses. When that
+// frees a spot, the head of the waitlist takes it. It reports whether the request changed and
+// the IDs of the promoted requests.
+func (r *OpenGameRepository) Release(requestID int64, status models.JoinRequestStatus, from ...models.JoinRequestStatus) (bool, []int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	gameID, current, err := lockRequest(tx, requestID)
+	if err != nil || gameID == 0 {
+		return false, nil, err
+	}
+	allowed := false
+	for _, s := range from {
+		allowed = allowed || s == current
+	}
+	if !allowed {
+		return false, nil, nil
+	}
+
+	query := `UPDATE open_game_requests SET status = ?, responded_at = COALESCE(responded_at, CURRENT_TIMESTAMP) WHERE id = ?`
+	if _, err := tx.Exec(query, status, requestID); err != nil {
+		return false, nil, fmt.Errorf("failed to update join request: %w", err)
+	}
+
+	var promoted []int64
+	if current == models.JoinRequestApproved {
+		if promoted, err = promoteWaitlist(tx, gameID); err != nil {
+			return false, nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, nil, fmt.Errorf("failed to commit join request: %w", err)
+	}
+	return true, promoted, nil
+}
+
+// SaveTeams stores the game's team split, replacing any earlier one
+func (r *OpenGameRepository) SaveTeams(split *models.TeamSplit) error {
+	query := `
+		INSERT INTO open_game_teams (game_id, teams, manual) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE teams = VALUES(teams), manual = VALUES(manual), updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := r.db.Exec(query, split.GameID, split.Assignment, split.Manual); err != nil {
+		return fmt.Errorf("failed to save teams: %w", err)
+	}
+	return nil
+}
+
+// GetTeams returns the game's team split, or nil if teams have not been picked
+func (r *OpenGameRepository) GetTeams(gameID int64) (*models.TeamSplit, error) {
+	query := `SELECT game_id, teams, manual, updated_at FROM open_game_teams WHERE game_id = ?`
+
+	var split models.TeamSplit
+	err := r.db.QueryRow(query, gameID).Scan(&split.GameID, &split.Assignment, &split.Manual, &split.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+
+	return &split, nil
+}
+
+// lockRequest locks a request row and returns its game and status, or a zero game ID if
+// there is no such request
+func lockRequest(tx *sql.Tx, requestID int64) (int64, models.JoinRequestStatus, error) {
+	var gameID int64
+	var status models.JoinRequestStatus
+	err := tx.QueryRow(`SELECT game_id, status FROM open_game_requests WHERE id = ? FOR UPDATE`, requestID).
+		Scan(&gameID, &status)
+	if err == sql.ErrNoRows {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to lock join request: %w", err)
+	}
+	return gameID, status, nil
+}
+
+// lockSpots locks the game row, serialising changes to its roster, and returns the spots left.
+// Games that are no longer open have none.
+func lockSpots(tx *sql.Tx, gameID int64) (int, error) {
+	var spots int
+	var status models.OpenGameStatus
+	err := tx.QueryRow(`SELECT spots, status FROM open_games WHERE id = ? FOR UPDATE`, gameID).Scan(&spots, &status)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock open game: %w", err)
+	}
+	if status != models.OpenGameStatusOpen {
+		return 0, nil
+	}
+
+	var filled int
+	query := `SELECT COUNT(*) FROM open_game_requests WHERE game_id = ? AND status = 'approved'`
+	if err := tx.QueryRow(query, gameID).Scan(&filled); err != nil {
+		return 0, fmt.Errorf("failed to count players: %w", err)
+	}
+	return spots - filled, nil
+}
+
+// promoteWaitlist fills the game's free spots from the waitlist, in waitlist order
+func promoteWaitlist(tx *sql.Tx, gameID int64) ([]int64, error) {
+	spotsLeft, err := lockSpots(tx, gameID)
+	if err != nil || spotsLeft <= 0 {
+		return nil, err
+	}
+
+	query := `
+		SELECT id FROM open_game_requests
+		WHERE game_id = ? AND status = 'waitlisted'
+		ORDER BY responded_at, id
+		LIMIT ?
+		FOR UPDATE
+	`
+	rows, err := tx.Query(query, gameID, spotsLeft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get waitlist: %w", err)
+	}
+	var promoted []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan waitlist: %w", err)
+		}
+		promoted = append(promoted, id)
+	}
+	rows.Close()
+
+	if len(promoted) == 0 {
+		return nil, nil
+	}
+	args := []interface{}{}
+	for _, id := range promoted {
+		args = append(args, id)
+	}
+	update := `UPDATE open_game_requests SET status = 'approved' WHERE id IN (` + placeholders(len(promoted)) + `)`
+	if _, err := tx.Exec(update, args...); err != nil {
+		return nil, fmt.Errorf("failed to promote waitlist: %w", err)
+	}
+	return promoted, nil
+}
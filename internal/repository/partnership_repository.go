@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/rating"
+)
+
+// PartnershipRepository stores doubles partnerships and their ratings
+type PartnershipRepository struct {
+	db *sql.DB
+}
+
+func NewPartnershipRepository() *PartnershipRepository {
+	return &PartnershipRepository{db: database.DB}
+}
+
+const partnershipColumns = `id, sport, user1_id, user2_id, proposed_by, status, rating, deviation, volatility,
+		games_played, last_played_at, formed_at, ended_at, created_at`
+
+func scanPartnership(row rowScanner) (*models.Partnership, error) {
+	var p models.Partnership
+	err := row.Scan(
+		&p.ID, &p.Sport, &p.User1ID, &p.User2ID, &p.ProposedBy, &p.Status, &p.Rating, &p.Deviation, &p.Volatility,
+		&p.GamesPlayed, &p.LastPlayedAt, &p.FormedAt, &p.EndedAt, &p.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	glicko := rating.Rating{Rating: p.Rating, Deviation: p.Deviation, Volatility: p.Volatility}
+	p.Confidence = glicko.Confidence()
+	p.Provisional = glicko.Provisional()
+	return &p, nil
+}
+
+// orderedPair returns the two user IDs lowest first, as partnerships store them
+func orderedPair(a, b int64) (int64, int64) {
+	if a > b {
+		return b, a
+	}
+	return a, b
+}
+
+// Propose stores a pending partnership between the two users in a sport, starting from the
+// given rating. A pair who declined or broke up before can propose again; a pair who had
+// played together keeps the rating they earned. It reports false if the pair is already
+// partners or has a proposal open.
+func (r *PartnershipRepository) Propose(p *models.Partnership) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	p.User1ID, p.User2ID = orderedPair(p.User1ID, p.User2ID)
+
+	var id int64
+	var status models.PartnershipStatus
+	query := `SELECT id, status FROM partnerships WHERE sport = ? AND user1_id = ? AND user2_id = ? FOR UPDATE`
+	err = tx.QueryRow(query, p.Sport, p.User1ID, p.User2ID).Scan(&id, &status)
+	switch {
+	case err == sql.ErrNoRows:
+		query = `
+			INSERT INTO partnerships (sport, user1_id, user2_id, proposed_by, rating, deviation, volatility,
+				seed_rating, seed_deviation, seed_volatility)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		result, err := tx.Exec(query, p.Sport, p.User1ID, p.User2ID, p.ProposedBy, p.Rating, p.Deviation, p.Volatility,
+			p.Rating, p.Deviation, p.Volatility)
+		if err != nil {
+			return false, fmt.Errorf("failed to create partnership: %w", err)
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return false, fmt.Errorf("failed to get last insert id: %w", err)
+		}
+	case err != nil:
+		return false, fmt.Errorf("failed to get partnership: %w", err)
+	case status == models.PartnershipStatusPending || status == models.PartnershipStatusActive:
+		return false, nil
+	default:
+		query = `
+			UPDATE partnerships SET proposed_by = ?, status = 'pending', formed_at = NULL, ended_at = NULL,
+				rating = IF(games_played = 0, ?, rating), deviation = IF(games_played = 0, ?, deviation),
+				volatility = IF(games_played = 0, ?, volatility),
+				seed_rating = IF(games_played = 0, ?, seed_rating), seed_deviation = IF(games_played = 0, ?, seed_deviation),
+				seed_volatility = IF(games_played = 0, ?, seed_volatility)
+			WHERE id = ?
+		`
+		_, err := tx.Exec(query, p.ProposedBy, p.Rating, p.Deviation, p.Volatility, p.Rating, p.Deviation, p.Volatility, id)
+		if err != nil {
+			return false, fmt.Errorf("failed to renew partnership: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit partnership: %w", err)
+	}
+
+	p.ID = id
+	return true, nil
+}
+
+func (r *PartnershipRepository) GetByID(id int64) (*models.Partnership, error) {
+	query := `SELECT ` + partnershipColumns + ` FROM partnerships WHERE id = ?`
+
+	p, err := scanPartnership(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get partnership: %w", err)
+	}
+
+	return p, nil
+}
+
+// GetForUser returns the user's active partnerships and open proposals, strongest first
+func (r *PartnershipRepository) GetForUser(userID int64) ([]models.Partnership, error) {
+	query := `
+		SELECT ` + partnershipColumns + `
+		FROM partnerships
+		WHERE (user1_id = ? OR user2_id = ?) AND status IN ('pending', 'active')
+		ORDER BY status = 'active' DESC, rating DESC, id DESC
+	`
+
+	rows, err := r.db.Query(query, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partnerships: %w", err)
+	}
+	defer rows.Close()
+
+	partnerships := []models.Partnership{}
+	for rows.Next() {
+		p, err := scanPartnership(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan partnership: %w", err)
+		}
+		partnerships = append(partnerships, *p)
+	}
+
+	return partnerships, rows.Err()
+}
+
+// Respond accepts or declines a proposal made to the user. It reports false if there is no
+// such open proposal.
+func (r *PartnershipRepository) Respond(id, userID int64, status models.PartnershipStatus) (bool, error) {
+	query := `
+		UPDATE partnerships SET status = ?, formed_at = IF(? = 'active', CURRENT_TIMESTAMP, NULL)
+		WHERE id = ? AND status = 'pending' AND proposed_by != ? AND (user1_id = ? OR user2_id = ?)
+	`
+
+	result, err := r.db.Exec(query, status, status, id, userID, userID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to answer partnership: %w", err)
+	}
+	return rowsChanged(result)
+}
+
+// Dissolve ends an active partnership, or withdraws the user's own proposal. It reports false
+// if there is nothing for the user to end.
+func (r *PartnershipRepository) Dissolve(id, userID int64) (bool, error) {
+	query := `
+		UPDATE partnerships SET status = 'dissolved', ended_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND (user1_id = ? OR user2_id = ?)
+			AND (status = 'active' OR (status = 'pending' AND proposed_by = ?))
+	`
+
+	result, err := r.db.Exec(query, id, userID, userID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to dissolve partnership: %w", err)
+	}
+	return rowsChanged(result)
+}
+
+// GetSeeded returns every partnership that has been formed, with its rating reset to the seed
+// it started from and no games played
+func (r *PartnershipRepository) GetSeeded() ([]models.Partnership, error) {
+	query := `
+		SELECT id, sport, user1_id, user2_id, status, seed_rating, seed_deviation, seed_volatility, formed_at, ended_at
+		FROM partnerships
+		WHERE formed_at IS NOT NULL
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partnerships: %w", err)
+	}
+	defer rows.Close()
+
+	partnerships := []models.Partnership{}
+	for rows.Next() {
+		var p models.Partnership
+		err := rows.Scan(&p.ID, &p.Sport, &p.User1ID, &p.User2ID, &p.Status, &p.Rating, &p.Deviation, &p.Volatility,
+			&p.FormedAt, &p.EndedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan partnership: %w", err)
+		}
+		partnerships = append(partnerships, p)
+	}
+
+	return partnerships, rows.Err()
+}
+
+// ReplaceRatings resets every partnership to its seed rating and then saves the given ratings,
+// in a single transaction
+func (r *PartnershipRepository) ReplaceRatings(partnerships []models.Partnership) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE partnerships SET rating = seed_rating, deviation = seed_deviation, volatility = seed_volatility,
+			games_played = 0, last_played_at = NULL
+	`
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("failed to reset partnership ratings: %w", err)
+	}
+	for i := range partnerships {
+		if err := savePartnershipRating(tx, &partnerships[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit partnership ratings: %w", err)
+	}
+
+	return nil
+}
+
+// lockPartnership reads the two users' active partnership in a sport, locking it until the
+// transaction ends. It returns nil if they are not partners.
+func lockPartnership(tx *sql.Tx, sport string, a, b int64) (*models.Partnership, error) {
+	user1, user2 := orderedPair(a, b)
+	query := `SELECT ` + partnershipColumns + ` FROM partnerships
+		WHERE sport = ? AND user1_id = ? AND user2_id = ? AND status = 'active' FOR UPDATE`
+
+	p, err := scanPartnership(tx.QueryRow(query, sport, user1, user2))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to lock partnership: %w", err)
+	}
+	return p, nil
+}
+
+func savePartnershipRating(tx *sql.Tx, p *models.Partnership) error {
+	query := `
+		UPDATE partnerships SET rating = ?, deviation = ?, volatility = ?, games_played = ?, last_played_at = ?
+		WHERE id = ?
+	`
+
+	if _, err := tx.Exec(query, p.Rating, p.Deviation, p.Volatility, p.GamesPlayed, p.LastPlayedAt, p.ID); err != nil {
+		return fmt.Errorf("failed to save partnership rating: %w", err)
+	}
+	return nil
+}
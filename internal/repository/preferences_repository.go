@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/models"
+)
+
+type PreferencesRepository struct {
+	db *sql.DB
+}
+
+func NewPreferencesRepository() *PreferencesRepository {
+	return &PreferencesRepository{db: database.DB}
+}
+
+func (r *PreferencesRepository) GetByUserID(userID int64) (*models.DiscoveryPreferences, error) {
+	query := `
+		SELECT user_id, min_age, max_age, max_distance_km, genders, sports, skill_levels,
+		       min_rating, max_rating, play_styles, updated_at
+		FROM discovery_preferences
+		WHERE user_id = ?
+	`
+
+	var prefs models.DiscoveryPreferences
+	err := r.db.QueryRow(query, userID).Scan(
+		&prefs.UserID, &prefs.MinAge, &prefs.MaxAge, &prefs.MaxDistanceKm, &prefs.Genders, &prefs.Sports,
+		&prefs.SkillLevels, &prefs.MinRating, &prefs.MaxRating, &prefs.PlayStyles, &prefs.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get discovery preferences: %w", err)
+	}
+
+	return &prefs, nil
+}
+
+func (r *PreferencesRepository) Upsert(prefs *models.DiscoveryPreferences) error {
+	query := `
+		INSERT INTO discovery_preferences (
+			user_id, min_age, max_age, max_distance_km, genders, sports, skill_levels,
+			min_rating, max_rating, play_styles
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			min_age = VALUES(min_age), max_age = VALUES(max_age), max_distance_km = VALUES(max_distance_km),
+			genders = VALUES(genders), sports = VALUES(sports), skill_levels = VALUES(skill_levels),
+			min_rating = VALUES(min_rating), max_rating = VALUES(max_rating), play_styles = VALUES(play_styles),
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := r.db.Exec(query,
+		prefs.UserID, prefs.MinAge, prefs.MaxAge, prefs.MaxDistanceKm, prefs.Genders, prefs.Sports,
+		prefs.SkillLevels, prefs.MinRating, prefs.MaxRating, prefs.PlayStyles,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save discovery preferences: %w", err)
+	}
+
+	return nil
+}
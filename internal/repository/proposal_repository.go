@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/models"
+)
+
+type ProposalRepository struct {
+	db *sql.DB
+}
+
+func NewProposalRepository() *ProposalRepository {
+	return &ProposalRepository{db: database.DB}
+}
+
+const proposalColumns = `p.id, p.match_id, p.proposed_by, p.sport, p.starts_at, p.duration_minutes, p.venue, p.venue_id, p.note,
+	p.status, p.counter_of, p.responded_at, p.sequence, p.created_at, p.updated_at`
+
+// scanProposal reads proposalColumns followed by any extra columns
+func scanProposal(row rowScanner, extra ...interface{}) (*models.GameProposal, error) {
+	var p models.GameProposal
+	dest := []interface{}{
+		&p.ID, &p.MatchID, &p.ProposedBy, &p.Sport, &p.StartsAt, &p.DurationMinutes, &p.Venue, &p.VenueID, &p.Note,
+		&p.Status, &p.CounterOf, &p.RespondedAt, &p.Sequence, &p.CreatedAt, &p.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	p.Conflicts = []models.ProposalConflict{}
+	return &p, nil
+}
+
+// Create stores a proposal and the chat message announcing it
+func (r *ProposalRepository) Create(proposal *models.GameProposal, message *models.Message) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertProposal(tx, proposal); err != nil {
+		return err
+	}
+	message.ProposalID = &proposal.ID
+	if err := insertMessage(tx, message); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit proposal: %w", err)
+	}
+	return nil
+}
+
+// Respond accepts or declines a pending proposal and records the response in the chat. It
+// reports false if the proposal was no longer pending.
+func (r *ProposalRepository) Respond(proposalID int64, status models.ProposalStatus, message *models.Message) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if responded, err := respondToProposal(tx, proposalID, status); err != nil || !responded {
+		return false, err
+	}
+	message.ProposalID = &proposalID
+	if err := insertMessage(tx, message); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit response: %w", err)
+	}
+	return true, nil
+}
+
+// Counter replaces a pending proposal with a counter-proposal and records it in the chat. It
+// reports false if the original was no longer pending.
+func (r *ProposalRepository) Counter(originalID int64, counter *models.GameProposal, message *models.Message) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if countered, err := respondToProposal(tx, originalID, models.ProposalStatusCountered); err != nil || !countered {
+		return false, err
+	}
+	if err := insertProposal(tx, counter); err != nil {
+		return false, err
+	}
+	message.ProposalID = &counter.ID
+	if err := insertMessage(tx, message); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit counter-proposal: %w", err)
+	}
+	return true, nil
+}
+
+// Cancel calls off a scheduled game and records it in the chat. It reports false if the game
+// was not scheduled.
+func (r *ProposalRepository) Cancel(proposalID int64, message *models.Message) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE game_proposals SET status = 'cancelled', sequence = sequence + 1 WHERE id = ? AND status = 'accepted'`
+	result, err := tx.Exec(query, proposalID)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel game: %w", err)
+	}
+	if cancelled, err := rowsChanged(result); err != nil || !cancelled {
+		return false, err
+	}
+	message.ProposalID = &proposalID
+	if err := insertMessage(tx, message); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit cancellation: %w", err)
+	}
+	return true, nil
+}
+
+// CancelUpcomingForMatch calls off the match's scheduled games that have not started yet
+func (r *ProposalRepository) CancelUpcomingForMatch(matchID int64, now time.Time) error {
+	query := `UPDATE game_proposals SET status = 'cancelled', sequence = sequence + 1 WHERE match_id = ? AND status = 'accepted' AND starts_at > ?`
+
+	if _, err := r.db.Exec(query, matchID, now); err != nil {
+		return fmt.Errorf("failed to cancel scheduled games: %w", err)
+	}
+	return nil
+}
+
+func (r *ProposalRepository) GetByID(id int64) (*models.GameProposal, error) {
+	query := `SELECT ` + proposalColumns + ` FROM game_proposals p WHERE p.id = ?`
+
+	proposal, err := scanProposal(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get proposal: %w", err)
+	}
+	return proposal, nil
+}
+
+// GetForMatch returns a match's proposals, newest first
+func (r *ProposalRepository) GetForMatch(matchID int64, limit int) ([]models.GameProposal, error) {
+	query := `SELECT ` + proposalColumns + ` FROM game_proposals p WHERE p.match_id = ? ORDER BY p.created_at DESC, p.id DESC LIMIT ?`
+
+	rows, err := r.db.Query(query, matchID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proposals: %w", err)
+	}
+	defer rows.Close()
+
+	proposals := []models.GameProposal{}
+	for rows.Next() {
+		proposal, err := scanProposal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan proposal: %w", err)
+		}
+		proposals = append(proposals, *proposal)
+	}
+
+	return proposals, rows.Err()
+}
+
+// GetScheduledOverlapping returns the IDs of the user's scheduled games, in any of their active
+// matches, that overlap [start, end). excludeID leaves out the proposal being checked.
+func (r *ProposalRepository) GetScheduledOverlapping(userID int64, start, end time.Time, excludeID int64) ([]int64, error) {
+	query := `
+		SELECT p.id
+		FROM game_proposals p
+		JOIN matches m ON m.id = p.match_id
+		WHERE (m.user1_id = ? OR m.user2_id = ?) AND m.status = 'active'
+			AND p.status = 'accepted' AND p.id <> ?
+			AND p.starts_at < ? AND DATE_ADD(p.starts_at, INTERVAL p.duration_minutes MINUTE) > ?
+		ORDER BY p.starts_at
+	`
+
+	rows, err := r.db.Query(query, userID, userID, excludeID, end, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled games: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled game: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetScheduledForUser returns the user's scheduled and cancelled games starting from since,
+// soonest first, each with the user's partner in it
+func (r *ProposalRepository) GetScheduledForUser(userID int64, since time.Time) ([]models.ScheduledGame, error) {
+	query := `
+		SELECT ` + proposalColumns + `,
+			CASE WHEN m.user1_id = ? THEN m.user2_id ELSE m.user1_id END
+		FROM game_proposals p
+		JOIN matches m ON m.id = p.match_id
+		WHERE (m.user1_id = ? OR m.user2_id = ?)
+			AND p.status IN ('accepted', 'cancelled') AND p.starts_at >= ?
+		ORDER BY p.starts_at
+	`
+
+	rows, err := r.db.Query(query, userID, userID, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled games: %w", err)
+	}
+	defer rows.Close()
+
+	games := []models.ScheduledGame{}
+	for rows.Next() {
+		var partnerID int64
+		proposal, err := scanProposal(rows, &partnerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled game: %w", err)
+		}
+		games = append(games, models.ScheduledGame{GameProposal: *proposal, PartnerID: partnerID})
+	}
+
+	return games, rows.Err()
+}
+
+func insertProposal(tx *sql.Tx, p *models.GameProposal) error {
+	query := `
+		INSERT INTO game_proposals (match_id, proposed_by, sport, starts_at, duration_minutes, venue, venue_id, note, status, counter_of)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := tx.Exec(query,
+		p.MatchID, p.ProposedBy, p.Sport, p.StartsAt, p.DurationMinutes, p.Venue, p.VenueID, p.Note, p.Status, p.CounterOf,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create proposal: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	p.ID = id
+	return nil
+}
+
+func respondToProposal(tx *sql.Tx, proposalID int64, status models.ProposalStatus) (bool, error) {
+	query := `UPDATE game_proposals SET status = ?, responded_at = CURRENT_TIMESTAMP WHERE id = ? AND status = 'pending'`
+
+	result, err := tx.Exec(query, status, proposalID)
+	if err != nil {
+		return false, fmt.Errorf("failed to respond to proposal: %w", err)
+	}
+	return rowsChanged(result)
+}
+
+func insertMessage(tx *sql.Tx, m *models.Message) error {
+	query := `INSERT INTO messages (match_id, sender_id, content, message_type, media_url, proposal_id) VALUES (?, ?, ?, ?, ?, ?)`
+
+	result, err := tx.Exec(query, m.MatchID, m.SenderID, m.Content, m.MessageType, m.MediaURL, m.ProposalID)
+	if err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	m.ID = id
+	m.CreatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/rating"
+)
+
+type RatingRepository struct {
+	db *sql.DB
+}
+
+func NewRatingRepository() *RatingRepository {
+	return &RatingRepository{db: database.DB}
+}
+
+const ratingColumns = `user_id, sport, format, rating, deviation, volatility, games_played, last_played_at, updated_at`
+
+// queryer is satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func scanRating(row rowScanner) (*models.SportRating, error) {
+	var r models.SportRating
+	err := row.Scan(
+		&r.UserID, &r.Sport, &r.Format, &r.Rating, &r.Deviation, &r.Volatility, &r.GamesPlayed,
+		&r.LastPlayedAt, &r.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	glicko := rating.Rating{Rating: r.Rating, Deviation: r.Deviation, Volatility: r.Volatility}
+	r.Confidence = glicko.Confidence()
+	r.Provisional = glicko.Provisional()
+	return &r, nil
+}
+
+// GetForUsers returns each user's ratings across sports, strongest first
+func (r *RatingRepository) GetForUsers(userIDs []int64) (map[int64][]models.SportRating, error) {
+	ratings := make(map[int64][]models.SportRating, len(userIDs))
+	if len(userIDs) == 0 {
+		return ratings, nil
+	}
+
+	args := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		args[i] = id
+	}
+	query := `SELECT ` + ratingColumns + ` FROM user_ratings WHERE user_id IN (` + placeholders(len(userIDs)) + `) ORDER BY rating DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ratings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		sportRating, err := scanRating(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rating: %w", err)
+		}
+		ratings[sportRating.UserID] = append(ratings[sportRating.UserID], *sportRating)
+	}
+
+	return ratings, rows.Err()
+}
+
+// GetRankable returns the ratings that belong on the leaderboards: established ratings of
+// players who played since activeSince, with each player's location
+func (r *RatingRepository) GetRankable(activeSince time.Time, maxDeviation float64) ([]models.RankedRating, error) {
+	query := `
+		SELECT ur.user_id, ur.sport, ur.format, ur.rating, ur.deviation, ur.volatility, ur.games_played,
+			ur.last_played_at, ur.updated_at, u.location
+		FROM user_ratings ur
+		JOIN users u ON u.id = ur.user_id
+		WHERE ur.last_played_at >= ? AND ur.deviation <= ?
+	`
+
+	rows, err := r.db.Query(query, activeSince, maxDeviation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rankable ratings: %w", err)
+	}
+	defer rows.Close()
+
+	var ratings []models.RankedRating
+	for rows.Next() {
+		var ranked models.RankedRating
+		if err := rows.Scan(
+			&ranked.UserID, &ranked.Sport, &ranked.Format, &ranked.Rating, &ranked.Deviation, &ranked.Volatility,
+			&ranked.GamesPlayed, &ranked.LastPlayedAt, &ranked.UpdatedAt, &ranked.Location,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan rating: %w", err)
+		}
+		ratings = append(ratings, ranked)
+	}
+
+	return ratings, rows.Err()
+}
+
+// ReplaceAll swaps every stored rating for the given ones in a single transaction
+func (r *RatingRepository) ReplaceAll(ratings []models.SportRating) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM user_ratings`); err != nil {
+		return fmt.Errorf("failed to clear ratings: %w", err)
+	}
+	for i := range ratings {
+		if err := saveRating(tx, &ratings[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit ratings: %w", err)
+	}
+
+	return nil
+}
+
+// lockRatings reads the users' ratings in a sport and format, locking the rows until the
+// transaction ends. Users without a rating yet are missing from the result.
+func lockRatings(tx *sql.Tx, sport string, format models.RatingFormat, userIDs []int64) (map[int64]models.SportRating, error) {
+	ratings := make(map[int64]models.SportRating, len(userIDs))
+	if len(userIDs) == 0 {
+		return ratings, nil
+	}
+
+	args := []interface{}{sport, format}
+	for _, id := range userIDs {
+		args = append(args, id)
+	}
+	query := `SELECT ` + ratingColumns + ` FROM user_ratings
+		WHERE sport = ? AND format = ? AND user_id IN (` + placeholders(len(userIDs)) + `) FOR UPDATE`
+
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock ratings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		sportRating, err := scanRating(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rating: %w", err)
+		}
+		ratings[sportRating.UserID] = *sportRating
+	}
+
+	return ratings, rows.Err()
+}
+
+func saveRating(tx *sql.Tx, r *models.SportRating) error {
+	query := `
+		INSERT INTO user_ratings (user_id, sport, format, rating, deviation, volatility, games_played, last_played_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			rating = VALUES(rating), deviation = VALUES(deviation), volatility = VALUES(volatility),
+			games_played = VALUES(games_played), last_played_at = VALUES(last_played_at)
+	`
+
+	_, err := tx.Exec(query, r.UserID, r.Sport, r.Format, r.Rating, r.Deviation, r.Volatility, r.GamesPlayed, r.LastPlayedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save rating: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/models"
+)
+
+type SportRepository struct {
+	db *sql.DB
+}
+
+func NewSportRepository() *SportRepository {
+	return &SportRepository{db: database.DB}
+}
+
+const sportColumns = `id, slug, name, display_names, team_size, rating_system, skill_levels, active, created_at, updated_at`
+
+func (r *SportRepository) Create(sport *models.Sport) error {
+	query := `
+		INSERT INTO sports (slug, name, display_names, team_size, rating_system, skill_levels, active)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query,
+		sport.Slug, sport.Name, sport.DisplayNames, sport.TeamSize, sport.RatingSystem, sport.SkillLevels, sport.Active,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create sport: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	sport.ID = id
+	return nil
+}
+
+func (r *SportRepository) GetBySlug(slug string) (*models.Sport, error) {
+	query := `SELECT ` + sportColumns + ` FROM sports WHERE slug = ?`
+
+	sport, err := scanSport(r.db.QueryRow(query, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get sport by slug: %w", err)
+	}
+
+	return sport, nil
+}
+
+// GetAll returns the catalog ordered by name. Inactive sports are only included when includeInactive is set.
+func (r *SportRepository) GetAll(includeInactive bool) ([]models.Sport, error) {
+	query := `SELECT ` + sportColumns + ` FROM sports`
+	if !includeInactive {
+		query += ` WHERE active = TRUE`
+	}
+	query += ` ORDER BY name`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sports: %w", err)
+	}
+	defer rows.Close()
+
+	var sports []models.Sport
+	for rows.Next() {
+		sport, err := scanSport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sport: %w", err)
+		}
+		sports = append(sports, *sport)
+	}
+
+	return sports, nil
+}
+
+func (r *SportRepository) Update(sport *models.Sport) error {
+	query := `
+		UPDATE sports SET
+			name = ?, display_names = ?, team_size = ?, rating_system = ?, skill_levels = ?, active = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+
+	_, err := r.db.Exec(query,
+		sport.Name, sport.DisplayNames, sport.TeamSize, sport.RatingSystem, sport.SkillLevels, sport.Active, sport.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update sport: %w", err)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSport(row rowScanner) (*models.Sport, error) {
+	var sport models.Sport
+	err := row.Scan(
+		&sport.ID, &sport.Slug, &sport.Name, &sport.DisplayNames, &sport.TeamSize, &sport.RatingSystem,
+		&sport.SkillLevels, &sport.Active, &sport.CreatedAt, &sport.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &sport, nil
+}
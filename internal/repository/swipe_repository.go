@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/models"
+)
+
+type SwipeRepository struct {
+	db *sql.DB
+}
+
+func NewSwipeRepository() *SwipeRepository {
+	return &SwipeRepository{db: database.DB}
+}
+
+func (r *SwipeRepository) CreateSwipe(swipe *models.Swipe) error {
+	query := `INSERT INTO swipes (swiper_id, swipee_id, direction) VALUES (?, ?, ?)`
+
+	result, err := r.db.Exec(query, swipe.SwiperID, swipe.SwipeeID, swipe.Direction)
+	if err != nil {
+		return fmt.Errorf("failed to create swipe: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	swipe.ID = id
+	return nil
+}
+
+func (r *SwipeRepository) GetSwipe(swiperID, swipeeID int64) (*models.Swipe, error) {
+	query := `SELECT id, swiper_id, swipee_id, direction, created_at FROM swipes WHERE swiper_id = ? AND swipee_id = ?`
+
+	var swipe models.Swipe
+	err := r.db.QueryRow(query, swiperID, swipeeID).Scan(
+		&swipe.ID, &swipe.SwiperID, &swipe.SwipeeID, &swipe.Direction, &swipe.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get swipe: %w", err)
+	}
+
+	return &swipe, nil
+}
+
+// CreateMatch stores a match with the lower user ID first so each pair is unique. If the pair
+// already has a match it is returned as it is, even if it has ended.
+func (r *SwipeRepository) CreateMatch(userAID, userBID int64) (*models.Match, error) {
+	user1ID, user2ID := userAID, userBID
+	if user1ID > user2ID {
+		user1ID, user2ID = user2ID, user1ID
+	}
+
+	existing, err := r.GetMatchBetween(user1ID, user2ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`INSERT INTO matches (user1_id, user2_id) VALUES (?, ?)`, user1ID, user2ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create match: %w", err)
+	}
+	matchID, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	if err := insertParticipants(tx, matchID, models.ParticipantRoleMember, user1ID, user2ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit match: %w", err)
+	}
+
+	return r.GetMatchBetween(user1ID, user2ID)
+}
+
+// matchColumns lists the matches columns in the order scanMatch reads them
+const matchColumns = `id, kind, user1_id, COALESCE(user2_id, 0), title, sport, status, ended_by, end_reason, ended_at, created_at`
+
+func scanMatch(row rowScanner) (*models.Match, error) {
+	var match models.Match
+	err := row.Scan(
+		&match.ID, &match.Kind, &match.User1ID, &match.User2ID, &match.Title, &match.Sport, &match.Status,
+		&match.EndedBy, &match.EndReason, &match.EndedAt, &match.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// GetMatchBetween finds the match for two users regardless of the order they were stored in
+func (r *SwipeRepository) GetMatchBetween(userAID, userBID int64) (*models.Match, error) {
+	query := `
+		SELECT ` + matchColumns + ` FROM matches
+		WHERE kind = 'direct' AND ((user1_id = ? AND user2_id = ?) OR (user1_id = ? AND user2_id = ?))
+		ORDER BY id
+		LIMIT 1
+	`
+
+	match, err := scanMatch(r.db.QueryRow(query, userAID, userBID, userBID, userAID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get match: %w", err)
+	}
+
+	return match, nil
+}
+
+// GetMatchByID returns the match whatever its status
+func (r *SwipeRepository) GetMatchByID(matchID int64) (*models.Match, error) {
+	query := `SELECT ` + matchColumns + ` FROM matches WHERE id = ?`
+
+	match, err := scanMatch(r.db.QueryRow(query, matchID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get match by id: %w", err)
+	}
+
+	return match, nil
+}
+
+// IsUserInMatch reports whether the user is an active participant of the match and it is
+// still active
+func (r *SwipeRepository) IsUserInMatch(userID, matchID int64) (bool, error) {
+	query := `
+		SELECT COUNT(*) FROM match_participants p
+		JOIN matches m ON m.id = p.match_id
+		WHERE p.match_id = ? AND p.user_id = ? AND p.status = 'active' AND m.status = 'active'
+	`
+
+	var count int
+	if err := r.db.QueryRow(query, matchID, userID).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check match membership: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// EndMatch marks an active match as ended. It returns false if the match was not active.
+func (r *SwipeRepository) EndMatch(matchID, endedBy int64, reason *models.EndReason) (bool, error) {
+	query := `
+		UPDATE matches SET status = 'ended', ended_by = ?, end_reason = ?, ended_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = 'active'
+	`
+
+	result, err := r.db.Exec(query, endedBy, reason, matchID)
+	if err != nil {
+		return false, fmt.Errorf("failed to end match: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to end match: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetMatchesForUser returns the user's active direct matches, newest first, with the other
+// user's profile. Groups are listed by GroupRepository.GetForUser.
+func (r *SwipeRepository) GetMatchesForUser(userID int64) ([]models.MatchResponse, error) {
+	query := fmt.Sprintf(`
+		SELECT %s, m.id, m.created_at
+		FROM matches m
+		JOIN users u ON u.id = IF(m.user1_id = ?, m.user2_id, m.user1_id)
+		WHERE (m.user1_id = ? OR m.user2_id = ?) AND m.kind = 'direct' AND m.status = 'active'
+		ORDER BY m.created_at DESC, m.id DESC
+	`, profileColumns)
+
+	rows, err := r.db.Query(query, userID, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matches: %w", err)
+	}
+	defer rows.Close()
+
+	matches := []models.MatchResponse{}
+	seen := make(map[int64]bool)
+	for rows.Next() {
+		var match models.MatchResponse
+		profile, err := scanProfile(rows, &match.ID, &match.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan match: %w", err)
+		}
+		// Older data may hold the same pair twice in opposite order
+		if seen[profile.ID] {
+			continue
+		}
+		seen[profile.ID] = true
+		match.User = *profile
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
+
+// GetLatestSwipe returns the user's most recent swipe, or nil if they have never swiped
+func (r *SwipeRepository) GetLatestSwipe(swiperID int64) (*models.Swipe, error) {
+	query := `
+		SELECT id, swiper_id, swipee_id, direction, created_at FROM swipes
+		WHERE swiper_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+
+	var swipe models.Swipe
+	err := r.db.QueryRow(query, swiperID).Scan(
+		&swipe.ID, &swipe.SwiperID, &swipe.SwipeeID, &swipe.Direction, &swipe.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest swipe: %w", err)
+	}
+
+	return &swipe, nil
+}
+
+// CountMessages returns how many messages have been sent in the match
+func (r *SwipeRepository) CountMessages(matchID int64) (int, error) {
+	query := `SELECT COUNT(*) FROM messages WHERE match_id = ?`
+
+	var count int
+	if err := r.db.QueryRow(query, matchID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	return count, nil
+}
+
+// UndoSwipe deletes a swipe and, when matchID is non-zero, the match that depended on it.
+// The match is only deleted while it has no messages, so a conversation is never lost.
+func (r *SwipeRepository) UndoSwipe(swipeID, matchID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if matchID != 0 {
+		query := `DELETE FROM matches WHERE id = ? AND NOT EXISTS (SELECT 1 FROM messages WHERE match_id = ?)`
+		result, err := tx.Exec(query, matchID, matchID)
+		if err != nil {
+			return fmt.Errorf("failed to delete match: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return fmt.Errorf("match already has messages")
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM swipes WHERE id = ?`, swipeID); err != nil {
+		return fmt.Errorf("failed to delete swipe: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit undo: %w", err)
+	}
+
+	return nil
+}
+
+// GetReceivedLikes returns up to limit likes the user has not answered yet, newest first.
+// Only likes with an ID below beforeID are returned when it is non-zero.
+func (r *SwipeRepository) GetReceivedLikes(userID, beforeID int64, limit int) ([]models.ReceivedLike, []int64, error) {
+	query := fmt.Sprintf(`
+		SELECT %s, s.id, s.direction, s.created_at
+		FROM swipes s
+		JOIN users u ON u.id = s.swiper_id
+		WHERE s.swipee_id = ? AND s.direction IN ('right', 'super')
+			AND NOT EXISTS (SELECT 1 FROM swipes mine WHERE mine.swiper_id = ? AND mine.swipee_id = s.swiper_id)
+			AND (? = 0 OR s.id < ?)
+		ORDER BY s.id DESC
+		LIMIT ?
+	`, profileColumns)
+
+	rows, err := r.db.Query(query, userID, userID, beforeID, beforeID, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get received likes: %w", err)
+	}
+	defer rows.Close()
+
+	likes := []models.ReceivedLike{}
+	var swipeIDs []int64
+	for rows.Next() {
+		var like models.ReceivedLike
+		var swipeID int64
+		var direction models.SwipeDirection
+		profile, err := scanProfile(rows, &swipeID, &direction, &like.LikedAt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan received like: %w", err)
+		}
+		like.Profile = *profile
+		like.SuperLike = direction == models.SwipeDirectionSuper
+		likes = append(likes, like)
+		swipeIDs = append(swipeIDs, swipeID)
+	}
+
+	return likes, swipeIDs, nil
+}
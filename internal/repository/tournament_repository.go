@@ -0,0 +1,343 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/models"
+)
+
+type TournamentRepository struct {
+	db *sql.DB
+}
+
+func NewTournamentRepository() *TournamentRepository {
+	return &TournamentRepository{db: database.DB}
+}
+
+// tournamentColumns selects tournaments aliased as t, with their number of registered players
+const tournamentColumns = `t.id, t.organizer_id, t.group_id, t.name, t.sport, t.format, t.status, t.max_entrants,
+	t.starts_at, t.venue, t.note, t.winner_id, t.started_at, t.completed_at, t.created_at, t.updated_at, t.bracket,
+	(SELECT COUNT(*) FROM tournament_entries e WHERE e.tournament_id = t.id AND e.status = 'registered')`
+
+func scanTournament(row rowScanner) (*models.Tournament, error) {
+	var t models.Tournament
+	err := row.Scan(
+		&t.ID, &t.OrganizerID, &t.GroupID, &t.Name, &t.Sport, &t.Format, &t.Status, &t.MaxEntrants,
+		&t.StartsAt, &t.Venue, &t.Note, &t.WinnerID, &t.StartedAt, &t.CompletedAt, &t.CreatedAt, &t.UpdatedAt, &t.State,
+		&t.Entrants,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TournamentRepository) Create(t *models.Tournament) error {
+	query := `
+		INSERT INTO tournaments (organizer_id, group_id, name, sport, format, status, max_entrants, starts_at, venue, note)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query,
+		t.OrganizerID, t.GroupID, t.Name, t.Sport, t.Format, t.Status, t.MaxEntrants, t.StartsAt, t.Venue, t.Note,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create tournament: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	t.ID = id
+	return nil
+}
+
+func (r *TournamentRepository) GetByID(id int64) (*models.Tournament, error) {
+	query := `SELECT ` + tournamentColumns + ` FROM tournaments t WHERE t.id = ?`
+
+	t, err := scanTournament(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get tournament: %w", err)
+	}
+
+	return t, nil
+}
+
+// List returns the tournaments open for registration or under way, soonest first: a group's
+// when groupID is set, otherwise the public ones. An empty sport means any.
+func (r *TournamentRepository) List(sport string, groupID *int64, limit int) ([]models.Tournament, error) {
+	query := `SELECT ` + tournamentColumns + ` FROM tournaments t
+		WHERE t.status IN ('registration', 'in_progress') AND (? = '' OR t.sport = ?)`
+	args := []interface{}{sport, sport}
+	if groupID != nil {
+		query += ` AND t.group_id = ?`
+		args = append(args, *groupID)
+	} else {
+		query += ` AND t.group_id IS NULL`
+	}
+	query += ` ORDER BY t.starts_at, t.id LIMIT ?`
+	args = append(args, limit)
+
+	return r.query(query, args...)
+}
+
+// GetForUser returns the tournaments the user organizes or is entered in, newest first
+func (r *TournamentRepository) GetForUser(userID int64) ([]models.Tournament, error) {
+	query := `SELECT ` + tournamentColumns + ` FROM tournaments t
+		WHERE t.organizer_id = ?
+			OR t.id IN (SELECT tournament_id FROM tournament_entries WHERE user_id = ? AND status = 'registered')
+		ORDER BY t.starts_at DESC, t.id DESC`
+
+	return r.query(query, userID, userID)
+}
+
+func (r *TournamentRepository) query(query string, args ...interface{}) ([]models.Tournament, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournaments: %w", err)
+	}
+	defer rows.Close()
+
+	tournaments := []models.Tournament{}
+	for rows.Next() {
+		t, err := scanTournament(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tournament: %w", err)
+		}
+		tournaments = append(tournaments, *t)
+	}
+
+	return tournaments, rows.Err()
+}
+
+// GetEntries returns the registered players with their profiles, by seed once the tournament
+// has started and in order of registration before that
+func (r *TournamentRepository) GetEntries(tournamentID int64) ([]models.TournamentEntry, error) {
+	query := fmt.Sprintf(`
+		SELECT %s, e.tournament_id, e.user_id, e.status, e.seed, e.rating, e.registered_at
+		FROM tournament_entries e
+		JOIN users u ON u.id = e.user_id
+		WHERE e.tournament_id = ? AND e.status = 'registered'
+		ORDER BY e.seed IS NULL, e.seed, e.registered_at, e.user_id
+	`, profileColumns)
+
+	rows, err := r.db.Query(query, tournamentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tournament entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.TournamentEntry{}
+	for rows.Next() {
+		var e models.TournamentEntry
+		profile, err := scanProfile(rows, &e.TournamentID, &e.UserID, &e.Status, &e.Seed, &e.Rating, &e.RegisteredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tournament entry: %w", err)
+		}
+		e.User = profile
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// Register enters the user in a tournament that is still taking registrations. Players who
+// withdrew can enter again. It reports false if registration is closed or the field is full.
+func (r *TournamentRepository) Register(tournamentID, userID int64) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status models.TournamentStatus
+	var maxEntrants int
+	query := `SELECT status, max_entrants FROM tournaments WHERE id = ? FOR UPDATE`
+	if err := tx.QueryRow(query, tournamentID).Scan(&status, &maxEntrants); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to lock tournament: %w", err)
+	}
+	if status != models.TournamentStatusRegistration {
+		return false, nil
+	}
+
+	var entrants int
+	query = `SELECT COUNT(*) FROM tournament_entries WHERE tournament_id = ? AND status = 'registered'`
+	if err := tx.QueryRow(query, tournamentID).Scan(&entrants); err != nil {
+		return false, fmt.Errorf("failed to count entrants: %w", err)
+	}
+	if entrants >= maxEntrants {
+		return false, nil
+	}
+
+	query = `
+		INSERT INTO tournament_entries (tournament_id, user_id) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE status = 'registered', registered_at = CURRENT_TIMESTAMP
+	`
+	if _, err := tx.Exec(query, tournamentID, userID); err != nil {
+		return false, fmt.Errorf("failed to register for tournament: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit registration: %w", err)
+	}
+
+	return true, nil
+}
+
+// Withdraw takes the user out of a tournament that has not started. It reports false if the
+// user was not entered or the tournament is under way.
+func (r *TournamentRepository) Withdraw(tournamentID, userID int64) (bool, error) {
+	query := `
+		UPDATE tournament_entries e
+		JOIN tournaments t ON t.id = e.tournament_id
+		SET e.status = 'withdrawn'
+		WHERE e.tournament_id = ? AND e.user_id = ? AND e.status = 'registered' AND t.status = 'registration'
+	`
+
+	result, err := r.db.Exec(query, tournamentID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to withdraw from tournament: %w", err)
+	}
+	return rowsChanged(result)
+}
+
+// Start closes registration, stores the seeds and the first bracket. The tournament row is
+// locked first, so the seeded entries can be checked against the registered ones. It reports
+// false if the tournament had already started or been cancelled, and fails if a player
+// registered or withdrew since the entries were seeded.
+func (r *TournamentRepository) Start(tournamentID int64, entries []models.TournamentEntry, state models.BracketState) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status models.TournamentStatus
+	query := `SELECT status FROM tournaments WHERE id = ? FOR UPDATE`
+	if err := tx.QueryRow(query, tournamentID).Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to lock tournament: %w", err)
+	}
+	if status != models.TournamentStatusRegistration {
+		return false, nil
+	}
+
+	seeded := make(map[int64]bool, len(entries))
+	for _, e := range entries {
+		seeded[e.UserID] = true
+	}
+	query = `SELECT user_id FROM tournament_entries WHERE tournament_id = ? AND status = 'registered'`
+	rows, err := tx.Query(query, tournamentID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get entrants: %w", err)
+	}
+	registered := 0
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return false, fmt.Errorf("failed to scan entrant: %w", err)
+		}
+		if !seeded[userID] {
+			rows.Close()
+			return false, fmt.Errorf("the entrants changed while the tournament was starting, try again")
+		}
+		registered++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to get entrants: %w", err)
+	}
+	if registered != len(entries) {
+		return false, fmt.Errorf("the entrants changed while the tournament was starting, try again")
+	}
+
+	query = `
+		UPDATE tournaments SET status = 'in_progress', bracket = ?, started_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+	if _, err := tx.Exec(query, state, tournamentID); err != nil {
+		return false, fmt.Errorf("failed to start tournament: %w", err)
+	}
+
+	query = `UPDATE tournament_entries SET seed = ?, rating = ? WHERE tournament_id = ? AND user_id = ?`
+	for _, e := range entries {
+		if _, err := tx.Exec(query, e.Seed, e.Rating, tournamentID, e.UserID); err != nil {
+			return false, fmt.Errorf("failed to seed entrant: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit tournament start: %w", err)
+	}
+
+	return true, nil
+}
+
+// BracketFunc changes a tournament's bracket state, and its status and winner when it ends
+type BracketFunc func(t *models.Tournament) error
+
+// UpdateBracket applies update to a tournament under way, with the row locked so results
+// reported at the same time are applied one after the other. It reports false if the
+// tournament is not under way; errors returned by update are passed back unchanged.
+func (r *TournamentRepository) UpdateBracket(tournamentID int64, update BracketFunc) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + tournamentColumns + ` FROM tournaments t WHERE t.id = ? FOR UPDATE`
+	t, err := scanTournament(tx.QueryRow(query, tournamentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to lock tournament: %w", err)
+	}
+	if t.Status != models.TournamentStatusInProgress {
+		return false, nil
+	}
+
+	if err := update(t); err != nil {
+		return false, err
+	}
+
+	query = `
+		UPDATE tournaments SET bracket = ?, status = ?, winner_id = ?,
+			completed_at = IF(? = 'completed', CURRENT_TIMESTAMP, NULL)
+		WHERE id = ?
+	`
+	if _, err := tx.Exec(query, t.State, t.Status, t.WinnerID, t.Status, tournamentID); err != nil {
+		return false, fmt.Errorf("failed to update bracket: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit bracket: %w", err)
+	}
+
+	return true, nil
+}
+
+// Cancel reports false if the tournament had already finished or been cancelled
+func (r *TournamentRepository) Cancel(id int64) (bool, error) {
+	query := `UPDATE tournaments SET status = 'cancelled' WHERE id = ? AND status IN ('registration', 'in_progress')`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel tournament: %w", err)
+	}
+	return rowsChanged(result)
+}
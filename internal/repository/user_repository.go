@@ -2,10 +2,12 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/geo"
 	"swipe-sports-backend/internal/models"
 )
 
@@ -45,15 +47,21 @@ func (r *UserRepository) Create(user *models.User) error {
 	return nil
 }
 
+// userColumns lists the users columns in the order GetByID and GetByOAuthID scan them
+const userColumns = `id, oauth_id, oauth_provider, name, first_name, last_name, age, email, gender, location,
+		latitude, longitude, rank, profile_pic_url, bio, sport_preferences, skill_level, ntrp_rating, play_style,
+		preferred_timeslots, availability, partner_sports, created_at, updated_at, last_active_at, plan`
+
 func (r *UserRepository) GetByID(id int64) (*models.User, error) {
-	query := `SELECT * FROM users WHERE id = ?`
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ?`
 	
 	var user models.User
 	err := r.db.QueryRow(query, id).Scan(
 		&user.ID, &user.OAuthID, &user.OAuthProvider, &user.Name, &user.FirstName, &user.LastName, &user.Age, &user.Email,
 		&user.Gender, &user.Location, &user.Latitude, &user.Longitude, &user.Rank,
 		&user.ProfilePicURL, &user.Bio, &user.SportPreferences, &user.SkillLevel,
-		&user.NTRPRating, &user.PlayStyle, &user.PreferredTimeslots, &user.Availability, &user.CreatedAt, &user.UpdatedAt,
+		&user.NTRPRating, &user.PlayStyle, &user.PreferredTimeslots, &user.Availability, &user.PartnerSports, &user.CreatedAt, &user.UpdatedAt,
+		&user.LastActiveAt, &user.Plan,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -66,14 +74,15 @@ func (r *UserRepository) GetByID(id int64) (*models.User, error) {
 }
 
 func (r *UserRepository) GetByOAuthID(oauthID, provider string) (*models.User, error) {
-	query := `SELECT * FROM users WHERE oauth_id = ? AND oauth_provider = ?`
+	query := `SELECT ` + userColumns + ` FROM users WHERE oauth_id = ? AND oauth_provider = ?`
 	
 	var user models.User
 	err := r.db.QueryRow(query, oauthID, provider).Scan(
 		&user.ID, &user.OAuthID, &user.OAuthProvider, &user.Name, &user.FirstName, &user.LastName, &user.Age, &user.Email,
 		&user.Gender, &user.Location, &user.Latitude, &user.Longitude, &user.Rank,
 		&user.ProfilePicURL, &user.Bio, &user.SportPreferences, &user.SkillLevel,
-		&user.NTRPRating, &user.PlayStyle, &user.PreferredTimeslots, &user.Availability, &user.CreatedAt, &user.UpdatedAt,
+		&user.NTRPRating, &user.PlayStyle, &user.PreferredTimeslots, &user.Availability, &user.PartnerSports, &user.CreatedAt, &user.UpdatedAt,
+		&user.LastActiveAt, &user.Plan,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -108,89 +117,293 @@ func (r *UserRepository) Update(user *models.User) error {
 	return nil
 }
 
-func (r *UserRepository) GetProfilesForSwipe(userID int64, filter models.ProfileFilter) ([]models.UserProfile, error) {
+// TouchLastActive records that the user was just active
+func (r *UserRepository) TouchLastActive(id int64) error {
+	query := `UPDATE users SET last_active_at = CURRENT_TIMESTAMP WHERE id = ?`
+
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to update last active: %w", err)
+	}
+
+	return nil
+}
+
+// GetProfilesForSwipe returns up to poolSize candidates for the viewer's deck, most recently
+// active first, for the ranking step to score. The filter should already have the viewer's
+// stored discovery preferences merged in (see ProfileFilter.WithDefaults); on top of that,
+// candidates whose own preferences would exclude the viewer are left out.
+func (r *UserRepository) GetProfilesForSwipe(viewer *models.User, filter models.ProfileFilter, poolSize int) ([]models.SwipeCandidate, error) {
 	var conditions []string
 	var args []interface{}
 
 	// Base condition: exclude users already swiped by current user
-	conditions = append(conditions, "id NOT IN (SELECT swipee_id FROM swipes WHERE swiper_id = ?)")
-	args = append(args, userID)
+	conditions = append(conditions, "u.id NOT IN (SELECT swipee_id FROM swipes WHERE swiper_id = ?)")
+	args = append(args, viewer.ID)
 
 	// Exclude current user
-	conditions = append(conditions, "id != ?")
-	args = append(args, userID)
+	conditions = append(conditions, "u.id != ?")
+	args = append(args, viewer.ID)
 
-	// Add filter conditions
-	if filter.Gender != nil {
-		conditions = append(conditions, "gender = ?")
-		args = append(args, *filter.Gender)
+	discovery, discoveryArgs := discoveryConditions(viewer, filter, "u")
+	conditions = append(conditions, discovery...)
+	args = append(args, discoveryArgs...)
+
+	// Partner mode only shows players looking for a partner in the sport
+	if filter.PartnerSport != "" {
+		conditions = append(conditions, "JSON_CONTAINS(u.partner_sports, JSON_QUOTE(?))")
+		args = append(args, filter.PartnerSport)
 	}
 
-	if filter.Location != nil {
-		conditions = append(conditions, "location = ?")
-		args = append(args, *filter.Location)
+	latitude, longitude := filter.Latitude, filter.Longitude
+	if latitude == nil || longitude == nil {
+		latitude, longitude = viewer.Latitude, viewer.Longitude
 	}
 
-	if filter.MinRank != nil {
-		conditions = append(conditions, "rank >= ?")
-		args = append(args, *filter.MinRank)
+	// Distance from the viewer, when known, is returned for scoring
+	distanceColumn := "NULL"
+	var selectArgs []interface{}
+	if latitude != nil && longitude != nil {
+		distanceColumn = distanceSQL
+		selectArgs = []interface{}{*latitude, *latitude, *longitude}
 	}
+	selectArgs = append(selectArgs, viewer.ID)
 
-	if filter.MaxRank != nil {
-		conditions = append(conditions, "rank <= ?")
-		args = append(args, *filter.MaxRank)
+	// Candidates who super-liked the viewer always make it into the pool
+	query := fmt.Sprintf(`
+		SELECT %s, %s AS distance_km, u.last_active_at,
+			EXISTS (SELECT 1 FROM swipes sl WHERE sl.swiper_id = u.id AND sl.swipee_id = ? AND sl.direction = 'super') AS super_liked
+		FROM users u
+		LEFT JOIN discovery_preferences dp ON dp.user_id = u.id
+		WHERE %s
+		ORDER BY super_liked DESC, u.last_active_at IS NULL, u.last_active_at DESC, u.id DESC
+		LIMIT ?
+	`, profileColumns, distanceColumn, strings.Join(conditions, " AND "))
+
+	args = append(append(selectArgs, args...), poolSize)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profiles for swipe: %w", err)
 	}
+	defer rows.Close()
 
-	// Distance-based filtering (simplified - in production use PostGIS)
-	if filter.Latitude != nil && filter.Longitude != nil && filter.Radius != nil {
-		// This is a simplified distance calculation
-		// In production, use PostGIS for proper geospatial queries
-		conditions = append(conditions, `
-			(6371 * acos(cos(radians(?)) * cos(radians(latitude)) * 
-			cos(radians(longitude) - radians(?)) + sin(radians(?)) * 
-			sin(radians(latitude)))) <= ?
-		`)
-		args = append(args, *filter.Latitude, *filter.Longitude, *filter.Latitude, *filter.Radius)
+	var candidates []models.SwipeCandidate
+	for rows.Next() {
+		var candidate models.SwipeCandidate
+		var superLiked bool
+		profile, err := scanProfile(rows, &candidate.DistanceKm, &candidate.LastActiveAt, &superLiked)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan profile: %w", err)
+		}
+		if candidate.DistanceKm != nil {
+			rounded := geo.RoundDistance(*candidate.DistanceKm)
+			profile.DistanceKm = &rounded
+		}
+		profile.SuperLiked = superLiked
+		candidate.Profile = *profile
+		candidates = append(candidates, candidate)
 	}
 
-	// Set default limit if not provided
-	if filter.Limit <= 0 {
-		filter.Limit = 20
+	return candidates, nil
+}
+
+// GetProfileByID returns the public profile of a user
+func (r *UserRepository) GetProfileByID(id int64) (*models.UserProfile, error) {
+	query := fmt.Sprintf(`SELECT %s FROM users u WHERE u.id = ?`, profileColumns)
+
+	profile, err := scanProfile(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get profile by id: %w", err)
 	}
 
-	query := fmt.Sprintf(`
-		SELECT id, name, age, gender, location, rank, profile_pic_url, bio, 
-		       sport_preferences, skill_level, ntrp_rating, play_style, preferred_timeslots, availability, created_at
-		FROM users 
-		WHERE %s
-		ORDER BY RAND()
-		LIMIT ? OFFSET ?
-	`, strings.Join(conditions, " AND "))
+	return profile, nil
+}
 
-	args = append(args, filter.Limit, filter.Offset)
+// GetProfilesByIDs returns the public profiles of the given users, keyed by ID. Unknown IDs
+// are missing from the result.
+func (r *UserRepository) GetProfilesByIDs(ids []int64) (map[int64]*models.UserProfile, error) {
+	profiles := make(map[int64]*models.UserProfile, len(ids))
+	if len(ids) == 0 {
+		return profiles, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	query := fmt.Sprintf(`SELECT %s FROM users u WHERE u.id IN (%s)`, profileColumns, placeholders(len(ids)))
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get profiles for swipe: %w", err)
+		return nil, fmt.Errorf("failed to get profiles: %w", err)
 	}
 	defer rows.Close()
 
-	var profiles []models.UserProfile
 	for rows.Next() {
-		var profile models.UserProfile
-		err := rows.Scan(
-			&profile.ID, &profile.Name, &profile.Age, &profile.Gender, &profile.Location,
-			&profile.Rank, &profile.ProfilePicURL, &profile.Bio,
-			&profile.SportPreferences, &profile.SkillLevel, &profile.NTRPRating, &profile.PlayStyle, &profile.PreferredTimeslots,
-			&profile.Availability, &profile.CreatedAt,
-		)
+		profile, err := scanProfile(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan profile: %w", err)
 		}
-		profiles = append(profiles, profile)
+		profiles[profile.ID] = profile
 	}
 
-	return profiles, nil
+	return profiles, rows.Err()
+}
+
+// discoveryConditions applies the viewer's discovery filter to users aliased as u, and requires
+// those users' own preferences, joined as dp, to include the viewer. Distances are measured to
+// the coordinates of the table aliased as at: u itself for profiles, or what the user offers,
+// such as an open game.
+func discoveryConditions(viewer *models.User, filter models.ProfileFilter, at string) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if len(filter.Genders) > 0 {
+		conditions = append(conditions, "u.gender IN ("+placeholders(len(filter.Genders))+")")
+		for _, gender := range filter.Genders {
+			args = append(args, gender)
+		}
+	}
+
+	if filter.Location != nil {
+		conditions = append(conditions, "u.location = ?")
+		args = append(args, *filter.Location)
+	}
+
+	if filter.MinAge != nil {
+		conditions = append(conditions, "u.age >= ?")
+		args = append(args, *filter.MinAge)
+	}
+
+	if filter.MaxAge != nil {
+		conditions = append(conditions, "u.age <= ?")
+		args = append(args, *filter.MaxAge)
+	}
+
+	if filter.MinRank != nil {
+		conditions = append(conditions, "u.rank >= ?")
+		args = append(args, *filter.MinRank)
+	}
+
+	if filter.MaxRank != nil {
+		conditions = append(conditions, "u.rank <= ?")
+		args = append(args, *filter.MaxRank)
+	}
+
+	if len(filter.Sports) > 0 {
+		var sportConditions []string
+		for _, sport := range filter.Sports {
+			sportConditions = append(sportConditions, "JSON_CONTAINS(u.sport_preferences, 'true', ?)")
+			args = append(args, sportJSONPath(sport))
+		}
+		conditions = append(conditions, "("+strings.Join(sportConditions, " OR ")+")")
+	}
+
+	if len(filter.SkillLevels) > 0 {
+		conditions = append(conditions, "u.skill_level IN ("+placeholders(len(filter.SkillLevels))+")")
+		for _, level := range filter.SkillLevels {
+			args = append(args, level)
+		}
+	}
+
+	if len(filter.PlayStyles) > 0 {
+		conditions = append(conditions, "u.play_style IN ("+placeholders(len(filter.PlayStyles))+")")
+		for _, style := range filter.PlayStyles {
+			args = append(args, style)
+		}
+	}
+
+	// Distance-based filtering, centred on the viewer unless the request gives coordinates
+	latitude, longitude := filter.Latitude, filter.Longitude
+	if latitude == nil || longitude == nil {
+		latitude, longitude = viewer.Latitude, viewer.Longitude
+	}
+	if latitude != nil && longitude != nil && filter.Radius != nil {
+		lat, lng, radius := *latitude, *longitude, *filter.Radius
+
+		// Narrow to the geohash cells around the centre (range scans on idx_geohash) and the
+		// bounding box before computing exact distances
+		if prefixes := geo.CoveringPrefixes(lat, lng, radius); prefixes != nil {
+			prefixConditions := make([]string, len(prefixes))
+			for i, prefix := range prefixes {
+				prefixConditions[i] = at + ".geohash LIKE ?"
+				args = append(args, prefix+"%")
+			}
+			conditions = append(conditions, "("+strings.Join(prefixConditions, " OR ")+")")
+		}
+
+		minLat, maxLat, minLng, maxLng := geo.BoundingBox(lat, lng, radius)
+		conditions = append(conditions,
+			at+".latitude BETWEEN ? AND ?",
+			at+".longitude BETWEEN ? AND ?",
+			distanceTo(at)+" <= ?",
+		)
+		args = append(args, minLat, maxLat, minLng, maxLng, lat, lat, lng, radius)
+	}
+
+	// Mutual preferences: the candidate's stored settings must include the viewer.
+	// Unknown viewer attributes never satisfy a restriction the candidate has set.
+	conditions = append(conditions,
+		"(dp.min_age IS NULL OR ? >= dp.min_age)",
+		"(dp.max_age IS NULL OR ? <= dp.max_age)",
+		"(dp.genders IS NULL OR JSON_LENGTH(dp.genders) = 0 OR JSON_CONTAINS(dp.genders, JSON_QUOTE(?)))",
+		"(dp.skill_levels IS NULL OR JSON_LENGTH(dp.skill_levels) = 0 OR JSON_CONTAINS(dp.skill_levels, JSON_QUOTE(?)))",
+		"(dp.play_styles IS NULL OR JSON_LENGTH(dp.play_styles) = 0 OR JSON_CONTAINS(dp.play_styles, JSON_QUOTE(?)))",
+		"(dp.min_rating IS NULL OR ? >= dp.min_rating)",
+		"(dp.max_rating IS NULL OR ? <= dp.max_rating)",
+		"(dp.sports IS NULL OR JSON_LENGTH(dp.sports) = 0 OR JSON_OVERLAPS(dp.sports, CAST(? AS JSON)))",
+		"(dp.max_distance_km IS NULL OR "+distanceTo(at)+" <= dp.max_distance_km)",
+	)
+	args = append(args,
+		viewer.Age, viewer.Age, viewer.Gender, viewer.SkillLevel, viewer.PlayStyle,
+		viewer.Rank, viewer.Rank, selectedSportsJSON(viewer.SportPreferences),
+		viewer.Latitude, viewer.Latitude, viewer.Longitude,
+	)
+
+	return conditions, args
+}
+
+// distanceSQL is the haversine distance in km between (?, ?) and the user aliased as u.
+// It takes the latitude, the latitude again and the longitude as arguments.
+var distanceSQL = distanceTo("u")
+
+// distanceTo is distanceSQL for any table with latitude and longitude columns
+func distanceTo(alias string) string {
+	return strings.NewReplacer("{t}", alias).Replace(`(2 * 6371 * ASIN(LEAST(1, SQRT(
+		POW(SIN(RADIANS({t}.latitude - ?) / 2), 2) +
+		COS(RADIANS(?)) * COS(RADIANS({t}.latitude)) * POW(SIN(RADIANS({t}.longitude - ?) / 2), 2)))))`)
+}
+
+// profileColumns selects the public profile fields of users aliased as u
+const profileColumns = `u.id, u.name, u.age, u.gender, u.location, u.rank, u.profile_pic_url, u.bio,
+		u.sport_preferences, u.skill_level, u.ntrp_rating, u.play_style, u.preferred_timeslots, u.availability, u.partner_sports, u.created_at`
+
+func scanProfile(row rowScanner, extra ...interface{}) (*models.UserProfile, error) {
+	var profile models.UserProfile
+	dest := []interface{}{
+		&profile.ID, &profile.Name, &profile.Age, &profile.Gender, &profile.Location,
+		&profile.Rank, &profile.ProfilePicURL, &profile.Bio,
+		&profile.SportPreferences, &profile.SkillLevel, &profile.NTRPRating, &profile.PlayStyle, &profile.PreferredTimeslots,
+		&profile.Availability, &profile.PartnerSports, &profile.CreatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// SetPartnerSports replaces the sports the user is looking for a doubles partner in
+func (r *UserRepository) SetPartnerSports(id int64, sports models.StringList) error {
+	query := `UPDATE users SET partner_sports = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+
+	if _, err := r.db.Exec(query, sports, id); err != nil {
+		return fmt.Errorf("failed to update partner sports: %w", err)
+	}
+
+	return nil
 }
 
 func (r *UserRepository) Delete(id int64) error {
@@ -202,4 +415,25 @@ func (r *UserRepository) Delete(id int64) error {
 	}
 
 	return nil
-} 
\ No newline at end of file
+} 
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// sportJSONPath builds the JSON path of a sport key in sport_preferences
+func sportJSONPath(sport string) string {
+	return `$."` + strings.ReplaceAll(sport, `"`, ``) + `"`
+}
+
+// selectedSportsJSON returns the sports a user has turned on as a JSON array
+func selectedSportsJSON(prefs models.SportPreferences) string {
+	selected := []string{}
+	for sport, enabled := range prefs {
+		if enabled {
+			selected = append(selected, sport)
+		}
+	}
+	data, _ := json.Marshal(selected)
+	return string(data)
+}
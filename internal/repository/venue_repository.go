@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"swipe-sports-backend/internal/database"
+	"swipe-sports-backend/internal/geo"
+	"swipe-sports-backend/internal/models"
+)
+
+type VenueRepository struct {
+	db *sql.DB
+}
+
+func NewVenueRepository() *VenueRepository {
+	return &VenueRepository{db: database.DB}
+}
+
+const venueColumns = `v.id, v.external_id, v.name, v.address, v.latitude, v.longitude, v.sports, v.surface,
+	v.indoor, v.lights, v.cost_per_hour, v.currency, v.opening_hours, v.created_at, v.updated_at`
+
+// venueDistanceSQL is distanceSQL for venues aliased as v
+var venueDistanceSQL = distanceTo("v")
+
+// scanVenue reads venueColumns followed by any extra columns
+func scanVenue(row rowScanner, extra ...interface{}) (*models.Venue, error) {
+	var v models.Venue
+	dest := []interface{}{
+		&v.ID, &v.ExternalID, &v.Name, &v.Address, &v.Latitude, &v.Longitude, &v.Sports, &v.Surface,
+		&v.Indoor, &v.Lights, &v.CostPerHour, &v.Currency, &v.OpeningHours, &v.CreatedAt, &v.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *VenueRepository) Create(venue *models.Venue) error {
+	query := `
+		INSERT INTO venues (external_id, name, address, latitude, longitude, sports, surface, indoor, lights,
+			cost_per_hour, currency, opening_hours)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query,
+		venue.ExternalID, venue.Name, venue.Address, venue.Latitude, venue.Longitude, venue.Sports, venue.Surface,
+		venue.Indoor, venue.Lights, venue.CostPerHour, venue.Currency, venue.OpeningHours,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create venue: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	venue.ID = id
+	return nil
+}
+
+// Upsert creates the venue or, when one with the same external ID exists, overwrites it. It
+// reports whether a new venue was created.
+func (r *VenueRepository) Upsert(venue *models.Venue) (bool, error) {
+	if venue.ExternalID == nil {
+		return true, r.Create(venue)
+	}
+
+	var id int64
+	err := r.db.QueryRow(`SELECT id FROM venues WHERE external_id = ?`, *venue.ExternalID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return true, r.Create(venue)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get venue by external id: %w", err)
+	}
+
+	venue.ID = id
+	return false, r.Update(venue)
+}
+
+func (r *VenueRepository) GetByID(id int64) (*models.Venue, error) {
+	query := `SELECT ` + venueColumns + ` FROM venues v WHERE v.id = ?`
+
+	venue, err := scanVenue(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get venue: %w", err)
+	}
+
+	return venue, nil
+}
+
+func (r *VenueRepository) Update(venue *models.Venue) error {
+	query := `
+		UPDATE venues SET
+			name = ?, address = ?, latitude = ?, longitude = ?, sports = ?, surface = ?, indoor = ?, lights = ?,
+			cost_per_hour = ?, currency = ?, opening_hours = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+
+	_, err := r.db.Exec(query,
+		venue.Name, venue.Address, venue.Latitude, venue.Longitude, venue.Sports, venue.Surface, venue.Indoor,
+		venue.Lights, venue.CostPerHour, venue.Currency, venue.OpeningHours, venue.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update venue: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a venue and the favourites pointing at it. Proposals keep the venue's name
+// but lose the link. It reports false if there was no such venue.
+func (r *VenueRepository) Delete(id int64) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE game_proposals SET venue_id = NULL WHERE venue_id = ?`, id); err != nil {
+		return false, fmt.Errorf("failed to unlink proposals: %w", err)
+	}
+	result, err := tx.Exec(`DELETE FROM venues WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete venue: %w", err)
+	}
+	deleted, err := rowsChanged(result)
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit venue deletion: %w", err)
+	}
+	return true, nil
+}
+
+// Search returns the venues within the radius of the query's point, nearest first, with the
+// user's favourites flagged. The query's coordinates must be set.
+func (r *VenueRepository) Search(userID int64, q models.VenueSearchQuery) ([]models.Venue, error) {
+	lat, lng, radius := *q.Latitude, *q.Longitude, q.RadiusKm
+
+	var conditions []string
+	var args []interface{}
+
+	// Same narrowing as the swipe deck: geohash cells, then the bounding box, then exact distance
+	if prefixes := geo.CoveringPrefixes(lat, lng, radius); prefixes != nil {
+		prefixConditions := make([]string, len(prefixes))
+		for i, prefix := range prefixes {
+			prefixConditions[i] = "v.geohash LIKE ?"
+			args = append(args, prefix+"%")
+		}
+		conditions = append(conditions, "("+strings.Join(prefixConditions, " OR ")+")")
+	}
+
+	minLat, maxLat, minLng, maxLng := geo.BoundingBox(lat, lng, radius)
+	conditions = append(conditions,
+		"v.latitude BETWEEN ? AND ?",
+		"v.longitude BETWEEN ? AND ?",
+		venueDistanceSQL+" <= ?",
+	)
+	args = append(args, minLat, maxLat, minLng, maxLng, lat, lat, lng, radius)
+
+	if q.Sport != "" {
+		// Venues that list no sports host any
+		conditions = append(conditions, "(JSON_LENGTH(v.sports) = 0 OR JSON_CONTAINS(v.sports, JSON_QUOTE(?)))")
+		args = append(args, q.Sport)
+	}
+	if q.Indoor != nil {
+		conditions = append(conditions, "v.indoor = ?")
+		args = append(args, *q.Indoor)
+	}
+	if q.Lights != nil {
+		conditions = append(conditions, "v.lights = ?")
+		args = append(args, *q.Lights)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, %s AS distance, f.user_id IS NOT NULL
+		FROM venues v
+		LEFT JOIN venue_favourites f ON f.venue_id = v.id AND f.user_id = ?
+		WHERE %s
+		ORDER BY distance, v.id
+		LIMIT ?
+	`, venueColumns, venueDistanceSQL, strings.Join(conditions, " AND "))
+
+	args = append(append([]interface{}{lat, lat, lng, userID}, args...), q.Limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search venues: %w", err)
+	}
+	defer rows.Close()
+
+	venues := []models.Venue{}
+	for rows.Next() {
+		var distance float64
+		var favourite bool
+		venue, err := scanVenue(rows, &distance, &favourite)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan venue: %w", err)
+		}
+		venue.DistanceKm = &distance
+		venue.Favourite = favourite
+		venues = append(venues, *venue)
+	}
+
+	return venues, nil
+}
+
+// AddFavourite saves a venue for the user; saving it twice is a no-op
+func (r *VenueRepository) AddFavourite(userID, venueID int64) error {
+	if _, err := r.db.Exec(`INSERT IGNORE INTO venue_favourites (user_id, venue_id) VALUES (?, ?)`, userID, venueID); err != nil {
+		return fmt.Errorf("failed to add favourite venue: %w", err)
+	}
+	return nil
+}
+
+// RemoveFavourite reports false if the venue was not one of the user's favourites
+func (r *VenueRepository) RemoveFavourite(userID, venueID int64) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM venue_favourites WHERE user_id = ? AND venue_id = ?`, userID, venueID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove favourite venue: %w", err)
+	}
+	return rowsChanged(result)
+}
+
+// GetFavourites returns the favourite venues of each user, most recently saved first. Users
+// without favourites are absent from the map.
+func (r *VenueRepository) GetFavourites(userIDs []int64) (map[int64][]models.Venue, error) {
+	favourites := make(map[int64][]models.Venue)
+	if len(userIDs) == 0 {
+		return favourites, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, f.user_id
+		FROM venue_favourites f
+		JOIN venues v ON v.id = f.venue_id
+		WHERE f.user_id IN (%s)
+		ORDER BY f.created_at DESC, v.id
+	`, venueColumns, placeholders(len(userIDs)))
+
+	args := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		args[i] = id
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favourite venues: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int64
+		venue, err := scanVenue(rows, &userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan venue: %w", err)
+		}
+		venue.Favourite = true
+		favourites[userID] = append(favourites[userID], *venue)
+	}
+
+	return favourites, nil
+}
@@ -0,0 +1,122 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"swipe-sports-backend/internal/models"
+)
+
+// MinimumOverlap is the shortest shared window worth reporting
+const MinimumOverlap = 30 * time.Minute
+
+// DefaultHorizon is how far ahead overlaps are computed
+const DefaultHorizon = 7 * 24 * time.Hour
+
+// Expand turns a weekly availability into concrete, merged time ranges within [from, to).
+// Each window is resolved in the availability's own time zone, so DST transitions shift the
+// UTC instants as expected.
+func Expand(availability models.Availability, from, to time.Time) []models.TimeRange {
+	loc := availability.Location()
+
+	var ranges []models.TimeRange
+	localFrom := from.In(loc)
+	// Start a day early so windows that began before "from" are clipped rather than dropped
+	day := time.Date(localFrom.Year(), localFrom.Month(), localFrom.Day()-1, 0, 0, 0, 0, loc)
+	for ; day.Before(to); day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc) {
+		for _, window := range availability.Windows {
+			weekday, ok := models.ParseWeekday(window.Day)
+			if !ok || weekday != day.Weekday() {
+				continue
+			}
+			startMinutes, err := models.ParseClock(window.Start)
+			if err != nil {
+				continue
+			}
+			endMinutes, err := models.ParseClock(window.End)
+			if err != nil || endMinutes <= startMinutes {
+				continue
+			}
+
+			start := time.Date(day.Year(), day.Month(), day.Day(), 0, startMinutes, 0, 0, loc)
+			end := time.Date(day.Year(), day.Month(), day.Day(), 0, endMinutes, 0, 0, loc)
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				ranges = append(ranges, models.TimeRange{Start: start, End: end})
+			}
+		}
+	}
+
+	return merge(ranges)
+}
+
+// Overlap returns the windows within [from, to) where both availabilities are free, expressed
+// in the first availability's time zone. Windows shorter than MinimumOverlap are dropped.
+func Overlap(a, b models.Availability, from, to time.Time) []models.TimeRange {
+	return Intersect(Expand(a, from, to), Expand(b, from, to), a.Location())
+}
+
+// Covers reports whether the availability is free for the whole of [start, end)
+func Covers(availability models.Availability, start, end time.Time) bool {
+	ranges := Expand(availability, start, end)
+	return len(ranges) == 1 && !ranges[0].Start.After(start) && !ranges[0].End.Before(end)
+}
+
+// Intersect intersects two sorted, merged range lists and converts the result to loc
+func Intersect(a, b []models.TimeRange, loc *time.Location) []models.TimeRange {
+	overlaps := []models.TimeRange{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		start := latest(a[i].Start, b[j].Start)
+		end := earliest(a[i].End, b[j].End)
+		if end.Sub(start) >= MinimumOverlap {
+			overlaps = append(overlaps, models.TimeRange{Start: start.In(loc), End: end.In(loc)})
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return overlaps
+}
+
+func merge(ranges []models.TimeRange) []models.TimeRange {
+	if len(ranges) == 0 {
+		return ranges
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start.Before(ranges[j].Start)
+	})
+
+	merged := []models.TimeRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if !r.Start.After(last.End) {
+			last.End = latest(last.End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,159 @@
+package schedule
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"swipe-sports-backend/internal/models"
+)
+
+func TestOverlap_AcrossTimeZones(t *testing.T) {
+	// 18:00-21:00 in Halifax (UTC-3 in summer) is 17:00-20:00 in Toronto (UTC-4)
+	halifax := models.Availability{
+		TimeZone: "America/Halifax",
+		Windows:  []models.AvailabilityWindow{{Day: "monday", Start: "18:00", End: "21:00"}},
+	}
+	toronto := models.Availability{
+		TimeZone: "America/Toronto",
+		Windows:  []models.AvailabilityWindow{{Day: "monday", Start: "19:00", End: "22:00"}},
+	}
+
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC) // a Monday
+	overlaps := Overlap(halifax, toronto, from, from.Add(DefaultHorizon))
+
+	require.Len(t, overlaps, 1)
+	assert.Equal(t, time.Date(2024, 7, 1, 23, 0, 0, 0, time.UTC), overlaps[0].Start.UTC())
+	assert.Equal(t, time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC), overlaps[0].End.UTC())
+	assert.Equal(t, "America/Halifax", overlaps[0].Start.Location().String())
+}
+
+func TestOverlap_DSTTransition(t *testing.T) {
+	// Toronto leaves DST on 2024-11-03 while UTC does not, so a fixed UTC window shifts
+	toronto := models.Availability{
+		TimeZone: "America/Toronto",
+		Windows: []models.AvailabilityWindow{
+			{Day: "saturday", Start: "10:00", End: "12:00"},
+			{Day: "sunday", Start: "10:00", End: "12:00"},
+		},
+	}
+	utc := models.Availability{
+		TimeZone: "UTC",
+		Windows: []models.AvailabilityWindow{
+			{Day: "saturday", Start: "14:00", End: "16:00"},
+			{Day: "sunday", Start: "14:00", End: "16:00"},
+		},
+	}
+
+	from := time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC)
+	overlaps := Overlap(toronto, utc, from, from.Add(48*time.Hour))
+
+	require.Len(t, overlaps, 2)
+	// Saturday (EDT, UTC-4): 14:00-16:00 UTC fully overlaps
+	assert.Equal(t, 2*time.Hour, overlaps[0].End.Sub(overlaps[0].Start))
+	// Sunday (EST, UTC-5): 15:00-17:00 UTC only shares 15:00-16:00
+	assert.Equal(t, time.Date(2024, 11, 3, 15, 0, 0, 0, time.UTC), overlaps[1].Start.UTC())
+	assert.Equal(t, time.Hour, overlaps[1].End.Sub(overlaps[1].Start))
+}
+
+func TestOverlap_DropsShortWindows(t *testing.T) {
+	a := models.Availability{TimeZone: "UTC", Windows: []models.AvailabilityWindow{{Day: "monday", Start: "18:00", End: "18:45"}}}
+	b := models.Availability{TimeZone: "UTC", Windows: []models.AvailabilityWindow{{Day: "monday", Start: "18:30", End: "20:00"}}}
+
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	assert.Empty(t, Overlap(a, b, from, from.Add(DefaultHorizon)))
+}
+
+func TestExpand_MergesAdjacentAndClips(t *testing.T) {
+	availability := models.Availability{
+		TimeZone: "UTC",
+		Windows: []models.AvailabilityWindow{
+			{Day: "monday", Start: "22:00", End: "24:00"},
+			{Day: "tuesday", Start: "00:00", End: "02:00"},
+		},
+	}
+
+	from := time.Date(2024, 7, 1, 23, 0, 0, 0, time.UTC)
+	ranges := Expand(availability, from, from.Add(24*time.Hour))
+
+	require.Len(t, ranges, 1)
+	assert.Equal(t, from, ranges[0].Start)
+	assert.Equal(t, time.Date(2024, 7, 2, 2, 0, 0, 0, time.UTC), ranges[0].End)
+}
+
+func TestAvailability_LegacyFormat(t *testing.T) {
+	var availability models.Availability
+	err := json.Unmarshal([]byte(`{"monday": ["18:00", "20:00"], "saturday": ["10:00", "14:00"]}`), &availability)
+	require.NoError(t, err)
+
+	assert.Equal(t, "UTC", availability.TimeZone)
+	assert.Equal(t, []models.AvailabilityWindow{
+		{Day: "monday", Start: "18:00", End: "20:00"},
+		{Day: "saturday", Start: "10:00", End: "14:00"},
+	}, availability.Windows)
+	assert.NoError(t, availability.Validate())
+}
+
+func TestAvailability_Validate(t *testing.T) {
+	valid := models.Availability{TimeZone: "Europe/Paris", Windows: []models.AvailabilityWindow{{Day: "friday", Start: "17:30", End: "24:00"}}}
+	assert.NoError(t, valid.Validate())
+
+	badZone := models.Availability{TimeZone: "Mars/Olympus"}
+	assert.Error(t, badZone.Validate())
+
+	inverted := models.Availability{TimeZone: "UTC", Windows: []models.AvailabilityWindow{{Day: "friday", Start: "20:00", End: "18:00"}}}
+	assert.Error(t, inverted.Validate())
+
+	overlapping := models.Availability{TimeZone: "UTC", Windows: []models.AvailabilityWindow{
+		{Day: "friday", Start: "17:00", End: "19:00"},
+		{Day: "friday", Start: "18:00", End: "20:00"},
+	}}
+	assert.Error(t, overlapping.Validate())
+}
+
+func TestCovers(t *testing.T) {
+	av := models.Availability{
+		TimeZone: "Europe/Berlin",
+		Windows: []models.AvailabilityWindow{
+			{Day: "tuesday", Start: "17:00", End: "19:00"},
+			{Day: "tuesday", Start: "19:00", End: "21:00"},
+		},
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	start := time.Date(2024, 7, 2, 18, 0, 0, 0, berlin) // a Tuesday
+	assert.True(t, Covers(av, start, start.Add(2*time.Hour)), "adjacent windows merge")
+	assert.True(t, Covers(av, start.Add(-time.Hour), start.Add(3*time.Hour)))
+	assert.False(t, Covers(av, start.Add(2*time.Hour), start.Add(4*time.Hour)), "runs past the last window")
+	assert.False(t, Covers(av, start.AddDate(0, 0, 1), start.AddDate(0, 0, 1).Add(time.Hour)), "wrong day")
+}
+
+func TestSuggestWeekly(t *testing.T) {
+	// Busy every weekday 09:00-17:00 for four weeks, plus one Tuesday evening
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC) // a Monday
+	var busy []models.TimeRange
+	for day := 0; day < 28; day++ {
+		date := from.AddDate(0, 0, day)
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+		busy = append(busy, models.TimeRange{Start: date.Add(9 * time.Hour), End: date.Add(17 * time.Hour)})
+	}
+	tuesday := from.AddDate(0, 0, 8)
+	busy = append(busy, models.TimeRange{Start: tuesday.Add(18 * time.Hour), End: tuesday.Add(20 * time.Hour)})
+
+	av := SuggestWeekly(busy, time.UTC, from, 4, DefaultSuggestOptions)
+
+	assert.Equal(t, "UTC", av.TimeZone)
+	require.NoError(t, av.Validate())
+	assert.Equal(t, models.AvailabilityWindow{Day: "monday", Start: "07:00", End: "09:00"}, av.Windows[0])
+	assert.Equal(t, models.AvailabilityWindow{Day: "monday", Start: "17:00", End: "22:00"}, av.Windows[1])
+	// Busy one Tuesday in four still leaves the evening free three weeks out of four
+	assert.Contains(t, av.Windows, models.AvailabilityWindow{Day: "tuesday", Start: "17:00", End: "22:00"})
+	assert.Contains(t, av.Windows, models.AvailabilityWindow{Day: "sunday", Start: "07:00", End: "22:00"})
+	assert.Len(t, av.Windows, 12)
+}
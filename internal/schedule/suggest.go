@@ -0,0 +1,101 @@
+package schedule
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"swipe-sports-backend/internal/models"
+)
+
+// SuggestOptions tune how a weekly availability is inferred from busy time
+type SuggestOptions struct {
+	// DayStart and DayEnd bound the hours considered each day, in minutes since midnight
+	DayStart int
+	DayEnd   int
+	// Slot is the granularity of the suggested windows
+	Slot time.Duration
+	// FreeShare is the fraction of weeks a slot must be free in to count as available
+	FreeShare float64
+	// MinWindow drops suggested windows shorter than this
+	MinWindow time.Duration
+}
+
+// DefaultSuggestOptions considers 07:00 to 22:00 in half-hour slots, and keeps windows of at
+// least an hour that were free in three weeks out of four
+var DefaultSuggestOptions = SuggestOptions{
+	DayStart:  7 * 60,
+	DayEnd:    22 * 60,
+	Slot:      30 * time.Minute,
+	FreeShare: 0.75,
+	MinWindow: time.Hour,
+}
+
+// SuggestWeekly proposes a weekly availability in loc from busy time observed over the given
+// number of weeks starting at from's local midnight. A slot of the week is available when it
+// was free in at least opts.FreeShare of those weeks; adjacent available slots are merged into
+// windows.
+func SuggestWeekly(busy []models.TimeRange, loc *time.Location, from time.Time, weeks int, opts SuggestOptions) models.Availability {
+	availability := models.Availability{TimeZone: loc.String(), Windows: []models.AvailabilityWindow{}}
+	slotMinutes := int(opts.Slot / time.Minute)
+	if weeks <= 0 || slotMinutes <= 0 || opts.DayEnd <= opts.DayStart {
+		return availability
+	}
+
+	busy = merge(append([]models.TimeRange(nil), busy...))
+	slots := (opts.DayEnd - opts.DayStart) / slotMinutes
+	needed := int(math.Ceil(opts.FreeShare * float64(weeks)))
+
+	local := from.In(loc)
+	first := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	// freeWeeks[weekday][slot] counts the weeks in which the slot was free
+	var freeWeeks [7][]int
+	for d := range freeWeeks {
+		freeWeeks[d] = make([]int, slots)
+	}
+	for day := 0; day < weeks*7; day++ {
+		date := time.Date(first.Year(), first.Month(), first.Day()+day, 0, 0, 0, 0, loc)
+		for slot := 0; slot < slots; slot++ {
+			startMinutes := opts.DayStart + slot*slotMinutes
+			start := time.Date(date.Year(), date.Month(), date.Day(), 0, startMinutes, 0, 0, loc)
+			end := time.Date(date.Year(), date.Month(), date.Day(), 0, startMinutes+slotMinutes, 0, 0, loc)
+			if !overlapsAny(busy, start, end) {
+				freeWeeks[date.Weekday()][slot]++
+			}
+		}
+	}
+
+	minSlots := int(math.Ceil(float64(opts.MinWindow) / float64(opts.Slot)))
+	for _, weekday := range []time.Weekday{
+		time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+	} {
+		day := strings.ToLower(weekday.String())
+		run := 0
+		for slot := 0; slot <= slots; slot++ {
+			if slot < slots && freeWeeks[weekday][slot] >= needed {
+				run++
+				continue
+			}
+			if run > 0 && run >= minSlots {
+				startMinutes := opts.DayStart + (slot-run)*slotMinutes
+				availability.Windows = append(availability.Windows, models.AvailabilityWindow{
+					Day:   day,
+					Start: models.FormatClock(startMinutes),
+					End:   models.FormatClock(startMinutes + run*slotMinutes),
+				})
+			}
+			run = 0
+		}
+	}
+
+	return availability
+}
+
+// overlapsAny reports whether [start, end) overlaps any of the sorted, merged ranges
+func overlapsAny(ranges []models.TimeRange, start, end time.Time) bool {
+	// The first range ending after start is the only candidate
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].End.After(start) })
+	return i < len(ranges) && ranges[i].Start.Before(end)
+}
@@ -14,7 +14,10 @@ import (
 	"swipe-sports-backend/internal/auth"
 	"swipe-sports-backend/internal/config"
 	"swipe-sports-backend/internal/handler"
+	"swipe-sports-backend/internal/middleware"
+	"swipe-sports-backend/internal/models"
 	"swipe-sports-backend/internal/redis"
+	"swipe-sports-backend/internal/service"
 )
 
 type Server struct {
@@ -58,6 +61,16 @@ func New(db *sql.DB, redisClient *redis.Client) *Server {
 	}
 
 	server.setupRoutes()
+
+	// Confirm game results nobody answered in time
+	go service.NewGameService().RunAutoConfirm(config.AppConfig.Games.AutoConfirmInterval)
+
+	// Rebuild leaderboards from MySQL to drop inactive players and repair drift
+	go service.NewLeaderboardService().RunRebuild(config.AppConfig.Leaderboard.RebuildInterval)
+
+	// Forfeit ladder challenges nobody accepted in time and expire those never played
+	go service.NewLadderService().RunDeadlines(config.AppConfig.Ladder.DeadlineInterval)
+
 	return server
 }
 
@@ -65,22 +78,57 @@ func (s *Server) setupRoutes() {
 	// Health check
 	s.router.GET("/health", s.healthCheck)
 
+	// Rate limits: per IP on /auth and calendar feeds, per user everywhere else
+	rateLimit := config.AppConfig.RateLimit
+	authLimiter := middleware.NewRateLimiter(
+		middleware.Policy{Name: "auth", Requests: rateLimit.AuthRequests, Window: rateLimit.AuthWindow},
+		middleware.ByIP,
+	)
+	apiLimiter := middleware.NewRateLimiter(
+		middleware.Policy{Name: "api", Requests: rateLimit.Requests, Window: rateLimit.Window},
+		middleware.ByUser,
+	).
+		Override("POST", "/api/v1/swipe", middleware.Policy{Name: "swipe", Requests: 300, Window: time.Minute}).
+		Override("POST", "/api/v1/profile/picture", middleware.Policy{Name: "upload", Requests: 10, Window: time.Hour}).
+		Override("POST", "/api/v1/profile/availability/import", middleware.Policy{Name: "import", Requests: 10, Window: time.Hour})
+	feedLimiter := middleware.NewRateLimiter(
+		middleware.Policy{Name: "calendar", Requests: 60, Window: time.Hour},
+		middleware.ByIP,
+	)
+
 	// API v1 routes
 	v1 := s.router.Group("/api/v1")
 	{
 		// Authentication routes (no auth required)
-		auth := v1.Group("/auth")
+		authRoutes := v1.Group("/auth")
+		authRoutes.Use(authLimiter.Middleware())
 		{
 			authHandler := handler.NewAuthHandler()
-			auth.POST("/signup", authHandler.Signup)
-			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", authHandler.RefreshToken)
-			auth.POST("/logout", authHandler.Logout)
+			authRoutes.POST("/signup", authHandler.Signup)
+			authRoutes.POST("/login", authHandler.Login)
+			authRoutes.POST("/refresh", authHandler.RefreshToken)
+			authRoutes.POST("/logout", authHandler.Logout)
+		}
+
+		// Sports catalog (public)
+		sports := v1.Group("/sports")
+		{
+			sportHandler := handler.NewSportHandler()
+			sports.GET("", sportHandler.GetSports)
+			sports.GET("/:slug", sportHandler.GetSport)
+		}
+
+		// Calendar feeds (the secret token in the URL authenticates; calendar apps poll these)
+		feeds := v1.Group("/calendar/feeds")
+		feeds.Use(feedLimiter.Middleware())
+		{
+			calendarHandler := handler.NewCalendarHandler()
+			feeds.GET("/:token", calendarHandler.ServeFeed)
 		}
 
 		// Protected routes (require authentication)
 		protected := v1.Group("")
-		protected.Use(auth.AuthMiddleware())
+		protected.Use(auth.AuthMiddleware(), apiLimiter.Middleware())
 		{
 			// Profile routes
 			profile := protected.Group("/profile")
@@ -90,6 +138,19 @@ func (s *Server) setupRoutes() {
 				profile.PUT("/me", authHandler.UpdateMyProfile)
 				profile.PUT("/update", authHandler.UpdateProfileFromOnboarding)
 				profile.POST("/picture", authHandler.UploadProfilePicture)
+
+				preferencesHandler := handler.NewPreferencesHandler()
+				profile.GET("/preferences", preferencesHandler.GetPreferences)
+				profile.PUT("/preferences", preferencesHandler.UpdatePreferences)
+
+				ratingHandler := handler.NewRatingHandler()
+				profile.GET("/ratings", ratingHandler.GetMyRatings)
+
+				availabilityHandler := handler.NewAvailabilityHandler()
+				profile.POST("/availability/import", availabilityHandler.ImportCalendar)
+
+				partnershipHandler := handler.NewPartnershipHandler()
+				profile.PUT("/partner-sports", partnershipHandler.SetPartnerSports)
 			}
 
 			// Swipe routes
@@ -98,8 +159,146 @@ func (s *Server) setupRoutes() {
 				swipeHandler := handler.NewSwipeHandler()
 				swipe.GET("/profiles", swipeHandler.GetProfiles)
 				swipe.POST("/swipe", swipeHandler.Swipe)
+				swipe.POST("/swipe/undo", swipeHandler.UndoSwipe)
+				swipe.GET("/quotas", swipeHandler.GetQuotas)
+				swipe.GET("/likes/received", middleware.RequireFeature(models.FeatureLikesInbox), swipeHandler.GetReceivedLikes)
 				swipe.GET("/matches", swipeHandler.GetMatches)
 				swipe.GET("/matches/:id", swipeHandler.GetMatch)
+				swipe.DELETE("/matches/:id", swipeHandler.Unmatch)
+			}
+
+			// Group chat routes
+			groups := protected.Group("/groups")
+			{
+				groupHandler := handler.NewGroupHandler()
+				groups.GET("", groupHandler.GetGroups)
+				groups.POST("", groupHandler.CreateGroup)
+				groups.GET("/:id", groupHandler.GetGroup)
+				groups.POST("/:id/invite", groupHandler.Invite)
+				groups.POST("/:id/leave", groupHandler.Leave)
+				groups.DELETE("/:id/participants/:user_id", groupHandler.Kick)
+			}
+
+			// Doubles partnership routes
+			partnerships := protected.Group("")
+			{
+				partnershipHandler := handler.NewPartnershipHandler()
+				partnerships.POST("/matches/:id/partnership", partnershipHandler.Propose)
+				partnerships.GET("/partnerships", partnershipHandler.GetPartnerships)
+				partnerships.GET("/partnerships/:id", partnershipHandler.GetPartnership)
+				partnerships.POST("/partnerships/:id/accept", partnershipHandler.AcceptPartnership)
+				partnerships.POST("/partnerships/:id/decline", partnershipHandler.DeclinePartnership)
+				partnerships.DELETE("/partnerships/:id", partnershipHandler.DissolvePartnership)
+			}
+
+			// Availability routes
+			availability := protected.Group("/availability")
+			{
+				availabilityHandler := handler.NewAvailabilityHandler()
+				availability.GET("/overlap/:user_id", availabilityHandler.GetOverlap)
+			}
+
+			// Game routes
+			games := protected.Group("/games")
+			{
+				gameHandler := handler.NewGameHandler()
+				games.POST("", gameHandler.ReportGame)
+				games.GET("", gameHandler.GetGames)
+				games.GET("/:id", gameHandler.GetGame)
+				games.POST("/:id/confirm", gameHandler.ConfirmGame)
+				games.POST("/:id/dispute", gameHandler.DisputeGame)
+			}
+
+			// Game proposal routes
+			proposals := protected.Group("")
+			{
+				proposalHandler := handler.NewProposalHandler()
+				proposals.POST("/matches/:id/proposals", proposalHandler.CreateProposal)
+				proposals.GET("/matches/:id/proposals", proposalHandler.GetProposals)
+				proposals.POST("/proposals/:id/accept", proposalHandler.AcceptProposal)
+				proposals.POST("/proposals/:id/decline", proposalHandler.DeclineProposal)
+				proposals.POST("/proposals/:id/counter", proposalHandler.CounterProposal)
+				proposals.POST("/proposals/:id/cancel", proposalHandler.CancelProposal)
+				proposals.GET("/matches/:id/venues", proposalHandler.GetVenueSuggestions)
+			}
+
+			// Calendar routes
+			calendar := protected.Group("")
+			{
+				calendarHandler := handler.NewCalendarHandler()
+				calendar.GET("/proposals/:id/calendar.ics", calendarHandler.GetGameCalendar)
+				calendar.GET("/calendar/feed", calendarHandler.GetFeed)
+				calendar.POST("/calendar/feed/reset", calendarHandler.ResetFeed)
+			}
+
+			// Venue routes
+			venues := protected.Group("/venues")
+			{
+				venueHandler := handler.NewVenueHandler()
+				venues.GET("", venueHandler.SearchVenues)
+				venues.GET("/favourites", venueHandler.GetFavourites)
+				venues.GET("/:id", venueHandler.GetVenue)
+				venues.POST("/:id/favourite", venueHandler.AddFavourite)
+				venues.DELETE("/:id/favourite", venueHandler.RemoveFavourite)
+			}
+
+			// Open game board routes
+			openGames := protected.Group("/open-games")
+			{
+				openGameHandler := handler.NewOpenGameHandler()
+				openGames.GET("", openGameHandler.GetFeed)
+				openGames.POST("", openGameHandler.CreateGame)
+				openGames.GET("/mine", openGameHandler.GetMyGames)
+				openGames.GET("/:id", openGameHandler.GetGame)
+				openGames.POST("/:id/cancel", openGameHandler.CancelGame)
+				openGames.POST("/:id/join", openGameHandler.RequestToJoin)
+				openGames.POST("/:id/leave", openGameHandler.LeaveGame)
+				openGames.GET("/:id/teams", openGameHandler.GetTeams)
+				openGames.POST("/:id/teams", openGameHandler.GenerateTeams)
+				openGames.PUT("/:id/teams", openGameHandler.AdjustTeams)
+				openGames.POST("/requests/:id/approve", openGameHandler.ApproveRequest)
+				openGames.POST("/requests/:id/reject", openGameHandler.RejectRequest)
+			}
+
+			// Tournament routes
+			tournaments := protected.Group("/tournaments")
+			{
+				tournamentHandler := handler.NewTournamentHandler()
+				tournaments.GET("", tournamentHandler.GetTournaments)
+				tournaments.POST("", tournamentHandler.CreateTournament)
+				tournaments.GET("/mine", tournamentHandler.GetMyTournaments)
+				tournaments.GET("/:id", tournamentHandler.GetTournament)
+				tournaments.GET("/:id/export", tournamentHandler.ExportTournament)
+				tournaments.POST("/:id/register", tournamentHandler.Register)
+				tournaments.POST("/:id/withdraw", tournamentHandler.Withdraw)
+				tournaments.POST("/:id/start", tournamentHandler.Start)
+				tournaments.POST("/:id/cancel", tournamentHandler.Cancel)
+				tournaments.POST("/:id/matches/:match_id/result", tournamentHandler.ReportResult)
+				tournaments.POST("/:id/matches/:match_id/confirm", tournamentHandler.ConfirmResult)
+			}
+
+			// Ladder routes
+			ladders := protected.Group("/ladders")
+			{
+				ladderHandler := handler.NewLadderHandler()
+				ladders.GET("", ladderHandler.GetLadders)
+				ladders.POST("", ladderHandler.CreateLadder)
+				ladders.GET("/mine", ladderHandler.GetMyLadders)
+				ladders.GET("/:id", ladderHandler.GetLadder)
+				ladders.POST("/:id/join", ladderHandler.Join)
+				ladders.POST("/:id/leave", ladderHandler.Leave)
+				ladders.POST("/:id/close", ladderHandler.Close)
+				ladders.POST("/:id/challenges", ladderHandler.Challenge)
+				ladders.POST("/challenges/:id/accept", ladderHandler.AcceptChallenge)
+				ladders.POST("/challenges/:id/forfeit", ladderHandler.ForfeitChallenge)
+			}
+
+			// Leaderboard routes
+			leaderboards := protected.Group("/leaderboards")
+			{
+				leaderboardHandler := handler.NewLeaderboardHandler()
+				leaderboards.GET("/:sport", leaderboardHandler.GetLeaderboard)
+				leaderboards.GET("/:sport/me", leaderboardHandler.GetMyStanding)
 			}
 
 			// Message routes
@@ -113,6 +312,26 @@ func (s *Server) setupRoutes() {
 				messages.GET("/:match_id/unread-count", messageHandler.GetUnreadCount)
 				messages.POST("/typing", messageHandler.SendTypingIndicator)
 			}
+
+			// Admin routes
+			admin := protected.Group("/admin")
+			admin.Use(auth.AdminMiddleware())
+			{
+				sportHandler := handler.NewSportHandler()
+				admin.GET("/sports", sportHandler.GetAllSports)
+				admin.POST("/sports", sportHandler.CreateSport)
+				admin.PUT("/sports/:slug", sportHandler.UpdateSport)
+
+				gameHandler := handler.NewGameHandler()
+				admin.GET("/games/disputes", gameHandler.GetDisputedGames)
+				admin.POST("/games/:id/resolve", gameHandler.ResolveDispute)
+
+				venueHandler := handler.NewVenueHandler()
+				admin.POST("/venues", venueHandler.CreateVenue)
+				admin.PUT("/venues/:id", venueHandler.UpdateVenue)
+				admin.DELETE("/venues/:id", venueHandler.DeleteVenue)
+				admin.POST("/venues/import", venueHandler.ImportVenues)
+			}
 		}
 
 		// WebSocket route (requires authentication in production)
@@ -10,13 +10,20 @@ import (
 	"swipe-sports-backend/internal/repository"
 )
 
+var (
+	validSkillLevels = []string{"beginner", "intermediate", "advanced"}
+	validPlayStyles  = []string{"ranked", "fun", "competitive", "casual"}
+)
+
 type AuthService struct {
-	userRepo *repository.UserRepository
+	userRepo     *repository.UserRepository
+	sportService *SportService
 }
 
 func NewAuthService() *AuthService {
 	return &AuthService{
-		userRepo: repository.NewUserRepository(),
+		userRepo:     repository.NewUserRepository(),
+		sportService: NewSportService(),
 	}
 }
 
@@ -53,6 +60,10 @@ func (s *AuthService) AuthenticateOAuth(provider, token string) (*AuthResponse,
 		}
 	}
 
+	if err := s.userRepo.TouchLastActive(user.ID); err != nil {
+		fmt.Printf("Failed to update last active: %v\n", err)
+	}
+
 	// Generate JWT token
 	jwtToken, err := auth.GenerateToken(user.ID, oauthUser.Email)
 	if err != nil {
@@ -111,6 +122,8 @@ func (s *AuthService) UpdateUser(userID int64, updateReq models.UpdateUserReques
 		return nil, fmt.Errorf("user not found")
 	}
 
+	oldRegion := userRegion(user.Location)
+
 	// Update fields if provided
 	if updateReq.Name != nil {
 		user.Name = *updateReq.Name
@@ -131,9 +144,15 @@ func (s *AuthService) UpdateUser(userID int64, updateReq models.UpdateUserReques
 		user.Location = updateReq.Location
 	}
 	if updateReq.Latitude != nil {
+		if *updateReq.Latitude < -90 || *updateReq.Latitude > 90 {
+			return nil, fmt.Errorf("latitude must be between -90 and 90")
+		}
 		user.Latitude = updateReq.Latitude
 	}
 	if updateReq.Longitude != nil {
+		if *updateReq.Longitude < -180 || *updateReq.Longitude > 180 {
+			return nil, fmt.Errorf("longitude must be between -180 and 180")
+		}
 		user.Longitude = updateReq.Longitude
 	}
 	if updateReq.ProfilePicURL != nil {
@@ -143,6 +162,9 @@ func (s *AuthService) UpdateUser(userID int64, updateReq models.UpdateUserReques
 		user.Bio = updateReq.Bio
 	}
 	if updateReq.SportPreferences != nil {
+		if err := s.sportService.ValidateSportPreferences(*updateReq.SportPreferences, ""); err != nil {
+			return nil, err
+		}
 		user.SportPreferences = *updateReq.SportPreferences
 	}
 	if updateReq.SkillLevel != nil {
@@ -155,6 +177,9 @@ func (s *AuthService) UpdateUser(userID int64, updateReq models.UpdateUserReques
 		user.PreferredTimeslots = updateReq.PreferredTimeslots
 	}
 	if updateReq.Availability != nil {
+		if err := updateReq.Availability.Validate(); err != nil {
+			return nil, err
+		}
 		user.Availability = *updateReq.Availability
 	}
 	if updateReq.NTRPRating != nil {
@@ -171,6 +196,12 @@ func (s *AuthService) UpdateUser(userID int64, updateReq models.UpdateUserReques
 		fmt.Printf("Failed to cache user profile: %v\n", err)
 	}
 
+	// The deck is centred on the user's location
+	if updateReq.Location != nil || updateReq.Latitude != nil || updateReq.Longitude != nil {
+		invalidateSwipeDeck(userID)
+	}
+	relocateOnLeaderboards(userID, oldRegion, userRegion(user.Location))
+
 	return user, nil
 }
 
@@ -191,7 +222,6 @@ func (s *AuthService) UpdateProfileFromOnboarding(userID int64, profileReq model
 	}
 
 	// Validate skill level
-	validSkillLevels := []string{"beginner", "intermediate", "advanced"}
 	isValidSkill := false
 	for _, level := range validSkillLevels {
 		if profileReq.SkillLevel == level {
@@ -204,7 +234,6 @@ func (s *AuthService) UpdateProfileFromOnboarding(userID int64, profileReq model
 	}
 
 	// Validate play style
-	validPlayStyles := []string{"ranked", "fun", "competitive", "casual"}
 	isValidPlayStyle := false
 	for _, style := range validPlayStyles {
 		if profileReq.PlayStyle == style {
@@ -229,6 +258,18 @@ func (s *AuthService) UpdateProfileFromOnboarding(userID int64, profileReq model
 		return nil, fmt.Errorf("invalid preferred timeslots")
 	}
 
+	// Validate weekly availability
+	if err := profileReq.Availability.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate sports against the catalog
+	if err := s.sportService.ValidateSportPreferences(profileReq.SportPreferences, profileReq.SkillLevel); err != nil {
+		return nil, err
+	}
+
+	oldRegion := userRegion(user.Location)
+
 	// Update user fields
 	user.Name = profileReq.Name
 	user.FirstName = &profileReq.FirstName
@@ -262,6 +303,8 @@ func (s *AuthService) UpdateProfileFromOnboarding(userID int64, profileReq model
 	if err := s.cacheUserProfile(user); err != nil {
 		fmt.Printf("Failed to cache user profile: %v\n", err)
 	}
+	invalidateSwipeDeck(userID)
+	relocateOnLeaderboards(userID, oldRegion, userRegion(user.Location))
 
 	// Generate new token with updated user info
 	var email string
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/ical"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/repository"
+	"swipe-sports-backend/internal/schedule"
+)
+
+// defaultImportWeeks is how much of an imported calendar, from tomorrow on, is read
+const defaultImportWeeks = 4
+
+type AvailabilityService struct {
+	userRepo *repository.UserRepository
+}
+
+func NewAvailabilityService() *AvailabilityService {
+	return &AvailabilityService{
+		userRepo: repository.NewUserRepository(),
+	}
+}
+
+// GetOverlap returns the shared free windows of two users over the coming week,
+// expressed in the first user's time zone
+func (s *AvailabilityService) GetOverlap(userID, otherUserID int64) ([]models.TimeRange, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	other, err := s.userRepo.GetProfileByID(otherUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || other == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	return schedule.Overlap(user.Availability, other.Availability, now, now.Add(schedule.DefaultHorizon)), nil
+}
+
+// ImportCalendar proposes a weekly availability from an iCalendar file: the busy time of the
+// coming weeks, recurring events included, is read off the calendar and the slots free in most
+// weeks become availability windows. Nothing is saved.
+func (s *AvailabilityService) ImportCalendar(userID int64, data []byte, req models.ImportAvailabilityRequest) (*models.AvailabilityImport, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, nil
+	}
+
+	opts := schedule.DefaultSuggestOptions
+	if req.DayStart != "" {
+		if opts.DayStart, err = models.ParseClock(req.DayStart); err != nil {
+			return nil, err
+		}
+	}
+	if req.DayEnd != "" {
+		if opts.DayEnd, err = models.ParseClock(req.DayEnd); err != nil {
+			return nil, err
+		}
+	}
+	if opts.DayEnd <= opts.DayStart {
+		return nil, fmt.Errorf("day_end must be after day_start")
+	}
+	weeks := req.Weeks
+	if weeks == 0 {
+		weeks = defaultImportWeeks
+	}
+
+	// Floating times in the file are read in the user's own zone until the import's is known
+	doc, err := ical.Parse(data, user.Availability.Location())
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar: %w", err)
+	}
+
+	zone := req.TimeZone
+	if zone == "" {
+		zone = user.Availability.TimeZone
+	}
+	if zone == "" {
+		zone = doc.TimeZone
+	}
+	loc := time.UTC
+	if zone != "" {
+		if loc, err = time.LoadLocation(zone); err != nil {
+			return nil, fmt.Errorf("invalid time_zone %q", zone)
+		}
+	}
+	if loc.String() != user.Availability.Location().String() {
+		if doc, err = ical.Parse(data, loc); err != nil {
+			return nil, fmt.Errorf("invalid calendar: %w", err)
+		}
+	}
+
+	today := time.Now().In(loc)
+	from := time.Date(today.Year(), today.Month(), today.Day()+1, 0, 0, 0, 0, loc)
+	to := time.Date(from.Year(), from.Month(), from.Day()+7*weeks, 0, 0, 0, 0, loc)
+
+	busy := []models.TimeRange{}
+	for _, b := range doc.Busy(from, to) {
+		busy = append(busy, models.TimeRange{Start: b.Start.In(loc), End: b.End.In(loc)})
+	}
+
+	return &models.AvailabilityImport{
+		Availability: schedule.SuggestWeekly(busy, loc, from, weeks, opts),
+		From:         from,
+		To:           to,
+		Events:       len(doc.Events),
+		Busy:         busy,
+	}, nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/config"
+	"swipe-sports-backend/internal/ical"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/repository"
+)
+
+const calendarName = "Swipe Sports games"
+
+type CalendarService struct {
+	calendarRepo *repository.CalendarRepository
+	proposalRepo *repository.ProposalRepository
+	swipeRepo    *repository.SwipeRepository
+	userRepo     *repository.UserRepository
+	sportService *SportService
+}
+
+func NewCalendarService() *CalendarService {
+	return &CalendarService{
+		calendarRepo: repository.NewCalendarRepository(),
+		proposalRepo: repository.NewProposalRepository(),
+		swipeRepo:    repository.NewSwipeRepository(),
+		userRepo:     repository.NewUserRepository(),
+		sportService: NewSportService(),
+	}
+}
+
+// GetFeed returns the user's calendar feed URL, issuing a token on first use
+func (s *CalendarService) GetFeed(userID int64) (*models.CalendarFeed, error) {
+	token, createdAt, err := s.calendarRepo.GetFeedToken(userID)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return s.ResetFeed(userID)
+	}
+	return &models.CalendarFeed{URL: feedURL(token), CreatedAt: createdAt}, nil
+}
+
+// ResetFeed issues the user a new feed token. The old URL stops working, so anyone it leaked
+// to loses access.
+func (s *CalendarService) ResetFeed(userID int64) (*models.CalendarFeed, error) {
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.calendarRepo.SetFeedToken(userID, token); err != nil {
+		return nil, err
+	}
+	return &models.CalendarFeed{URL: feedURL(token), CreatedAt: time.Now()}, nil
+}
+
+// Feed renders the calendar of the feed token's owner: their scheduled games, plus recent and
+// cancelled ones so calendar apps see the changes. It returns nil for an unknown token.
+func (s *CalendarService) Feed(token string) ([]byte, error) {
+	if token == "" {
+		return nil, nil
+	}
+	userID, err := s.calendarRepo.GetUserIDByFeedToken(token)
+	if err != nil || userID == 0 {
+		return nil, err
+	}
+
+	games, err := s.proposalRepo.GetScheduledForUser(userID, time.Now().Add(-config.AppConfig.Calendar.Lookback))
+	if err != nil {
+		return nil, err
+	}
+
+	calendar, err := s.calendar(userID, games)
+	if err != nil {
+		return nil, err
+	}
+	calendar.RefreshInterval = config.AppConfig.Calendar.RefreshInterval
+	return calendar.Encode(), nil
+}
+
+// GameCalendar renders a single scheduled game as an .ics file. It returns nil if the user is
+// not one of the players.
+func (s *CalendarService) GameCalendar(userID, proposalID int64) ([]byte, error) {
+	proposal, err := s.proposalRepo.GetByID(proposalID)
+	if err != nil || proposal == nil {
+		return nil, err
+	}
+
+	match, err := s.swipeRepo.GetMatchByID(proposal.MatchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match: %w", err)
+	}
+	if match == nil || match.Partner(userID) == 0 {
+		return nil, nil
+	}
+	if proposal.Status != models.ProposalStatusAccepted && proposal.Status != models.ProposalStatusCancelled {
+		return nil, fmt.Errorf("game is not scheduled")
+	}
+
+	partnerID := match.Partner(userID)
+
+	calendar, err := s.calendar(userID, []models.ScheduledGame{{GameProposal: *proposal, PartnerID: partnerID}})
+	if err != nil {
+		return nil, err
+	}
+	return calendar.Encode(), nil
+}
+
+// calendar builds the user's calendar of games, in the time zone of their availability
+func (s *CalendarService) calendar(userID int64, games []models.ScheduledGame) (*ical.Calendar, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	partnerIDs := make([]int64, len(games))
+	for i, g := range games {
+		partnerIDs[i] = g.PartnerID
+	}
+	partners, err := s.userRepo.GetProfilesByIDs(partnerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	sportNames := make(map[string]string)
+	if sports, err := s.sportService.ListAllSports(); err != nil {
+		fmt.Printf("Failed to load sports catalog: %v\n", err)
+	} else {
+		for _, sport := range sports {
+			sportNames[sport.Slug] = sport.Name
+		}
+	}
+
+	events := make([]ical.Event, 0, len(games))
+	for _, g := range games {
+		events = append(events, gameEvent(g, sportNames, partners[g.PartnerID]))
+	}
+
+	return &ical.Calendar{
+		Name:     calendarName,
+		TimeZone: user.Availability.Location(),
+		Events:   events,
+	}, nil
+}
+
+func gameEvent(game models.ScheduledGame, sportNames map[string]string, partner *models.UserProfile) ical.Event {
+	sport := sportNames[game.Sport]
+	if sport == "" {
+		sport = game.Sport
+	}
+	summary := sport
+	if partner != nil {
+		summary = fmt.Sprintf("%s with %s", sport, partner.Name)
+	}
+
+	status := ical.StatusConfirmed
+	if game.Status == models.ProposalStatusCancelled {
+		status = ical.StatusCancelled
+		summary = "Cancelled: " + summary
+	}
+
+	event := ical.Event{
+		UID:      fmt.Sprintf("game-%d@%s", game.ID, config.AppConfig.Calendar.UIDDomain),
+		Sequence: game.Sequence,
+		Status:   status,
+		Summary:  summary,
+		Start:    game.StartsAt,
+		End:      game.EndsAt(),
+		Modified: game.UpdatedAt,
+	}
+	if game.Venue != nil {
+		event.Location = *game.Venue
+	}
+	if game.Note != nil {
+		event.Description = *game.Note
+	}
+	return event
+}
+
+func feedURL(token string) string {
+	return fmt.Sprintf("%s/api/v1/calendar/feeds/%s.ics", config.AppConfig.Calendar.PublicURL, token)
+}
+
+func newFeedToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
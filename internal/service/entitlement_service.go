@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+
+	"swipe-sports-backend/internal/config"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/repository"
+)
+
+type EntitlementService struct {
+	userRepo *repository.UserRepository
+}
+
+func NewEntitlementService() *EntitlementService {
+	return &EntitlementService{
+		userRepo: repository.NewUserRepository(),
+	}
+}
+
+// HasFeature reports whether the user's plan includes the feature
+func (s *EntitlementService) HasFeature(userID int64, feature models.Feature) (bool, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return false, nil
+	}
+
+	for _, f := range userPlan(user).Features {
+		if f == string(feature) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// planName is the user's plan, treating unknown plans as free
+func planName(user *models.User) models.Plan {
+	if _, ok := config.AppConfig.Plans[string(user.Plan)]; ok {
+		return user.Plan
+	}
+	return models.PlanFree
+}
+
+func userPlan(user *models.User) config.PlanConfig {
+	return config.AppConfig.Plans[string(planName(user))]
+}
@@ -0,0 +1,411 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/config"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/repository"
+)
+
+const (
+	maxGamesPerPage  = 50
+	autoConfirmBatch = 100
+	// reports may be stamped slightly ahead by a phone clock
+	maxPlayedAtSkew = 15 * time.Minute
+)
+
+type GameService struct {
+	gameRepo     *repository.GameRepository
+	swipeRepo    *repository.SwipeRepository
+	sportService *SportService
+	leaderboards *LeaderboardService
+	ladders      *LadderService
+}
+
+func NewGameService() *GameService {
+	return &GameService{
+		gameRepo:     repository.NewGameRepository(),
+		swipeRepo:    repository.NewSwipeRepository(),
+		sportService: NewSportService(),
+		leaderboards: NewLeaderboardService(),
+		ladders:      NewLadderService(),
+	}
+}
+
+// ReportGame records the result of a game played with a match partner or within a group, or
+// for an accepted ladder challenge. The other team has until the confirmation window closes to confirm or
+// dispute it.
+func (s *GameService) ReportGame(userID int64, req models.ReportGameRequest) (*models.Game, error) {
+	sport, err := s.sportService.GetSport(req.Sport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sport: %w", err)
+	}
+	if sport == nil || !sport.Active {
+		return nil, fmt.Errorf("unknown sport %s", req.Sport)
+	}
+
+	var matchID *int64
+	var partnerID int64
+	if req.ChallengeID != nil {
+		// Ladder games are singles between the challenger and the defender
+		if len(req.Participants) > 0 {
+			return nil, fmt.Errorf("ladder games are between the two players only")
+		}
+		if partnerID, err = s.ladders.challengeOpponent(userID, *req.ChallengeID, sport.Slug); err != nil {
+			return nil, err
+		}
+	} else {
+		match, err := s.swipeRepo.GetMatchByID(req.MatchID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get match: %w", err)
+		}
+		inMatch, err := s.swipeRepo.IsUserInMatch(userID, req.MatchID)
+		if err != nil {
+			return nil, err
+		}
+		if match == nil || !inMatch {
+			return nil, fmt.Errorf("match not found")
+		}
+		// A group has no single partner, so its games list their players
+		matchID, partnerID = &match.ID, match.Partner(userID)
+		if partnerID == 0 && len(req.Participants) == 0 {
+			return nil, fmt.Errorf("group games must list their players")
+		}
+	}
+
+	if req.PlayedAt.After(time.Now().Add(maxPlayedAtSkew)) {
+		return nil, fmt.Errorf("game cannot be played in the future")
+	}
+	if err := req.Score.Validate(); err != nil {
+		return nil, err
+	}
+
+	participants := req.Participants
+	if len(participants) == 0 {
+		participants = []models.GameParticipant{{UserID: userID, Team: 1}, {UserID: partnerID, Team: 2}}
+	}
+	if err := s.validateParticipants(participants, sport.TeamSize, userID, partnerID, matchID); err != nil {
+		return nil, err
+	}
+
+	game := &models.Game{
+		MatchID:         matchID,
+		ChallengeID:     req.ChallengeID,
+		Sport:           sport.Slug,
+		PlayedAt:        req.PlayedAt,
+		Score:           req.Score,
+		WinnerTeam:      req.Score.Winner(),
+		Status:          models.GameStatusPending,
+		ReportedBy:      userID,
+		ConfirmDeadline: time.Now().Add(config.AppConfig.Games.ConfirmWindow),
+		Participants:    participants,
+	}
+	if err := s.gameRepo.Create(game); err != nil {
+		return nil, err
+	}
+
+	game, err = s.gameRepo.GetByID(game.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyParticipants(game, userID)
+
+	return game, nil
+}
+
+// validateParticipants checks that the reporter and their partner, if any, played against each
+// other, on teams 1 and 2 only, with team sizes that fit the sport. Every other player must be
+// an active participant of the match or group.
+func (s *GameService) validateParticipants(participants []models.GameParticipant, teamSize models.TeamSize, userID, partnerID int64, matchID *int64) error {
+	teamOf := make(map[int64]int, len(participants))
+	teams := map[int]int{}
+	for _, p := range participants {
+		if p.Team != 1 && p.Team != 2 {
+			return fmt.Errorf("team must be 1 or 2")
+		}
+		if teamOf[p.UserID] != 0 {
+			return fmt.Errorf("user %d is listed twice", p.UserID)
+		}
+		teamOf[p.UserID] = p.Team
+		teams[p.Team]++
+	}
+	if teamOf[userID] == 0 {
+		return fmt.Errorf("you must be a participant")
+	}
+	if partnerID != 0 {
+		if teamOf[partnerID] == 0 {
+			return fmt.Errorf("both match users must be participants")
+		}
+		if teamOf[partnerID] == teamOf[userID] {
+			return fmt.Errorf("both match users must be on opposite teams")
+		}
+	}
+
+	switch teamSize {
+	case models.TeamSizeSingles:
+		// Singles sports are also played as doubles
+		if teams[1] != teams[2] || teams[1] > 2 {
+			return fmt.Errorf("games of this sport need one or two players per team")
+		}
+	case models.TeamSizeDoubles:
+		if teams[1] != 2 || teams[2] != 2 {
+			return fmt.Errorf("doubles games need two players per team")
+		}
+	default:
+		if teams[1] == 0 || teams[2] == 0 {
+			return fmt.Errorf("each team needs at least one player")
+		}
+	}
+
+	for id := range teamOf {
+		if id == userID || id == partnerID {
+			continue
+		}
+		inMatch := false
+		if matchID != nil {
+			var err error
+			if inMatch, err = s.swipeRepo.IsUserInMatch(id, *matchID); err != nil {
+				return err
+			}
+		}
+		if !inMatch {
+			return fmt.Errorf("user %d is not in this match", id)
+		}
+	}
+
+	return nil
+}
+
+// GetGame returns a game the user played in, or nil
+func (s *GameService) GetGame(userID, gameID int64) (*models.Game, error) {
+	game, err := s.gameRepo.GetByID(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game == nil || !game.HasParticipant(userID) {
+		return nil, nil
+	}
+	return game, nil
+}
+
+func (s *GameService) GetGames(userID int64) ([]models.Game, error) {
+	games, err := s.gameRepo.GetForUser(userID, maxGamesPerPage)
+	if err != nil {
+		return nil, err
+	}
+	if games == nil {
+		games = []models.Game{}
+	}
+	return games, nil
+}
+
+// ConfirmGame accepts a reported result. Only a player on the other team from the reporter
+// can confirm. It returns nil if the user did not play in the game.
+func (s *GameService) ConfirmGame(userID, gameID int64) (*models.Game, error) {
+	game, err := s.opponentGame(userID, gameID)
+	if err != nil || game == nil {
+		return nil, err
+	}
+	if game.Status != models.GameStatusPending {
+		return nil, fmt.Errorf("game is already %s", game.Status)
+	}
+
+	return s.confirm(gameID, models.GameStatusPending, userID)
+}
+
+// DisputeGame sends a reported result to the admin queue. Any player other than the reporter
+// can dispute, including their teammate, but only before the result auto-confirms.
+func (s *GameService) DisputeGame(userID, gameID int64, req models.DisputeGameRequest) (*models.Game, error) {
+	game, err := s.GetGame(userID, gameID)
+	if err != nil || game == nil {
+		return nil, err
+	}
+	if userID == game.ReportedBy {
+		return nil, fmt.Errorf("you cannot dispute your own result")
+	}
+	if game.Status != models.GameStatusPending {
+		return nil, fmt.Errorf("game is already %s", game.Status)
+	}
+
+	disputed, err := s.gameRepo.Dispute(gameID, userID, req.Reason, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !disputed {
+		return nil, fmt.Errorf("confirmation window has closed")
+	}
+
+	game, err = s.gameRepo.GetByID(gameID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyParticipants(game, userID)
+
+	return game, nil
+}
+
+// opponentGame loads a game for one of the players opposing the reporter. It returns nil if the
+// user did not play in it, and an error if they are on the reporter's team.
+func (s *GameService) opponentGame(userID, gameID int64) (*models.Game, error) {
+	game, err := s.GetGame(userID, gameID)
+	if err != nil || game == nil {
+		return nil, err
+	}
+	if game.TeamOf(userID) == game.TeamOf(game.ReportedBy) {
+		return nil, fmt.Errorf("only the opposing team can respond to a result")
+	}
+	return game, nil
+}
+
+// GetDisputedGames is the admin queue of disputed results, oldest first
+func (s *GameService) GetDisputedGames() ([]models.Game, error) {
+	games, err := s.gameRepo.GetByStatus(models.GameStatusDisputed, maxGamesPerPage)
+	if err != nil {
+		return nil, err
+	}
+	if games == nil {
+		games = []models.Game{}
+	}
+	return games, nil
+}
+
+// ResolveDispute lets an admin confirm a disputed game, optionally with a corrected score, or
+// void it. It returns nil if the game does not exist.
+func (s *GameService) ResolveDispute(adminID, gameID int64, req models.ResolveGameRequest) (*models.Game, error) {
+	game, err := s.gameRepo.GetByID(gameID)
+	if err != nil || game == nil {
+		return nil, err
+	}
+	if game.Status != models.GameStatusDisputed {
+		return nil, fmt.Errorf("game is not disputed")
+	}
+
+	score := game.Score
+	if req.Score != nil {
+		if err := req.Score.Validate(); err != nil {
+			return nil, err
+		}
+		score = *req.Score
+	}
+
+	if err := s.gameRepo.Resolve(gameID, adminID, score, req.Note); err != nil {
+		return nil, err
+	}
+
+	if req.Outcome == models.GameStatusConfirmed {
+		return s.confirm(gameID, models.GameStatusDisputed, adminID)
+	}
+
+	voided, err := s.gameRepo.Void(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !voided {
+		return nil, fmt.Errorf("game was already resolved")
+	}
+
+	game, err = s.gameRepo.GetByID(gameID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyParticipants(game, adminID)
+
+	return game, nil
+}
+
+// AutoConfirmExpired confirms every pending game whose confirmation window has closed and
+// returns how many it confirmed. Games answered concurrently are skipped.
+func (s *GameService) AutoConfirmExpired() (int, error) {
+	confirmed := 0
+	for {
+		ids, err := s.gameRepo.GetExpiredPendingIDs(time.Now(), autoConfirmBatch)
+		if err != nil {
+			return confirmed, err
+		}
+
+		progress := false
+		for _, id := range ids {
+			game, err := s.confirm(id, models.GameStatusPending, 0)
+			if err != nil {
+				fmt.Printf("Failed to auto-confirm game %d: %v\n", id, err)
+				continue
+			}
+			if game != nil {
+				confirmed++
+				progress = true
+			}
+		}
+
+		if len(ids) < autoConfirmBatch || !progress {
+			return confirmed, nil
+		}
+	}
+}
+
+// RunAutoConfirm calls AutoConfirmExpired every interval, forever. Several servers can run it
+// at once: each game is only confirmed by whichever gets there first.
+func (s *GameService) RunAutoConfirm(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := s.AutoConfirmExpired(); err != nil {
+			fmt.Printf("Failed to auto-confirm games: %v\n", err)
+		}
+	}
+}
+
+// confirm is the single path by which a game becomes confirmed, updating the players' ratings
+// in the same transaction. It returns nil if the game was no longer in the expected status.
+func (s *GameService) confirm(gameID int64, from models.GameStatus, actorID int64) (*models.Game, error) {
+	confirmed, err := s.gameRepo.Confirm(gameID, from, rateGame, ratePartnerships)
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		if actorID != 0 {
+			return nil, fmt.Errorf("game was already answered")
+		}
+		return nil, nil
+	}
+
+	game, err := s.gameRepo.GetByID(gameID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyParticipants(game, actorID)
+
+	userIDs := make([]int64, len(game.Participants))
+	for i, p := range game.Participants {
+		userIDs[i] = p.UserID
+	}
+	if err := s.leaderboards.SyncPlayers(userIDs, game.Sport, game.Format()); err != nil {
+		fmt.Printf("Failed to update leaderboards: %v\n", err)
+	}
+	if game.ChallengeID != nil {
+		if err := s.ladders.settleChallenge(game); err != nil {
+			fmt.Printf("Failed to settle ladder challenge: %v\n", err)
+		}
+	}
+
+	return game, nil
+}
+
+// notifyParticipants tells every player except the one who acted about the game's new state
+func (s *GameService) notifyParticipants(game *models.Game, actorID int64) {
+	if game == nil {
+		return
+	}
+
+	event := models.WSMessage{Type: models.WSMessageTypeGameResult, Payload: game}
+	for _, p := range game.Participants {
+		if p.UserID != actorID {
+			notifyUser(p.UserID, event)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/repository"
+)
+
+// maxGroupSize caps the active participants of a group, the host included
+const maxGroupSize = 30
+
+type GroupService struct {
+	swipeRepo    *repository.SwipeRepository
+	groupRepo    *repository.GroupRepository
+	sportService *SportService
+}
+
+func NewGroupService() *GroupService {
+	return &GroupService{
+		swipeRepo:    repository.NewSwipeRepository(),
+		groupRepo:    repository.NewGroupRepository(),
+		sportService: NewSportService(),
+	}
+}
+
+// CreateGroup starts a group chat hosted by the user with some of their matches
+func (s *GroupService) CreateGroup(hostID int64, req models.CreateGroupRequest) (*models.Match, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	if req.Sport != nil {
+		sport, err := s.sportService.GetSport(*req.Sport)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sport: %w", err)
+		}
+		if sport == nil || !sport.Active {
+			return nil, fmt.Errorf("unknown sport %s", *req.Sport)
+		}
+	}
+
+	memberIDs := uniqueIDs(req.UserIDs, map[int64]bool{hostID: true})
+	if len(memberIDs) == 0 {
+		return nil, fmt.Errorf("invite at least one other player")
+	}
+	if len(memberIDs)+1 > maxGroupSize {
+		return nil, fmt.Errorf("a group can have at most %d players", maxGroupSize)
+	}
+	if err := s.checkInvitable(hostID, memberIDs); err != nil {
+		return nil, err
+	}
+
+	group := &models.Match{Kind: models.MatchKindGroup, User1ID: hostID, Title: &title, Sport: req.Sport}
+	if err := s.groupRepo.Create(group, memberIDs); err != nil {
+		return nil, err
+	}
+
+	return s.afterChange(group.ID, memberIDs, models.ParticipantStatusActive)
+}
+
+// GetGroups returns the user's active groups, newest first
+func (s *GroupService) GetGroups(userID int64) ([]models.Match, error) {
+	groups, err := s.groupRepo.GetForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		if groups[i].Participants, err = s.groupRepo.GetParticipants(groups[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+// GetGroup returns a group with its participants, or nil if the user is not in it
+func (s *GroupService) GetGroup(userID, groupID int64) (*models.Match, error) {
+	inGroup, err := s.swipeRepo.IsUserInMatch(userID, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check group: %w", err)
+	}
+	if !inGroup {
+		return nil, nil
+	}
+	return s.loadGroup(groupID)
+}
+
+// Invite adds some of the host's matches to the group. Players who left or were removed can
+// be invited back.
+func (s *GroupService) Invite(hostID, groupID int64, req models.InviteToGroupRequest) (*models.Match, error) {
+	group, err := s.GetGroup(hostID, groupID)
+	if err != nil || group == nil {
+		return nil, err
+	}
+	if host := group.Host(); host == nil || host.UserID != hostID {
+		return nil, fmt.Errorf("only the host can invite players")
+	}
+
+	active := make(map[int64]bool)
+	for _, p := range group.Participants {
+		if p.Status == models.ParticipantStatusActive {
+			active[p.UserID] = true
+		}
+	}
+	userIDs := uniqueIDs(req.UserIDs, active)
+	if len(userIDs) == 0 {
+		return nil, fmt.Errorf("those players are already in the group")
+	}
+	if err := s.checkInvitable(hostID, userIDs); err != nil {
+		return nil, err
+	}
+
+	added, err := s.groupRepo.AddParticipants(groupID, userIDs, maxGroupSize)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, fmt.Errorf("a group can have at most %d players", maxGroupSize)
+	}
+
+	return s.afterChange(groupID, userIDs, models.ParticipantStatusActive)
+}
+
+// Leave takes the user out of a group. The host's role passes to the longest-standing member
+// and the group ends once nobody is left. It returns nil if the user is not in the group.
+func (s *GroupService) Leave(userID, groupID int64) (*models.Match, error) {
+	group, err := s.GetGroup(userID, groupID)
+	if err != nil || group == nil {
+		return nil, err
+	}
+
+	left, err := s.groupRepo.RemoveParticipant(groupID, userID, models.ParticipantStatusLeft)
+	if err != nil || !left {
+		return nil, err
+	}
+
+	return s.afterChange(groupID, []int64{userID}, models.ParticipantStatusLeft)
+}
+
+// Kick removes a player from the host's group. It returns nil if the host is not in the group.
+func (s *GroupService) Kick(hostID, groupID, userID int64) (*models.Match, error) {
+	group, err := s.GetGroup(hostID, groupID)
+	if err != nil || group == nil {
+		return nil, err
+	}
+	if host := group.Host(); host == nil || host.UserID != hostID {
+		return nil, fmt.Errorf("only the host can remove players")
+	}
+	if userID == hostID {
+		return nil, fmt.Errorf("hosts leave their group rather than remove themselves")
+	}
+
+	removed, err := s.groupRepo.RemoveParticipant(groupID, userID, models.ParticipantStatusRemoved)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, fmt.Errorf("player is not in the group")
+	}
+
+	return s.afterChange(groupID, []int64{userID}, models.ParticipantStatusRemoved)
+}
+
+// checkInvitable makes sure the host has an active one-to-one match with every player
+func (s *GroupService) checkInvitable(hostID int64, userIDs []int64) error {
+	for _, userID := range userIDs {
+		match, err := s.swipeRepo.GetMatchBetween(hostID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get match: %w", err)
+		}
+		if match == nil || match.Status != models.MatchStatusActive {
+			return fmt.Errorf("you can only invite players you have matched with")
+		}
+	}
+	return nil
+}
+
+func (s *GroupService) loadGroup(groupID int64) (*models.Match, error) {
+	group, err := s.swipeRepo.GetMatchByID(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+	if group == nil || group.Kind != models.MatchKindGroup {
+		return nil, nil
+	}
+	if group.Participants, err = s.groupRepo.GetParticipants(groupID); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// afterChange reloads the group and tells everyone in it, and anyone who just left it, which
+// players joined, left or were removed
+func (s *GroupService) afterChange(groupID int64, userIDs []int64, change models.ParticipantStatus) (*models.Match, error) {
+	group, err := s.loadGroup(groupID)
+	if err != nil || group == nil {
+		return nil, err
+	}
+
+	event := models.WSMessage{
+		Type:    models.WSMessageTypeGroupUpdated,
+		Payload: models.WSGroupMessage{MatchID: groupID, UserIDs: userIDs, Change: change, Group: group},
+	}
+	for _, p := range group.Participants {
+		if p.Status == models.ParticipantStatusActive {
+			notifyUser(p.UserID, event)
+		}
+	}
+
+	// The departing players' chat connections for the group close on this event
+	if change != models.ParticipantStatusActive {
+		event = models.WSMessage{
+			Type:    models.WSMessageTypeGroupRemoved,
+			Payload: models.WSGroupMessage{MatchID: groupID, UserIDs: userIDs, Change: change},
+		}
+		for _, userID := range userIDs {
+			notifyUser(userID, event)
+		}
+	}
+
+	return group, nil
+}
+
+// checkGroupMember fails unless groupID is a group chat the user is in
+func checkGroupMember(swipeRepo *repository.SwipeRepository, userID, groupID int64) error {
+	group, err := swipeRepo.GetMatchByID(groupID)
+	if err != nil {
+		return fmt.Errorf("failed to get group: %w", err)
+	}
+	inGroup, err := swipeRepo.IsUserInMatch(userID, groupID)
+	if err != nil {
+		return fmt.Errorf("failed to check group: %w", err)
+	}
+	if group == nil || group.Kind != models.MatchKindGroup || !inGroup {
+		return fmt.Errorf("you are not in that group")
+	}
+	return nil
+}
+
+// uniqueIDs returns the IDs in their original order without duplicates or the excluded ones
+func uniqueIDs(ids []int64, exclude map[int64]bool) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := []int64{}
+	for _, id := range ids {
+		if exclude[id] || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
@@ -0,0 +1,518 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/repository"
+)
+
+const (
+	maxLaddersPerPage = 50
+	// challenges shown with a ladder: the open ones and then the latest results
+	maxLadderChallenges = 30
+	overdueBatch        = 100
+
+	defaultChallengeRange = 3
+	defaultAcceptHours    = 72
+	defaultPlayHours      = 14 * 24
+)
+
+type LadderService struct {
+	ladderRepo   *repository.LadderRepository
+	swipeRepo    *repository.SwipeRepository
+	userRepo     *repository.UserRepository
+	sportService *SportService
+}
+
+func NewLadderService() *LadderService {
+	return &LadderService{
+		ladderRepo:   repository.NewLadderRepository(),
+		swipeRepo:    repository.NewSwipeRepository(),
+		userRepo:     repository.NewUserRepository(),
+		sportService: NewSportService(),
+	}
+}
+
+// CreateLadder starts an empty ladder. A ladder run for a group chat is only visible to the
+// group's members.
+func (s *LadderService) CreateLadder(organizerID int64, req models.CreateLadderRequest) (*models.Ladder, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	sport, err := s.sportService.GetSport(req.Sport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sport: %w", err)
+	}
+	if sport == nil || !sport.Active {
+		return nil, fmt.Errorf("unknown sport %s", req.Sport)
+	}
+	// Challenges are played one against one
+	if sport.TeamSize != models.TeamSizeSingles {
+		return nil, fmt.Errorf("ladders are only for singles sports")
+	}
+	if req.GroupID != nil {
+		if err := checkGroupMember(s.swipeRepo, organizerID, *req.GroupID); err != nil {
+			return nil, err
+		}
+	}
+
+	ladder := &models.Ladder{
+		OrganizerID:    organizerID,
+		GroupID:        req.GroupID,
+		Name:           name,
+		Sport:          sport.Slug,
+		ChallengeRange: defaultChallengeRange,
+		AcceptHours:    defaultAcceptHours,
+		PlayHours:      defaultPlayHours,
+		Status:         models.LadderStatusActive,
+	}
+	if req.ChallengeRange > 0 {
+		ladder.ChallengeRange = req.ChallengeRange
+	}
+	if req.AcceptHours > 0 {
+		ladder.AcceptHours = req.AcceptHours
+	}
+	if req.PlayHours > 0 {
+		ladder.PlayHours = req.PlayHours
+	}
+	if err := s.ladderRepo.Create(ladder); err != nil {
+		return nil, err
+	}
+	return s.GetLadder(organizerID, ladder.ID)
+}
+
+// ListLadders returns the active ladders, newest first: the public ones, or a group's when the
+// query names a group the user is in
+func (s *LadderService) ListLadders(userID int64, query models.LadderQuery) ([]models.Ladder, error) {
+	if query.GroupID != nil {
+		if err := checkGroupMember(s.swipeRepo, userID, *query.GroupID); err != nil {
+			return nil, err
+		}
+	}
+
+	ladders, err := s.ladderRepo.List(query.Sport, query.GroupID, maxLaddersPerPage)
+	if err != nil {
+		return nil, err
+	}
+	return ladders, s.attachOrganizers(ladders)
+}
+
+// GetMyLadders returns the ladders the user organizes or plays on, newest first
+func (s *LadderService) GetMyLadders(userID int64) ([]models.Ladder, error) {
+	ladders, err := s.ladderRepo.GetForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return ladders, s.attachOrganizers(ladders)
+}
+
+// GetLadder returns a ladder with its standings and challenges, or nil if the user cannot see
+// it
+func (s *LadderService) GetLadder(userID, ladderID int64) (*models.Ladder, error) {
+	ladder, err := s.ladderRepo.GetByID(ladderID)
+	if err != nil || ladder == nil {
+		return nil, err
+	}
+	if err := s.describe(ladder); err != nil {
+		return nil, err
+	}
+
+	visible, err := s.canSee(userID, ladder)
+	if err != nil || !visible {
+		return nil, err
+	}
+	return ladder, nil
+}
+
+// Join puts the user at the bottom of the ladder. It returns nil if the user cannot see it.
+func (s *LadderService) Join(userID, ladderID int64) (*models.Ladder, error) {
+	ladder, err := s.GetLadder(userID, ladderID)
+	if err != nil || ladder == nil {
+		return nil, err
+	}
+	if ladder.GroupID != nil {
+		if err := checkGroupMember(s.swipeRepo, userID, *ladder.GroupID); err != nil {
+			return nil, err
+		}
+	}
+
+	joined, err := s.ladderRepo.Join(ladderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !joined {
+		return nil, fmt.Errorf("you are already on this ladder, or it is closed")
+	}
+
+	return s.publish(ladderID, nil)
+}
+
+// Leave takes the user off the ladder; everyone below moves up and the user's open challenges
+// are cancelled. It returns nil if the user cannot see the ladder.
+func (s *LadderService) Leave(userID, ladderID int64) (*models.Ladder, error) {
+	ladder, err := s.GetLadder(userID, ladderID)
+	if err != nil || ladder == nil {
+		return nil, err
+	}
+
+	left, err := s.ladderRepo.Leave(ladderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !left {
+		return nil, fmt.Errorf("you are not on this ladder")
+	}
+
+	return s.publish(ladderID, nil, userID)
+}
+
+// Close stops the ladder and cancels its open challenges. It returns nil if the ladder is not
+// the organizer's.
+func (s *LadderService) Close(organizerID, ladderID int64) (*models.Ladder, error) {
+	ladder, err := s.GetLadder(organizerID, ladderID)
+	if err != nil || ladder == nil || ladder.OrganizerID != organizerID {
+		return nil, err
+	}
+
+	closed, err := s.ladderRepo.Close(ladderID)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, fmt.Errorf("ladder is already closed")
+	}
+
+	return s.publish(ladderID, nil)
+}
+
+// Challenge challenges a player at most the ladder's challenge range above the user. Neither
+// player can have another open challenge on the ladder. It returns nil if the user cannot see
+// the ladder.
+func (s *LadderService) Challenge(userID, ladderID int64, req models.CreateChallengeRequest) (*models.LadderChallenge, error) {
+	ladder, err := s.GetLadder(userID, ladderID)
+	if err != nil || ladder == nil {
+		return nil, err
+	}
+	if req.DefenderID == userID {
+		return nil, fmt.Errorf("you cannot challenge yourself")
+	}
+
+	challenge := &models.LadderChallenge{
+		LadderID:       ladderID,
+		ChallengerID:   userID,
+		DefenderID:     req.DefenderID,
+		Status:         models.ChallengeStatusPending,
+		AcceptDeadline: time.Now().Add(time.Duration(ladder.AcceptHours) * time.Hour),
+	}
+	created, err := s.ladderRepo.CreateChallenge(challenge, func(l *models.Ladder, challenger, defender *models.LadderPlayer, busy bool) error {
+		switch {
+		case challenger == nil:
+			return fmt.Errorf("you are not on this ladder")
+		case defender == nil:
+			return fmt.Errorf("that player is not on this ladder")
+		case !l.CanChallenge(challenger.Position, defender.Position):
+			return fmt.Errorf("you can only challenge players up to %d places above you", l.ChallengeRange)
+		case busy:
+			return fmt.Errorf("you or that player already have an open challenge")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, fmt.Errorf("ladder is closed")
+	}
+
+	return s.afterChallenge(challenge.ID)
+}
+
+// AcceptChallenge takes up a challenge before its acceptance deadline; the game must then be
+// played before the play deadline. It returns nil if the challenge does not exist.
+func (s *LadderService) AcceptChallenge(userID, challengeID int64) (*models.LadderChallenge, error) {
+	challenge, ladder, err := s.getChallenge(userID, challengeID)
+	if err != nil || challenge == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	playDeadline := now.Add(time.Duration(ladder.PlayHours) * time.Hour)
+	accepted, err := s.ladderRepo.Accept(challengeID, userID, now, playDeadline)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, fmt.Errorf("only the challenged player can accept, before the deadline")
+	}
+
+	return s.afterChallenge(challengeID)
+}
+
+// ForfeitChallenge concedes an open challenge: if the defender forfeits, the challenger takes
+// their place. It returns nil if the challenge does not exist.
+func (s *LadderService) ForfeitChallenge(userID, challengeID int64) (*models.LadderChallenge, error) {
+	challenge, _, err := s.getChallenge(userID, challengeID)
+	if err != nil || challenge == nil {
+		return nil, err
+	}
+
+	forfeited, err := s.ladderRepo.Forfeit(challengeID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !forfeited {
+		return nil, fmt.Errorf("challenge has already been decided, or has a game reported for it")
+	}
+
+	return s.afterChallenge(challengeID)
+}
+
+// challengeOpponent checks that a game for the challenge can be reported by the user in the
+// sport and returns the opponent
+func (s *LadderService) challengeOpponent(userID, challengeID int64, sport string) (int64, error) {
+	challenge, ladder, err := s.getChallenge(userID, challengeID)
+	if err != nil {
+		return 0, err
+	}
+	if challenge == nil || challenge.Other(userID) == 0 {
+		return 0, fmt.Errorf("challenge not found")
+	}
+	if challenge.Status != models.ChallengeStatusAccepted {
+		return 0, fmt.Errorf("challenge must be accepted before its game is reported")
+	}
+	if ladder.Sport != sport {
+		return 0, fmt.Errorf("this ladder is for %s", ladder.Sport)
+	}
+
+	reported, err := s.ladderRepo.HasGame(challengeID)
+	if err != nil {
+		return 0, err
+	}
+	if reported {
+		return 0, fmt.Errorf("a game was already reported for this challenge")
+	}
+	return challenge.Other(userID), nil
+}
+
+// settleChallenge decides the challenge a confirmed game was reported for; if the challenger
+// won, the two players swap places
+func (s *LadderService) settleChallenge(game *models.Game) error {
+	var winnerID int64
+	if game.WinnerTeam != nil {
+		for _, p := range game.Participants {
+			if p.Team == *game.WinnerTeam {
+				winnerID = p.UserID
+			}
+		}
+	}
+
+	settled, err := s.ladderRepo.Settle(*game.ChallengeID, game.ID, winnerID)
+	if err != nil || !settled {
+		return err
+	}
+
+	_, err = s.afterChallenge(*game.ChallengeID)
+	return err
+}
+
+// ResolveOverdue applies the deadlines of every challenge that has run out of time and returns
+// how many it resolved: challenges not accepted in time are forfeited by the defender, and
+// accepted ones never played expire.
+func (s *LadderService) ResolveOverdue() (int, error) {
+	resolved := 0
+	for {
+		now := time.Now()
+		ids, err := s.ladderRepo.GetOverdueIDs(now, overdueBatch)
+		if err != nil {
+			return resolved, err
+		}
+
+		progress := false
+		for _, id := range ids {
+			done, err := s.ladderRepo.ResolveOverdue(id, now)
+			if err != nil {
+				fmt.Printf("Failed to resolve challenge %d: %v\n", id, err)
+				continue
+			}
+			if done {
+				resolved++
+				progress = true
+				if _, err := s.afterChallenge(id); err != nil {
+					fmt.Printf("Failed to publish challenge %d: %v\n", id, err)
+				}
+			}
+		}
+
+		if len(ids) < overdueBatch || !progress {
+			return resolved, nil
+		}
+	}
+}
+
+// RunDeadlines calls ResolveOverdue every interval, forever. Several servers can run it at
+// once: each challenge is resolved under its ladder's lock by whichever gets there first.
+func (s *LadderService) RunDeadlines(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := s.ResolveOverdue(); err != nil {
+			fmt.Printf("Failed to resolve overdue challenges: %v\n", err)
+		}
+	}
+}
+
+// getChallenge returns a challenge and its ladder, or nil if the user cannot see the ladder
+func (s *LadderService) getChallenge(userID, challengeID int64) (*models.LadderChallenge, *models.Ladder, error) {
+	challenge, err := s.ladderRepo.GetChallenge(challengeID)
+	if err != nil || challenge == nil {
+		return nil, nil, err
+	}
+	ladder, err := s.ladderRepo.GetByID(challenge.LadderID)
+	if err != nil || ladder == nil {
+		return nil, nil, err
+	}
+
+	visible, err := s.canSee(userID, ladder)
+	if err != nil || !visible {
+		return nil, nil, err
+	}
+	return challenge, ladder, nil
+}
+
+// afterChallenge reloads a challenge that changed and publishes its ladder
+func (s *LadderService) afterChallenge(challengeID int64) (*models.LadderChallenge, error) {
+	challenge, err := s.ladderRepo.GetChallenge(challengeID)
+	if err != nil || challenge == nil {
+		return nil, err
+	}
+	challenges := []models.LadderChallenge{*challenge}
+	if err := s.attachPlayers(challenges); err != nil {
+		return nil, err
+	}
+	challenge = &challenges[0]
+
+	if _, err := s.publish(challenge.LadderID, challenge); err != nil {
+		fmt.Printf("Failed to publish ladder %d: %v\n", challenge.LadderID, err)
+	}
+	return challenge, nil
+}
+
+// canSee allows everyone to see public ladders, and the organizer, players and group members
+// to see a group's
+func (s *LadderService) canSee(userID int64, ladder *models.Ladder) (bool, error) {
+	if ladder.GroupID == nil || ladder.OrganizerID == userID {
+		return true, nil
+	}
+
+	players := ladder.Standings
+	if players == nil {
+		var err error
+		if players, err = s.ladderRepo.GetPlayers(ladder.ID); err != nil {
+			return false, err
+		}
+	}
+	for _, p := range players {
+		if p.UserID == userID {
+			return true, nil
+		}
+	}
+
+	inGroup, err := s.swipeRepo.IsUserInMatch(userID, *ladder.GroupID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check group: %w", err)
+	}
+	return inGroup, nil
+}
+
+// describe fills in the organizer, the standings and the challenges
+func (s *LadderService) describe(ladder *models.Ladder) error {
+	organizer, err := s.userRepo.GetByID(ladder.OrganizerID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if organizer != nil {
+		profile := organizer.Profile()
+		ladder.Organizer = &profile
+	}
+
+	if ladder.Standings, err = s.ladderRepo.GetPlayers(ladder.ID); err != nil {
+		return err
+	}
+	if ladder.Challenges, err = s.ladderRepo.GetChallenges(ladder.ID, maxLadderChallenges); err != nil {
+		return err
+	}
+	// Challenges can involve players who have since left, so their profiles are looked up
+	// rather than taken from the standings
+	return s.attachPlayers(ladder.Challenges)
+}
+
+// attachPlayers fills in the challengers' and defenders' profiles
+func (s *LadderService) attachPlayers(challenges []models.LadderChallenge) error {
+	ids := []int64{}
+	for _, c := range challenges {
+		ids = append(ids, c.ChallengerID, c.DefenderID)
+	}
+	profiles, err := s.userRepo.GetProfilesByIDs(uniqueIDs(ids, nil))
+	if err != nil {
+		return err
+	}
+
+	for i := range challenges {
+		challenges[i].Challenger = profiles[challenges[i].ChallengerID]
+		challenges[i].Defender = profiles[challenges[i].DefenderID]
+	}
+	return nil
+}
+
+func (s *LadderService) attachOrganizers(ladders []models.Ladder) error {
+	ids := make([]int64, len(ladders))
+	for i, l := range ladders {
+		ids[i] = l.OrganizerID
+	}
+	profiles, err := s.userRepo.GetProfilesByIDs(ids)
+	if err != nil {
+		return err
+	}
+	for i := range ladders {
+		ladders[i].Organizer = profiles[ladders[i].OrganizerID]
+	}
+	return nil
+}
+
+// publish reloads the ladder and pushes it to the organizer, the players and anyone else
+// affected, such as a player who just left
+func (s *LadderService) publish(ladderID int64, challenge *models.LadderChallenge, also ...int64) (*models.Ladder, error) {
+	ladder, err := s.ladderRepo.GetByID(ladderID)
+	if err != nil || ladder == nil {
+		return nil, err
+	}
+	if err := s.describe(ladder); err != nil {
+		return nil, err
+	}
+
+	event := models.WSMessage{
+		Type:    models.WSMessageTypeLadderUpdated,
+		Payload: models.WSLadderMessage{Ladder: ladder, Challenge: challenge},
+	}
+	recipients := append([]int64{ladder.OrganizerID}, also...)
+	for _, p := range ladder.Standings {
+		recipients = append(recipients, p.UserID)
+	}
+	if challenge != nil {
+		recipients = append(recipients, challenge.ChallengerID, challenge.DefenderID)
+	}
+	for _, id := range uniqueIDs(recipients, nil) {
+		notifyUser(id, event)
+	}
+
+	return ladder, nil
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/config"
+	"swipe-sports-backend/internal/geo"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/rating"
+	"swipe-sports-backend/internal/redis"
+	"swipe-sports-backend/internal/repository"
+)
+
+const (
+	defaultLeaderboardSize = 20
+	// players shown on each side of the user in their standing
+	leaderboardNeighbours = 5
+)
+
+type LeaderboardService struct {
+	ratingRepo *repository.RatingRepository
+	userRepo   *repository.UserRepository
+}
+
+func NewLeaderboardService() *LeaderboardService {
+	return &LeaderboardService{
+		ratingRepo: repository.NewRatingRepository(),
+		userRepo:   repository.NewUserRepository(),
+	}
+}
+
+// GetLeaderboard returns the top players of a sport, globally or in a region
+func (s *LeaderboardService) GetLeaderboard(userID int64, sport string, query models.LeaderboardQuery) (*models.Leaderboard, error) {
+	format, region, err := s.resolveQuery(userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultLeaderboardSize
+	}
+
+	members, total, err := redis.GetLeaderboardRange(redis.LeaderboardKeyFor(sport, string(format), region), 0, int64(limit-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+
+	entries, err := s.entries(members, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Leaderboard{
+		Sport:   sport,
+		Format:  format,
+		Region:  region,
+		Total:   total,
+		Entries: entries,
+	}, nil
+}
+
+// GetStanding returns the user's rank and the players just above and below them
+func (s *LeaderboardService) GetStanding(userID int64, sport string, query models.LeaderboardQuery) (*models.LeaderboardStanding, error) {
+	format, region, err := s.resolveQuery(userID, query)
+	if err != nil {
+		return nil, err
+	}
+	key := redis.LeaderboardKeyFor(sport, string(format), region)
+
+	standing := &models.LeaderboardStanding{
+		Sport:  sport,
+		Format: format,
+		Region: region,
+		Around: []models.LeaderboardEntry{},
+	}
+
+	rank, ranked, err := redis.GetLeaderboardRank(key, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rank: %w", err)
+	}
+	if !ranked {
+		total, err := redis.GetLeaderboardSize(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+		}
+		standing.Total = total
+		return standing, nil
+	}
+
+	start := rank - leaderboardNeighbours
+	if start < 0 {
+		start = 0
+	}
+	members, total, err := redis.GetLeaderboardRange(key, start, rank+leaderboardNeighbours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+
+	entries, err := s.entries(members, start)
+	if err != nil {
+		return nil, err
+	}
+
+	position := rank + 1
+	standing.Rank = &position
+	standing.Total = total
+	standing.Around = entries
+	return standing, nil
+}
+
+// resolveQuery applies the defaults to a leaderboard query and turns region=me into the
+// user's own region
+func (s *LeaderboardService) resolveQuery(userID int64, query models.LeaderboardQuery) (models.RatingFormat, string, error) {
+	format := query.Format
+	if format == "" {
+		format = models.RatingFormatSingles
+	}
+
+	region := query.Region
+	if region == "me" {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil {
+			return "", "", fmt.Errorf("user not found")
+		}
+		region = userRegion(user.Location)
+		if region == "" {
+			return "", "", fmt.Errorf("set your location to see your region's leaderboard")
+		}
+	}
+
+	return format, region, nil
+}
+
+// entries fills in the players' names; start is the 0-based rank of the first member
+func (s *LeaderboardService) entries(members []redis.LeaderboardMember, start int64) ([]models.LeaderboardEntry, error) {
+	ids := make([]int64, len(members))
+	for i, m := range members {
+		ids[i] = m.UserID
+	}
+
+	profiles, err := s.userRepo.GetProfilesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.LeaderboardEntry, 0, len(members))
+	for i, m := range members {
+		entry := models.LeaderboardEntry{
+			Rank:   start + int64(i) + 1,
+			UserID: m.UserID,
+			Rating: m.Score,
+		}
+		// Deleted users stay ranked until the next rebuild
+		if profile, ok := profiles[m.UserID]; ok {
+			entry.Name = profile.Name
+			entry.ProfilePicURL = profile.ProfilePicURL
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// SyncPlayers brings the users' leaderboard entries for a sport and format in line with their
+// current ratings. It runs after a game is confirmed.
+func (s *LeaderboardService) SyncPlayers(userIDs []int64, sport string, format models.RatingFormat) error {
+	ratings, err := s.ratingRepo.GetForUsers(userIDs)
+	if err != nil {
+		return err
+	}
+	profiles, err := s.userRepo.GetProfilesByIDs(userIDs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, userID := range userIDs {
+		profile, ok := profiles[userID]
+		if !ok {
+			continue
+		}
+		keys := leaderboardKeys(sport, format, userRegion(profile.Location))
+
+		var current *models.SportRating
+		for i, r := range ratings[userID] {
+			if r.Sport == sport && r.Format == format {
+				current = &ratings[userID][i]
+			}
+		}
+
+		if current != nil && rankable(*current, now) {
+			err = redis.SetLeaderboardScore(keys, userID, current.Rating)
+		} else {
+			err = redis.RemoveFromLeaderboards(keys, userID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update leaderboards: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Rebuild recreates every leaderboard from the ratings in MySQL, dropping players who are no
+// longer active and anything the incremental updates missed. It returns the number of ranked
+// ratings.
+func (s *LeaderboardService) Rebuild() (int, error) {
+	activeSince := time.Now().Add(-config.AppConfig.Leaderboard.ActiveWindow)
+	ratings, err := s.ratingRepo.GetRankable(activeSince, rating.ProvisionalDeviation)
+	if err != nil {
+		return 0, err
+	}
+
+	boards := make(map[string][]redis.LeaderboardMember)
+	for _, r := range ratings {
+		member := redis.LeaderboardMember{UserID: r.UserID, Score: r.Rating}
+		for _, key := range leaderboardKeys(r.Sport, r.Format, userRegion(r.Location)) {
+			boards[key] = append(boards[key], member)
+		}
+	}
+
+	if err := redis.ReplaceLeaderboards(boards); err != nil {
+		return 0, err
+	}
+	return len(ratings), nil
+}
+
+// RunRebuild calls Rebuild now and then every interval, forever
+func (s *LeaderboardService) RunRebuild(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Rebuild(); err != nil {
+			fmt.Printf("Failed to rebuild leaderboards: %v\n", err)
+		}
+		<-ticker.C
+	}
+}
+
+// relocateOnLeaderboards moves a user's entries to their new region's leaderboards after a
+// location change. Failures are only logged; the next rebuild corrects them.
+func relocateOnLeaderboards(userID int64, from, to string) {
+	if from == to {
+		return
+	}
+
+	ratings, err := repository.NewRatingRepository().GetForUsers([]int64{userID})
+	if err != nil {
+		fmt.Printf("Failed to load ratings: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	for _, r := range ratings[userID] {
+		if from != "" {
+			if err := redis.RemoveFromLeaderboards([]string{redis.LeaderboardKeyFor(r.Sport, string(r.Format), from)}, userID); err != nil {
+				fmt.Printf("Failed to update leaderboards: %v\n", err)
+			}
+		}
+		if to != "" && rankable(r, now) {
+			if err := redis.SetLeaderboardScore([]string{redis.LeaderboardKeyFor(r.Sport, string(r.Format), to)}, userID, r.Rating); err != nil {
+				fmt.Printf("Failed to update leaderboards: %v\n", err)
+			}
+		}
+	}
+}
+
+// leaderboardKeys are the global leaderboard and, for users with a location, their region's
+func leaderboardKeys(sport string, format models.RatingFormat, region string) []string {
+	keys := []string{redis.LeaderboardKeyFor(sport, string(format), "")}
+	if region != "" {
+		keys = append(keys, redis.LeaderboardKeyFor(sport, string(format), region))
+	}
+	return keys
+}
+
+// rankable reports whether a rating belongs on the leaderboards: established, and earned
+// recently enough
+func rankable(r models.SportRating, now time.Time) bool {
+	if r.Provisional || r.LastPlayedAt == nil {
+		return false
+	}
+	return now.Sub(*r.LastPlayedAt) <= config.AppConfig.Leaderboard.ActiveWindow
+}
+
+func userRegion(location *string) string {
+	if location == nil {
+		return ""
+	}
+	return geo.Region(*location)
+}
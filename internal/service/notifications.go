@@ -0,0 +1,22 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/redis"
+)
+
+// notifyUser pushes a live event to the user's open WebSocket connections. Delivery is best
+// effort: users who are offline simply miss it.
+func notifyUser(userID int64, message models.WSMessage) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		fmt.Printf("Failed to marshal notification: %v\n", err)
+		return
+	}
+	if err := redis.PublishUserEvent(userID, data); err != nil {
+		fmt.Printf("Failed to publish notification: %v\n", err)
+	}
+}
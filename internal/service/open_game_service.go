@@ -0,0 +1,436 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/repository"
+)
+
+const (
+	// openGameFeedHorizon is how far ahead the board looks when the query sets no end
+	openGameFeedHorizon = 7 * 24 * time.Hour
+	// maxOpenGamesPerPage caps the board
+	maxOpenGamesPerPage = 50
+)
+
+type OpenGameService struct {
+	openGameRepo *repository.OpenGameRepository
+	userRepo     *repository.UserRepository
+	prefsRepo    *repository.PreferencesRepository
+	venueRepo    *repository.VenueRepository
+	ratingRepo   *repository.RatingRepository
+	sportService *SportService
+}
+
+func NewOpenGameService() *OpenGameService {
+	return &OpenGameService{
+		openGameRepo: repository.NewOpenGameRepository(),
+		userRepo:     repository.NewUserRepository(),
+		prefsRepo:    repository.NewPreferencesRepository(),
+		venueRepo:    repository.NewVenueRepository(),
+		ratingRepo:   repository.NewRatingRepository(),
+		sportService: NewSportService(),
+	}
+}
+
+// CreateGame posts a game on the board
+func (s *OpenGameService) CreateGame(hostID int64, req models.CreateOpenGameRequest) (*models.OpenGame, error) {
+	host, err := s.userRepo.GetByID(hostID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if host == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	sport, err := s.sportService.GetSport(req.Sport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sport: %w", err)
+	}
+	if sport == nil || !sport.Active {
+		return nil, fmt.Errorf("unknown sport %s", req.Sport)
+	}
+	if !req.StartsAt.After(time.Now()) {
+		return nil, fmt.Errorf("start time must be in the future")
+	}
+	for _, level := range req.SkillLevels {
+		if !sport.HasSkillLevel(level) {
+			return nil, fmt.Errorf("skill level %s is not available for %s", level, sport.Slug)
+		}
+	}
+
+	game := &models.OpenGame{
+		HostID:          hostID,
+		Sport:           sport.Slug,
+		StartsAt:        req.StartsAt.UTC(),
+		DurationMinutes: req.DurationMinutes,
+		Venue:           req.Venue,
+		SkillLevels:     req.SkillLevels,
+		Spots:           req.Spots,
+		Note:            req.Note,
+		Status:          models.OpenGameStatusOpen,
+	}
+	if game.SkillLevels == nil {
+		game.SkillLevels = models.StringList{}
+	}
+
+	switch {
+	case req.VenueID != nil:
+		venue, err := s.venueRepo.GetByID(*req.VenueID)
+		if err != nil {
+			return nil, err
+		}
+		if venue == nil {
+			return nil, fmt.Errorf("venue not found")
+		}
+		if !venue.HasSport(sport.Slug) {
+			return nil, fmt.Errorf("%s does not host %s", venue.Name, sport.Name)
+		}
+		label := venue.Label()
+		game.VenueID, game.Venue = &venue.ID, &label
+		game.Latitude, game.Longitude = venue.Latitude, venue.Longitude
+	case req.Latitude != nil && req.Longitude != nil:
+		game.Latitude, game.Longitude = *req.Latitude, *req.Longitude
+	case host.Latitude != nil && host.Longitude != nil:
+		game.Latitude, game.Longitude = *host.Latitude, *host.Longitude
+	default:
+		return nil, fmt.Errorf("a venue or coordinates are required when your location is not set")
+	}
+
+	if err := s.openGameRepo.Create(game); err != nil {
+		return nil, err
+	}
+	return s.GetGame(hostID, game.ID)
+}
+
+// GetFeed lists the open games the user can join, soonest first. The query's filter is
+// merged with the user's discovery preferences, as for the swipe deck.
+func (s *OpenGameService) GetFeed(userID int64, query models.OpenGameFeedQuery) ([]models.OpenGame, error) {
+	viewer, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if viewer == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	prefs, err := s.prefsRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	from, to := time.Now(), time.Now().Add(openGameFeedHorizon)
+	if query.From != nil && query.From.After(from) {
+		from = *query.From
+	}
+	if query.To != nil {
+		to = *query.To
+	}
+	limit := query.Limit
+	if limit <= 0 || limit > maxOpenGamesPerPage {
+		limit = maxOpenGamesPerPage
+	}
+
+	games, err := s.openGameRepo.GetFeed(viewer, query.ProfileFilter.WithDefaults(prefs), from, to, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachViewer(userID, games); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+// GetMyGames lists the upcoming and ongoing games the user hosts or has asked to join
+func (s *OpenGameService) GetMyGames(userID int64) ([]models.OpenGame, error) {
+	games, err := s.openGameRepo.GetForUser(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachViewer(userID, games); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+// GetGame returns a game with its players. The host also sees every other request; anyone
+// else sees their own. It returns nil if the game does not exist.
+func (s *OpenGameService) GetGame(userID, gameID int64) (*models.OpenGame, error) {
+	game, err := s.openGameRepo.GetByID(gameID)
+	if err != nil || game == nil {
+		return nil, err
+	}
+
+	requests, err := s.openGameRepo.GetRequests(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := []int64{game.HostID}
+	for _, r := range requests {
+		userIDs = append(userIDs, r.UserID)
+	}
+	profiles, err := s.userRepo.GetProfilesByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	game.Host = profiles[game.HostID]
+	game.Players = []models.JoinRequest{}
+	if game.HostID == userID {
+		game.Requests = []models.JoinRequest{}
+	}
+	for _, r := range requests {
+		r.User = profiles[r.UserID]
+		switch {
+		case r.Status == models.JoinRequestApproved:
+			game.Players = append(game.Players, r)
+		case game.HostID == userID && r.Status != models.JoinRequestWithdrawn:
+			game.Requests = append(game.Requests, r)
+		}
+		if r.UserID == userID {
+			mine := r
+			game.MyRequest = &mine
+		}
+	}
+	return game, nil
+}
+
+// RequestToJoin asks the host for a spot. Only games the user would see on their board can be
+// joined. It returns nil if the game does not exist.
+func (s *OpenGameService) RequestToJoin(userID, gameID int64, req models.JoinOpenGameRequest) (*models.JoinRequest, error) {
+	game, err := s.openGameRepo.GetByID(gameID)
+	if err != nil || game == nil {
+		return nil, err
+	}
+	if game.HostID == userID {
+		return nil, fmt.Errorf("you are hosting this game")
+	}
+	if game.Status != models.OpenGameStatusOpen || !game.StartsAt.After(time.Now()) {
+		return nil, fmt.Errorf("game is no longer open")
+	}
+
+	existing, err := s.openGameRepo.GetRequest(gameID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		switch existing.Status {
+		case models.JoinRequestRejected:
+			return nil, fmt.Errorf("the host declined your request")
+		case models.JoinRequestWithdrawn:
+		default:
+			return nil, fmt.Errorf("you have already asked to join")
+		}
+	}
+
+	viewer, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if viewer == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	// Only the host's and the viewer's own rules apply here, not the viewer's saved filter
+	visible, err := s.openGameRepo.GetFeed(viewer, models.ProfileFilter{}, time.Now(), game.StartsAt.Add(time.Second), 1, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if len(visible) == 0 {
+		return nil, fmt.Errorf("this game is not open to you")
+	}
+
+	created, err := s.openGameRepo.CreateRequest(&models.JoinRequest{GameID: gameID, UserID: userID, Message: req.Message})
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, fmt.Errorf("you have already asked to join")
+	}
+
+	request, err := s.openGameRepo.GetRequest(gameID, userID)
+	if err != nil || request == nil {
+		return nil, err
+	}
+	request.User = profileOf(viewer)
+	s.notify(game.HostID, models.WSMessageTypeJoinRequest, gameID, request)
+	return request, nil
+}
+
+// ApproveRequest gives the requester a spot, or a place on the waitlist when the game is
+// full. It returns nil if the request does not exist or is not for one of the host's games.
+func (s *OpenGameService) ApproveRequest(hostID, requestID int64) (*models.JoinRequest, error) {
+	request, game, err := s.requestForHost(hostID, requestID)
+	if err != nil || request == nil {
+		return nil, err
+	}
+	if game.Status != models.OpenGameStatusOpen {
+		return nil, fmt.Errorf("game is no longer open")
+	}
+
+	status, err := s.openGameRepo.Approve(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		return nil, fmt.Errorf("request cannot be approved")
+	}
+
+	return s.afterRequestChange(game.ID, requestID, nil)
+}
+
+// RejectRequest turns down a request, or removes an approved player. A freed spot goes to the
+// head of the waitlist. It returns nil if the request is not for one of the host's games.
+func (s *OpenGameService) RejectRequest(hostID, requestID int64) (*models.JoinRequest, error) {
+	request, game, err := s.requestForHost(hostID, requestID)
+	if err != nil || request == nil {
+		return nil, err
+	}
+
+	rejected, promoted, err := s.openGameRepo.Release(requestID, models.JoinRequestRejected,
+		models.JoinRequestPending, models.JoinRequestWaitlisted, models.JoinRequestApproved)
+	if err != nil {
+		return nil, err
+	}
+	if !rejected {
+		return nil, fmt.Errorf("request already closed")
+	}
+
+	return s.afterRequestChange(game.ID, requestID, promoted)
+}
+
+// LeaveGame withdraws the user's request or gives up their spot, which goes to the head of
+// the waitlist. It returns nil if the user has no open request for the game.
+func (s *OpenGameService) LeaveGame(userID, gameID int64) (*models.JoinRequest, error) {
+	request, err := s.openGameRepo.GetRequest(gameID, userID)
+	if err != nil || request == nil {
+		return nil, err
+	}
+
+	left, promoted, err := s.openGameRepo.Release(request.ID, models.JoinRequestWithdrawn,
+		models.JoinRequestPending, models.JoinRequestWaitlisted, models.JoinRequestApproved)
+	if err != nil || !left {
+		return nil, err
+	}
+
+	updated, err := s.afterRequestChange(gameID, request.ID, promoted)
+	if err != nil || updated == nil {
+		return nil, err
+	}
+	if game, err := s.openGameRepo.GetByID(gameID); err != nil {
+		fmt.Printf("Failed to load open game: %v\n", err)
+	} else if game != nil {
+		s.notify(game.HostID, models.WSMessageTypeJoinRequestUpdated, gameID, updated)
+	}
+	return updated, nil
+}
+
+// CancelGame takes the game off the board and tells everyone who asked to join. It returns
+// nil if the game does not exist or is not the user's.
+func (s *OpenGameService) CancelGame(hostID, gameID int64) (*models.OpenGame, error) {
+	game, err := s.openGameRepo.GetByID(gameID)
+	if err != nil || game == nil || game.HostID != hostID {
+		return nil, err
+	}
+
+	cancelled, err := s.openGameRepo.Cancel(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, fmt.Errorf("game already cancelled")
+	}
+
+	game, err = s.GetGame(hostID, gameID)
+	if err != nil || game == nil {
+		return nil, err
+	}
+	for _, r := range append(append([]models.JoinRequest{}, game.Players...), game.Requests...) {
+		if r.Status != models.JoinRequestRejected {
+			s.notify(r.UserID, models.WSMessageTypeOpenGameCancelled, gameID, nil)
+		}
+	}
+	return game, nil
+}
+
+// requestForHost returns a request and its game, or nil if the game is not the host's
+func (s *OpenGameService) requestForHost(hostID, requestID int64) (*models.JoinRequest, *models.OpenGame, error) {
+	request, err := s.openGameRepo.GetRequestByID(requestID)
+	if err != nil || request == nil {
+		return nil, nil, err
+	}
+	game, err := s.openGameRepo.GetByID(request.GameID)
+	if err != nil || game == nil || game.HostID != hostID {
+		return nil, nil, err
+	}
+	return request, game, nil
+}
+
+// afterRequestChange reloads a changed request and tells its owner, and the owners of any
+// requests promoted from the waitlist, about it
+func (s *OpenGameService) afterRequestChange(gameID, requestID int64, promoted []int64) (*models.JoinRequest, error) {
+	request, err := s.openGameRepo.GetRequestByID(requestID)
+	if err != nil || request == nil {
+		return nil, err
+	}
+	if profile, err := s.userRepo.GetProfileByID(request.UserID); err != nil {
+		fmt.Printf("Failed to load profile: %v\n", err)
+	} else {
+		request.User = profile
+	}
+
+	s.notify(request.UserID, models.WSMessageTypeJoinRequestUpdated, gameID, request)
+	for _, id := range promoted {
+		p, err := s.openGameRepo.GetRequestByID(id)
+		if err != nil || p == nil {
+			fmt.Printf("Failed to load promoted request: %v\n", err)
+			continue
+		}
+		s.notify(p.UserID, models.WSMessageTypeJoinRequestUpdated, gameID, p)
+	}
+	return request, nil
+}
+
+// attachViewer sets the host profiles and the viewer's own requests on a list of games
+func (s *OpenGameService) attachViewer(userID int64, games []models.OpenGame) error {
+	hostIDs := make([]int64, len(games))
+	gameIDs := make([]int64, len(games))
+	for i, g := range games {
+		hostIDs[i], gameIDs[i] = g.HostID, g.ID
+	}
+
+	hosts, err := s.userRepo.GetProfilesByIDs(hostIDs)
+	if err != nil {
+		return err
+	}
+	requests, err := s.openGameRepo.GetUserRequests(userID, gameIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range games {
+		games[i].Host = hosts[games[i].HostID]
+		if r, ok := requests[games[i].ID]; ok {
+			games[i].MyRequest = &r
+		}
+	}
+	return nil
+}
+
+func (s *OpenGameService) notify(userID int64, kind models.WSMessageType, gameID int64, request *models.JoinRequest) {
+	game, err := s.openGameRepo.GetByID(gameID)
+	if err != nil {
+		fmt.Printf("Failed to load open game: %v\n", err)
+		return
+	}
+	notifyUser(userID, models.WSMessage{
+		Type:    kind,
+		Payload: models.WSOpenGameMessage{Game: game, Request: request},
+	})
+}
+
+func profileOf(user *models.User) *models.UserProfile {
+	profile := user.Profile()
+	return &profile
+}
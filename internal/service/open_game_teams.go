@@ -0,0 +1,252 @@
+package service
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/rating"
+	"swipe-sports-backend/internal/teams"
+)
+
+// defaultRank is the overall rank new users start on. Players without a rating in the game's
+// sport are placed on the rating scale by their distance from it.
+const defaultRank = 1000
+
+// teamRatingFormats is the order in which a player's ratings in the game's sport are preferred
+var teamRatingFormats = []models.RatingFormat{models.RatingFormatTeam, models.RatingFormatDoubles, models.RatingFormatSingles}
+
+// GetTeams returns the game's teams as picked, rated as the players stand now. It returns nil
+// if the user is neither the host nor an approved player, or if no teams were picked yet.
+func (s *OpenGameService) GetTeams(userID, gameID int64) (*models.TeamSplit, error) {
+	game, roster, err := s.roster(gameID)
+	if err != nil || game == nil || !inRoster(roster, userID) {
+		return nil, err
+	}
+
+	split, err := s.openGameRepo.GetTeams(gameID)
+	if err != nil || split == nil {
+		return nil, err
+	}
+	return s.describeTeams(game, roster, split)
+}
+
+// GenerateTeams splits the roster into balanced teams, replacing the current split. Calling it
+// again offers a different split when one as fair exists. It returns nil if the game is not the
+// host's.
+func (s *OpenGameService) GenerateTeams(hostID, gameID int64, req models.GenerateTeamsRequest) (*models.TeamSplit, error) {
+	game, roster, err := s.roster(gameID)
+	if err != nil || game == nil || game.HostID != hostID {
+		return nil, err
+	}
+	if game.Status != models.OpenGameStatusOpen {
+		return nil, fmt.Errorf("game has been cancelled")
+	}
+
+	players, err := s.teamPlayers(game, roster, req.BalanceGender)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := teams.Options{
+		Teams:         req.Teams,
+		Together:      req.KeepTogether,
+		BalanceGroups: req.BalanceGender,
+		Rand:          rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	current, err := s.openGameRepo.GetTeams(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		opts.Avoid = current.Assignment
+	}
+
+	generated, err := teams.Generate(players, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	split := &models.TeamSplit{GameID: gameID, Assignment: make(models.TeamAssignment, len(generated.Teams))}
+	for i, team := range generated.Teams {
+		split.Assignment[i] = team.Players
+	}
+	return s.saveTeams(game, roster, split)
+}
+
+// AdjustTeams replaces the split with one set by the host. Every player on the roster must be
+// on exactly one team. It returns nil if the game is not the host's.
+func (s *OpenGameService) AdjustTeams(hostID, gameID int64, req models.AdjustTeamsRequest) (*models.TeamSplit, error) {
+	game, roster, err := s.roster(gameID)
+	if err != nil || game == nil || game.HostID != hostID {
+		return nil, err
+	}
+	if game.Status != models.OpenGameStatusOpen {
+		return nil, fmt.Errorf("game has been cancelled")
+	}
+
+	placed := make(map[int64]bool, len(roster))
+	for _, team := range req.Teams {
+		if len(team) == 0 {
+			return nil, fmt.Errorf("every team needs at least one player")
+		}
+		for _, id := range team {
+			if !inRoster(roster, id) {
+				return nil, fmt.Errorf("player %d is not on the roster", id)
+			}
+			if placed[id] {
+				return nil, fmt.Errorf("player %d is on more than one team", id)
+			}
+			placed[id] = true
+		}
+	}
+	if len(placed) != len(roster) {
+		return nil, fmt.Errorf("every player on the roster must be on a team")
+	}
+
+	return s.saveTeams(game, roster, &models.TeamSplit{GameID: gameID, Assignment: req.Teams, Manual: true})
+}
+
+// roster returns the game and its confirmed players: the host, then the approved players
+func (s *OpenGameService) roster(gameID int64) (*models.OpenGame, []int64, error) {
+	game, err := s.openGameRepo.GetByID(gameID)
+	if err != nil || game == nil {
+		return nil, nil, err
+	}
+	requests, err := s.openGameRepo.GetRequests(gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roster := []int64{game.HostID}
+	for _, r := range requests {
+		if r.Status == models.JoinRequestApproved {
+			roster = append(roster, r.UserID)
+		}
+	}
+	return game, roster, nil
+}
+
+func inRoster(roster []int64, userID int64) bool {
+	for _, id := range roster {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// teamPlayers rates the roster for the game's sport: by the player's rating in the sport when
+// they have one, otherwise by their overall rank with the deviation of a new player
+func (s *OpenGameService) teamPlayers(game *models.OpenGame, roster []int64, withGender bool) ([]teams.Player, error) {
+	ratings, err := s.ratingRepo.GetForUsers(roster)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := s.userRepo.GetProfilesByIDs(roster)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]teams.Player, 0, len(roster))
+	for _, id := range roster {
+		player := teams.Player{ID: id, Rating: rating.Default()}
+		if profile := profiles[id]; profile != nil {
+			player.Rating.Rating = rating.DefaultRating + float64(profile.Rank-defaultRank)
+			if withGender && profile.Gender != nil {
+				player.Group = string(*profile.Gender)
+			}
+		}
+		if r := sportRating(ratings[id], game.Sport, teamRatingFormats); r != nil {
+			player.Rating = rating.Rating{Rating: r.Rating, Deviation: r.Deviation, Volatility: r.Volatility}
+		}
+		players = append(players, player)
+	}
+	return players, nil
+}
+
+// sportRating picks the user's rating in the sport in the first of the formats they have one
+// in, or nil if there is none
+func sportRating(ratings []models.SportRating, sport string, formats []models.RatingFormat) *models.SportRating {
+	for _, format := range formats {
+		for i := range ratings {
+			if ratings[i].Sport == sport && ratings[i].Format == format {
+				return &ratings[i]
+			}
+		}
+	}
+	return nil
+}
+
+// saveTeams stores the split and shows it to the roster
+func (s *OpenGameService) saveTeams(game *models.OpenGame, roster []int64, split *models.TeamSplit) (*models.TeamSplit, error) {
+	if err := s.openGameRepo.SaveTeams(split); err != nil {
+		return nil, err
+	}
+	split, err := s.openGameRepo.GetTeams(game.ID)
+	if err != nil || split == nil {
+		return nil, err
+	}
+	split, err = s.describeTeams(game, roster, split)
+	if err != nil {
+		return nil, err
+	}
+
+	event := models.WSMessage{Type: models.WSMessageTypeOpenGameTeams, Payload: split}
+	for _, id := range roster {
+		notifyUser(id, event)
+	}
+	return split, nil
+}
+
+// describeTeams fills in the players, ratings and win probabilities of a stored split. Players
+// who have left since are dropped and players approved since are listed as unassigned.
+func (s *OpenGameService) describeTeams(game *models.OpenGame, roster []int64, split *models.TeamSplit) (*models.TeamSplit, error) {
+	players, err := s.teamPlayers(game, roster, false)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := s.userRepo.GetProfilesByIDs(roster)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make([][]int64, len(split.Assignment))
+	placed := make(map[int64]bool)
+	for i, team := range split.Assignment {
+		current[i] = []int64{}
+		for _, id := range team {
+			if inRoster(roster, id) {
+				current[i] = append(current[i], id)
+				placed[id] = true
+			}
+		}
+	}
+
+	evaluated := teams.Evaluate(players, current)
+	split.Spread = evaluated.Spread
+	split.Teams = make([]models.GameTeam, len(evaluated.Teams))
+	for i, team := range evaluated.Teams {
+		gameTeam := models.GameTeam{
+			Number:         i + 1,
+			Players:        []models.UserProfile{},
+			Rating:         team.Rating.Rating,
+			WinProbability: team.WinProbability,
+		}
+		for _, id := range team.Players {
+			if profile := profiles[id]; profile != nil {
+				gameTeam.Players = append(gameTeam.Players, *profile)
+			}
+		}
+		split.Teams[i] = gameTeam
+	}
+
+	split.Unassigned = []models.UserProfile{}
+	for _, id := range roster {
+		if profile := profiles[id]; !placed[id] && profile != nil {
+			split.Unassigned = append(split.Unassigned, *profile)
+		}
+	}
+	return split, nil
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"fmt"
+
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/rating"
+	"swipe-sports-backend/internal/redis"
+	"swipe-sports-backend/internal/repository"
+)
+
+// partnerRatingFormats is the order in which a player's ratings are preferred when seeding a
+// partnership's rating
+var partnerRatingFormats = []models.RatingFormat{models.RatingFormatDoubles, models.RatingFormatSingles, models.RatingFormatTeam}
+
+type PartnershipService struct {
+	partnershipRepo *repository.PartnershipRepository
+	swipeRepo       *repository.SwipeRepository
+	userRepo        *repository.UserRepository
+	ratingRepo      *repository.RatingRepository
+	sportService    *SportService
+}
+
+func NewPartnershipService() *PartnershipService {
+	return &PartnershipService{
+		partnershipRepo: repository.NewPartnershipRepository(),
+		swipeRepo:       repository.NewSwipeRepository(),
+		userRepo:        repository.NewUserRepository(),
+		ratingRepo:      repository.NewRatingRepository(),
+		sportService:    NewSportService(),
+	}
+}
+
+// SetPartnerSports replaces the sports the user is looking for a doubles partner in. They must
+// be sports the user plays and that are played in pairs.
+func (s *PartnershipService) SetPartnerSports(userID int64, req models.UpdatePartnerSportsRequest) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	sports := models.StringList{}
+	for _, slug := range req.Sports {
+		if contains(sports, slug) {
+			continue
+		}
+		if err := s.checkPartnerSport(slug, user); err != nil {
+			return nil, err
+		}
+		sports = append(sports, slug)
+	}
+
+	if err := s.userRepo.SetPartnerSports(userID, sports); err != nil {
+		return nil, err
+	}
+	user.PartnerSports = sports
+
+	if err := redis.DeleteUserProfile(userID); err != nil {
+		fmt.Printf("Failed to invalidate user profile cache: %v\n", err)
+	}
+	invalidateSwipeDeck(userID)
+
+	return user, nil
+}
+
+// Propose asks a match to become the user's doubles partner in a sport. It returns nil if the
+// match is not the user's.
+func (s *PartnershipService) Propose(userID, matchID int64, req models.ProposePartnershipRequest) (*models.Partnership, error) {
+	match, err := s.swipeRepo.GetMatchByID(matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match: %w", err)
+	}
+	if match == nil || match.Status != models.MatchStatusActive || match.Partner(userID) == 0 {
+		return nil, nil
+	}
+	partnerID := match.Partner(userID)
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	partner, err := s.userRepo.GetByID(partnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || partner == nil {
+		return nil, nil
+	}
+	if err := s.checkPartnerSport(req.Sport, user); err != nil {
+		return nil, err
+	}
+	if !partner.SportPreferences[req.Sport] {
+		return nil, fmt.Errorf("%s does not play %s", partner.Name, req.Sport)
+	}
+
+	seed, err := s.seedRating(req.Sport, user, partner)
+	if err != nil {
+		return nil, err
+	}
+	partnership := &models.Partnership{
+		Sport:      req.Sport,
+		User1ID:    userID,
+		User2ID:    partnerID,
+		ProposedBy: userID,
+		Rating:     seed.Rating,
+		Deviation:  seed.Deviation,
+		Volatility: seed.Volatility,
+	}
+	proposed, err := s.partnershipRepo.Propose(partnership)
+	if err != nil {
+		return nil, err
+	}
+	if !proposed {
+		return nil, fmt.Errorf("you are already partners in %s or have a proposal open", req.Sport)
+	}
+
+	return s.afterChange(userID, partnership.ID, models.WSMessageTypePartnershipProposed)
+}
+
+// GetPartnerships returns the user's partnerships and open proposals, each with the partner's
+// profile
+func (s *PartnershipService) GetPartnerships(userID int64) ([]models.Partnership, error) {
+	partnerships, err := s.partnershipRepo.GetForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(partnerships))
+	for i, p := range partnerships {
+		ids[i] = p.Other(userID)
+	}
+	profiles, err := s.userRepo.GetProfilesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range partnerships {
+		partnerships[i].Partner = profiles[partnerships[i].Other(userID)]
+	}
+	return partnerships, nil
+}
+
+// GetPartnership returns a partnership with the partner's profile, or nil if the user is not in it
+func (s *PartnershipService) GetPartnership(userID, partnershipID int64) (*models.Partnership, error) {
+	partnership, err := s.partnershipRepo.GetByID(partnershipID)
+	if err != nil || partnership == nil || partnership.Other(userID) == 0 {
+		return nil, err
+	}
+
+	partner, err := s.userRepo.GetByID(partnership.Other(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if partner != nil {
+		profile := partner.Profile()
+		partnership.Partner = &profile
+	}
+	return partnership, nil
+}
+
+// Accept forms the partnership proposed to the user. It returns nil if the user is not in it.
+func (s *PartnershipService) Accept(userID, partnershipID int64) (*models.Partnership, error) {
+	return s.respond(userID, partnershipID, models.PartnershipStatusActive)
+}
+
+// Decline turns down the partnership proposed to the user. It returns nil if the user is not in it.
+func (s *PartnershipService) Decline(userID, partnershipID int64) (*models.Partnership, error) {
+	return s.respond(userID, partnershipID, models.PartnershipStatusDeclined)
+}
+
+// Dissolve breaks up a partnership, or withdraws the user's own proposal. The rating is kept in
+// case the pair teams up again. It returns nil if the user is not in the partnership.
+func (s *PartnershipService) Dissolve(userID, partnershipID int64) (*models.Partnership, error) {
+	partnership, err := s.GetPartnership(userID, partnershipID)
+	if err != nil || partnership == nil {
+		return nil, err
+	}
+
+	dissolved, err := s.partnershipRepo.Dissolve(partnershipID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !dissolved {
+		return nil, fmt.Errorf("partnership is not active")
+	}
+
+	return s.afterChange(userID, partnershipID, models.WSMessageTypePartnershipUpdated)
+}
+
+func (s *PartnershipService) respond(userID, partnershipID int64, status models.PartnershipStatus) (*models.Partnership, error) {
+	partnership, err := s.GetPartnership(userID, partnershipID)
+	if err != nil || partnership == nil {
+		return nil, err
+	}
+
+	answered, err := s.partnershipRepo.Respond(partnershipID, userID, status)
+	if err != nil {
+		return nil, err
+	}
+	if !answered {
+		return nil, fmt.Errorf("partnership was already answered")
+	}
+
+	return s.afterChange(userID, partnershipID, models.WSMessageTypePartnershipUpdated)
+}
+
+// checkPartnerSport makes sure the user plays the sport and that it is played in pairs
+func (s *PartnershipService) checkPartnerSport(slug string, user *models.User) error {
+	sport, err := s.sportService.GetSport(slug)
+	if err != nil {
+		return fmt.Errorf("failed to get sport: %w", err)
+	}
+	if sport == nil || !sport.Active {
+		return fmt.Errorf("unknown sport %s", slug)
+	}
+	if sport.TeamSize == models.TeamSizeTeam {
+		return fmt.Errorf("%s is not played in pairs", sport.Name)
+	}
+	if !user.SportPreferences[slug] {
+		return fmt.Errorf("add %s to your sports first", sport.Name)
+	}
+	return nil
+}
+
+// seedRating combines the partners' own ratings in the sport into the pair's starting rating.
+// Players without a rating in the sport are placed on the scale by their overall rank.
+func (s *PartnershipService) seedRating(sport string, users ...*models.User) (rating.Rating, error) {
+	ids := make([]int64, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	ratings, err := s.ratingRepo.GetForUsers(ids)
+	if err != nil {
+		return rating.Rating{}, err
+	}
+
+	members := make([]rating.Rating, len(users))
+	for i, u := range users {
+		members[i] = rating.Default()
+		members[i].Rating = rating.DefaultRating + float64(u.Rank-defaultRank)
+		if r := sportRating(ratings[u.ID], sport, partnerRatingFormats); r != nil {
+			members[i] = rating.Rating{Rating: r.Rating, Deviation: r.Deviation, Volatility: r.Volatility}
+		}
+	}
+	return rating.Team(members), nil
+}
+
+// afterChange reloads the partnership for the user who acted and tells the partner about it
+func (s *PartnershipService) afterChange(userID, partnershipID int64, eventType models.WSMessageType) (*models.Partnership, error) {
+	partnership, err := s.GetPartnership(userID, partnershipID)
+	if err != nil || partnership == nil {
+		return nil, err
+	}
+
+	// The partner sees the pair from their own side
+	event := *partnership
+	if user, err := s.userRepo.GetByID(userID); err != nil {
+		fmt.Printf("Failed to get user: %v\n", err)
+	} else if user != nil {
+		profile := user.Profile()
+		event.Partner = &profile
+	}
+	notifyUser(partnership.Other(userID), models.WSMessage{Type: eventType, Payload: event})
+
+	return partnership, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"fmt"
+
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/repository"
+)
+
+type PreferencesService struct {
+	prefsRepo    *repository.PreferencesRepository
+	sportService *SportService
+}
+
+func NewPreferencesService() *PreferencesService {
+	return &PreferencesService{
+		prefsRepo:    repository.NewPreferencesRepository(),
+		sportService: NewSportService(),
+	}
+}
+
+// GetPreferences returns the stored discovery preferences, or empty (unrestricted) ones
+func (s *PreferencesService) GetPreferences(userID int64) (*models.DiscoveryPreferences, error) {
+	prefs, err := s.prefsRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	if prefs == nil {
+		prefs = &models.DiscoveryPreferences{
+			UserID:      userID,
+			Genders:     models.StringList{},
+			Sports:      models.StringList{},
+			SkillLevels: models.StringList{},
+			PlayStyles:  models.StringList{},
+		}
+	}
+
+	return prefs, nil
+}
+
+func (s *PreferencesService) UpdatePreferences(userID int64, req models.UpdateDiscoveryPreferencesRequest) (*models.DiscoveryPreferences, error) {
+	if req.MinAge != nil && req.MaxAge != nil && *req.MinAge > *req.MaxAge {
+		return nil, fmt.Errorf("min_age cannot be greater than max_age")
+	}
+	if req.MinRating != nil && req.MaxRating != nil && *req.MinRating > *req.MaxRating {
+		return nil, fmt.Errorf("min_rating cannot be greater than max_rating")
+	}
+
+	if err := s.sportService.ValidateSports(req.Sports); err != nil {
+		return nil, err
+	}
+	for _, level := range req.SkillLevels {
+		if !contains(validSkillLevels, level) {
+			return nil, fmt.Errorf("invalid skill level: %s", level)
+		}
+	}
+	for _, style := range req.PlayStyles {
+		if !contains(validPlayStyles, style) {
+			return nil, fmt.Errorf("invalid play style: %s", style)
+		}
+	}
+
+	prefs := &models.DiscoveryPreferences{
+		UserID:        userID,
+		MinAge:        req.MinAge,
+		MaxAge:        req.MaxAge,
+		MaxDistanceKm: req.MaxDistanceKm,
+		Genders:       req.Genders,
+		Sports:        req.Sports,
+		SkillLevels:   req.SkillLevels,
+		MinRating:     req.MinRating,
+		MaxRating:     req.MaxRating,
+		PlayStyles:    req.PlayStyles,
+	}
+
+	if err := s.prefsRepo.Upsert(prefs); err != nil {
+		return nil, fmt.Errorf("failed to update preferences: %w", err)
+	}
+	invalidateSwipeDeck(userID)
+
+	return s.GetPreferences(userID)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/redis"
+	"swipe-sports-backend/internal/repository"
+	"swipe-sports-backend/internal/schedule"
+)
+
+const maxProposalsPerPage = 50
+
+type ProposalService struct {
+	proposalRepo *repository.ProposalRepository
+	swipeRepo    *repository.SwipeRepository
+	userRepo     *repository.UserRepository
+	venueRepo    *repository.VenueRepository
+	sportService *SportService
+}
+
+func NewProposalService() *ProposalService {
+	return &ProposalService{
+		proposalRepo: repository.NewProposalRepository(),
+		swipeRepo:    repository.NewSwipeRepository(),
+		userRepo:     repository.NewUserRepository(),
+		venueRepo:    repository.NewVenueRepository(),
+		sportService: NewSportService(),
+	}
+}
+
+// CreateProposal offers the match partner a game at a given time. Conflicts with either
+// player's availability or scheduled games are flagged but do not block the proposal.
+func (s *ProposalService) CreateProposal(userID, matchID int64, req models.CreateProposalRequest) (*models.GameProposal, error) {
+	match, err := s.activeMatch(userID, matchID)
+	if err != nil || match == nil {
+		return nil, err
+	}
+
+	proposal, err := s.newProposal(userID, match.ID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	message := s.proposalMessage(userID, match.ID, "Proposed", proposal)
+	if err := s.proposalRepo.Create(proposal, message); err != nil {
+		return nil, err
+	}
+
+	return s.afterAction(match, proposal.ID, models.WSMessageTypeProposal, message)
+}
+
+// GetProposals returns a match's proposals, newest first
+func (s *ProposalService) GetProposals(userID, matchID int64) ([]models.GameProposal, error) {
+	inMatch, err := s.swipeRepo.IsUserInMatch(userID, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check match: %w", err)
+	}
+	if !inMatch {
+		return nil, nil
+	}
+
+	proposals, err := s.proposalRepo.GetForMatch(matchID, maxProposalsPerPage)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := s.swipeRepo.GetMatchByID(matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match: %w", err)
+	}
+	for i := range proposals {
+		if proposals[i].Status == models.ProposalStatusPending || proposals[i].Status == models.ProposalStatusAccepted {
+			s.attachConflicts(&proposals[i], match)
+		}
+	}
+	return proposals, nil
+}
+
+// AcceptProposal schedules the game
+func (s *ProposalService) AcceptProposal(userID, proposalID int64) (*models.GameProposal, error) {
+	return s.respond(userID, proposalID, models.ProposalStatusAccepted)
+}
+
+// DeclineProposal turns the proposal down without offering another time
+func (s *ProposalService) DeclineProposal(userID, proposalID int64) (*models.GameProposal, error) {
+	return s.respond(userID, proposalID, models.ProposalStatusDeclined)
+}
+
+// CounterProposal declines the proposal in favour of a new one, which the original proposer
+// can then accept, decline or counter in turn
+func (s *ProposalService) CounterProposal(userID, proposalID int64, req models.CreateProposalRequest) (*models.GameProposal, error) {
+	original, match, err := s.pendingForResponder(userID, proposalID)
+	if err != nil || original == nil {
+		return nil, err
+	}
+
+	counter, err := s.newProposal(userID, match.ID, req)
+	if err != nil {
+		return nil, err
+	}
+	counter.CounterOf = &original.ID
+
+	message := s.proposalMessage(userID, match.ID, "Suggested instead", counter)
+	countered, err := s.proposalRepo.Counter(original.ID, counter, message)
+	if err != nil {
+		return nil, err
+	}
+	if !countered {
+		return nil, fmt.Errorf("proposal already answered")
+	}
+
+	return s.afterAction(match, counter.ID, models.WSMessageTypeProposalCountered, message)
+}
+
+// SuggestVenues lists places to propose for a match: the venues both players saved first, then
+// the rest of either player's favourites. With a sport, venues that do not host it are left out.
+func (s *ProposalService) SuggestVenues(userID, matchID int64, sport string) ([]models.Venue, error) {
+	match, err := s.activeMatch(userID, matchID)
+	if err != nil || match == nil {
+		return nil, err
+	}
+
+	partnerID := match.Partner(userID)
+	favourites, err := s.venueRepo.GetFavourites([]int64{userID, partnerID})
+	if err != nil {
+		return nil, err
+	}
+
+	mine := make(map[int64]bool)
+	for _, v := range favourites[userID] {
+		mine[v.ID] = true
+	}
+	theirs := make(map[int64]bool)
+	for _, v := range favourites[partnerID] {
+		theirs[v.ID] = true
+	}
+
+	var shared, others []models.Venue
+	seen := make(map[int64]bool)
+	for _, v := range append(favourites[userID], favourites[partnerID]...) {
+		if seen[v.ID] || (sport != "" && !v.HasSport(sport)) {
+			continue
+		}
+		seen[v.ID] = true
+		v.Favourite = mine[v.ID]
+		if mine[v.ID] && theirs[v.ID] {
+			shared = append(shared, v)
+		} else {
+			others = append(others, v)
+		}
+	}
+	return append(append([]models.Venue{}, shared...), others...), nil
+}
+
+// CancelProposal calls off a scheduled game. Either player can cancel until it starts.
+func (s *ProposalService) CancelProposal(userID, proposalID int64) (*models.GameProposal, error) {
+	proposal, err := s.proposalRepo.GetByID(proposalID)
+	if err != nil || proposal == nil {
+		return nil, err
+	}
+
+	match, err := s.activeMatch(userID, proposal.MatchID)
+	if err != nil || match == nil {
+		return nil, err
+	}
+
+	if proposal.Status != models.ProposalStatusAccepted {
+		return nil, fmt.Errorf("only scheduled games can be cancelled")
+	}
+	if !proposal.StartsAt.After(time.Now()) {
+		return nil, fmt.Errorf("game has already started")
+	}
+
+	message := s.proposalMessage(userID, match.ID, "Cancelled", proposal)
+	cancelled, err := s.proposalRepo.Cancel(proposal.ID, message)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, fmt.Errorf("only scheduled games can be cancelled")
+	}
+
+	return s.afterAction(match, proposal.ID, models.WSMessageTypeProposalCancelled, message)
+}
+
+func (s *ProposalService) respond(userID, proposalID int64, status models.ProposalStatus) (*models.GameProposal, error) {
+	proposal, match, err := s.pendingForResponder(userID, proposalID)
+	if err != nil || proposal == nil {
+		return nil, err
+	}
+
+	verb, eventType := "Declined", models.WSMessageTypeProposalDeclined
+	if status == models.ProposalStatusAccepted {
+		if !proposal.StartsAt.After(time.Now()) {
+			return nil, fmt.Errorf("proposed time has already passed")
+		}
+		verb, eventType = "Accepted", models.WSMessageTypeProposalAccepted
+	}
+
+	message := s.proposalMessage(userID, match.ID, verb, proposal)
+	responded, err := s.proposalRepo.Respond(proposal.ID, status, message)
+	if err != nil {
+		return nil, err
+	}
+	if !responded {
+		return nil, fmt.Errorf("proposal already answered")
+	}
+
+	return s.afterAction(match, proposal.ID, eventType, message)
+}
+
+// activeMatch returns the match if it is active and the user is in it, or nil otherwise
+func (s *ProposalService) activeMatch(userID, matchID int64) (*models.Match, error) {
+	match, err := s.swipeRepo.GetMatchByID(matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match: %w", err)
+	}
+	if match == nil || match.Partner(userID) == 0 {
+		return nil, nil
+	}
+	if match.Status != models.MatchStatusActive {
+		return nil, fmt.Errorf("match has ended")
+	}
+	return match, nil
+}
+
+// pendingForResponder returns a pending proposal that the user may answer, with its match. Only
+// the partner of the proposer can respond.
+func (s *ProposalService) pendingForResponder(userID, proposalID int64) (*models.GameProposal, *models.Match, error) {
+	proposal, err := s.proposalRepo.GetByID(proposalID)
+	if err != nil || proposal == nil {
+		return nil, nil, err
+	}
+
+	match, err := s.activeMatch(userID, proposal.MatchID)
+	if err != nil || match == nil {
+		return nil, nil, err
+	}
+
+	if proposal.ProposedBy == userID {
+		return nil, nil, fmt.Errorf("you cannot respond to your own proposal")
+	}
+	if proposal.Status != models.ProposalStatusPending {
+		return nil, nil, fmt.Errorf("proposal already answered")
+	}
+	return proposal, match, nil
+}
+
+func (s *ProposalService) newProposal(userID, matchID int64, req models.CreateProposalRequest) (*models.GameProposal, error) {
+	sport, err := s.sportService.GetSport(req.Sport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sport: %w", err)
+	}
+	if sport == nil || !sport.Active {
+		return nil, fmt.Errorf("unknown sport %s", req.Sport)
+	}
+	if !req.StartsAt.After(time.Now()) {
+		return nil, fmt.Errorf("proposed time must be in the future")
+	}
+
+	proposal := &models.GameProposal{
+		MatchID:         matchID,
+		ProposedBy:      userID,
+		Sport:           sport.Slug,
+		StartsAt:        req.StartsAt.UTC(),
+		DurationMinutes: req.DurationMinutes,
+		Venue:           req.Venue,
+		Note:            req.Note,
+		Status:          models.ProposalStatusPending,
+	}
+
+	if req.VenueID != nil {
+		venue, err := s.venueRepo.GetByID(*req.VenueID)
+		if err != nil {
+			return nil, err
+		}
+		if venue == nil {
+			return nil, fmt.Errorf("venue not found")
+		}
+		if !venue.HasSport(sport.Slug) {
+			return nil, fmt.Errorf("%s does not host %s", venue.Name, sport.Name)
+		}
+		if len(venue.OpeningHours.Windows) > 0 && !schedule.Covers(venue.OpeningHours, proposal.StartsAt, proposal.EndsAt()) {
+			return nil, fmt.Errorf("%s is closed at the proposed time", venue.Name)
+		}
+		label := venue.Label()
+		proposal.Venue, proposal.VenueID = &label, &venue.ID
+	}
+
+	return proposal, nil
+}
+
+// proposalMessage builds the chat message recording an action on a proposal, with the time
+// written in the acting user's time zone
+func (s *ProposalService) proposalMessage(userID, matchID int64, verb string, proposal *models.GameProposal) *models.Message {
+	loc := time.UTC
+	if user, err := s.userRepo.GetByID(userID); err != nil {
+		fmt.Printf("Failed to load user time zone: %v\n", err)
+	} else if user != nil {
+		loc = user.Availability.Location()
+	}
+
+	content := fmt.Sprintf("%s %s on %s for %d minutes", verb, proposal.Sport,
+		proposal.StartsAt.In(loc).Format("Mon 2 Jan 15:04 MST"), proposal.DurationMinutes)
+	if proposal.Venue != nil && *proposal.Venue != "" {
+		content += " at " + *proposal.Venue
+	}
+
+	return &models.Message{
+		MatchID:     matchID,
+		SenderID:    userID,
+		Content:     content,
+		MessageType: models.MessageTypeProposal,
+	}
+}
+
+// afterAction reloads the proposal with its conflicts, refreshes the chat and tells both
+// players what happened
+func (s *ProposalService) afterAction(match *models.Match, proposalID int64, eventType models.WSMessageType, message *models.Message) (*models.GameProposal, error) {
+	proposal, err := s.proposalRepo.GetByID(proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if proposal == nil {
+		return nil, fmt.Errorf("proposal not found")
+	}
+	s.attachConflicts(proposal, match)
+
+	if err := redis.DeleteMatchMessages(match.ID); err != nil {
+		fmt.Printf("Failed to invalidate match messages cache: %v\n", err)
+	}
+
+	event := models.WSMessage{
+		Type:    eventType,
+		Payload: models.WSProposalMessage{Proposal: *proposal, Message: *message},
+	}
+	notifyUser(match.User1ID, event)
+	notifyUser(match.User2ID, event)
+
+	return proposal, nil
+}
+
+// attachConflicts flags each player whose weekly availability does not cover the proposed time
+// or who already has a game scheduled then. Players without availability set are not flagged
+// for it. Failures are only logged.
+func (s *ProposalService) attachConflicts(proposal *models.GameProposal, match *models.Match) {
+	conflicts := []models.ProposalConflict{}
+	start, end := proposal.StartsAt, proposal.EndsAt()
+
+	for _, userID := range []int64{match.User1ID, match.User2ID} {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			fmt.Printf("Failed to load availability: %v\n", err)
+		} else if user != nil && len(user.Availability.Windows) > 0 && !schedule.Covers(user.Availability, start, end) {
+			conflicts = append(conflicts, models.ProposalConflict{UserID: userID, Kind: models.ConflictOutsideAvailability})
+		}
+
+		scheduled, err := s.proposalRepo.GetScheduledOverlapping(userID, start, end, proposal.ID)
+		if err != nil {
+			fmt.Printf("Failed to check scheduled games: %v\n", err)
+			continue
+		}
+		for i := range scheduled {
+			conflicts = append(conflicts, models.ProposalConflict{
+				UserID:     userID,
+				Kind:       models.ConflictScheduledGame,
+				ProposalID: &scheduled[i],
+			})
+		}
+	}
+
+	proposal.Conflicts = conflicts
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/redis"
+)
+
+var quotaKinds = []models.QuotaKind{models.QuotaRightSwipes, models.QuotaSuperLikes, models.QuotaRewinds}
+
+// localDay is the user's current date in their own time zone, so daily allowances reset at
+// their midnight
+func localDay(user *models.User) string {
+	return time.Now().In(user.Availability.Location()).Format("2006-01-02")
+}
+
+// nextLocalMidnight is when the user's daily allowances reset
+func nextLocalMidnight(user *models.User) time.Time {
+	now := time.Now().In(user.Availability.Location())
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+}
+
+// quotaLimit returns the user's daily allowance of a kind under their plan; negative means
+// unlimited. Users on an unknown plan get the free allowances.
+func quotaLimit(kind models.QuotaKind, user *models.User) int {
+	limits := userPlan(user).Quotas
+
+	switch kind {
+	case models.QuotaRightSwipes:
+		return limits.RightSwipes
+	case models.QuotaSuperLikes:
+		return limits.SuperLikes
+	case models.QuotaRewinds:
+		return limits.Rewinds
+	}
+	return 0
+}
+
+// consumeQuota uses one of the user's daily allowance and returns how many are left, or nil
+// when the allowance is unlimited or unknown. If Redis is unavailable the action is allowed
+// rather than blocking the user.
+func consumeQuota(kind models.QuotaKind, user *models.User) (*int, error) {
+	limit := quotaLimit(kind, user)
+	if limit < 0 {
+		return nil, nil
+	}
+
+	allowed, used, err := redis.ConsumeDailyQuota(string(kind), user.ID, localDay(user), limit)
+	if err != nil {
+		fmt.Printf("Failed to check %s quota: %v\n", kind, err)
+		return nil, nil
+	}
+	if !allowed {
+		return nil, fmt.Errorf("daily %s limit reached", kind)
+	}
+
+	remaining := limit - int(used)
+	return &remaining, nil
+}
+
+// refundQuota returns a use consumed for an action that then failed
+func refundQuota(kind models.QuotaKind, user *models.User) {
+	if quotaLimit(kind, user) < 0 {
+		return
+	}
+	if err := redis.RefundDailyQuota(string(kind), user.ID, localDay(user)); err != nil {
+		fmt.Printf("Failed to refund %s quota: %v\n", kind, err)
+	}
+}
+
+// quotaStatus reports every daily allowance of the user
+func quotaStatus(user *models.User) (*models.QuotaStatus, error) {
+	kinds := make([]string, len(quotaKinds))
+	for i, kind := range quotaKinds {
+		kinds[i] = string(kind)
+	}
+
+	usage, err := redis.GetDailyQuotaUsage(user.ID, localDay(user), kinds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota usage: %w", err)
+	}
+
+	status := &models.QuotaStatus{
+		Plan:     planName(user),
+		ResetsAt: nextLocalMidnight(user),
+		Quotas:   make(map[models.QuotaKind]models.Quota, len(quotaKinds)),
+	}
+	for i, kind := range quotaKinds {
+		quota := models.Quota{Used: int(usage[i])}
+		if limit := quotaLimit(kind, user); limit >= 0 {
+			remaining := limit - quota.Used
+			if remaining < 0 {
+				remaining = 0
+			}
+			quota.Limit = &limit
+			quota.Remaining = &remaining
+		}
+		status.Quotas[kind] = quota
+	}
+
+	return status, nil
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"swipe-sports-backend/internal/config"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/rating"
+	"swipe-sports-backend/internal/repository"
+)
+
+type RatingService struct {
+	ratingRepo      *repository.RatingRepository
+	gameRepo        *repository.GameRepository
+	partnershipRepo *repository.PartnershipRepository
+}
+
+func NewRatingService() *RatingService {
+	return &RatingService{
+		ratingRepo:      repository.NewRatingRepository(),
+		gameRepo:        repository.NewGameRepository(),
+		partnershipRepo: repository.NewPartnershipRepository(),
+	}
+}
+
+// GetRatings returns the user's ratings in every sport and format they have played
+func (s *RatingService) GetRatings(userID int64) ([]models.SportRating, error) {
+	ratings, err := s.ratingRepo.GetForUsers([]int64{userID})
+	if err != nil {
+		return nil, err
+	}
+	if ratings[userID] == nil {
+		return []models.SportRating{}, nil
+	}
+	return ratings[userID], nil
+}
+
+// RecomputeAll rebuilds every rating, the partnerships' included, by replaying the confirmed
+// games in the order they were played, and returns the number of games replayed. Partnerships
+// start again from their seed ratings and are rated for the doubles games confirmed while the
+// two were partners. Games confirmed while it runs are lost from the result, so run it while the
+// API is stopped.
+func (s *RatingService) RecomputeAll() (int, error) {
+	type ratingKey struct {
+		userID int64
+		sport  string
+		format models.RatingFormat
+	}
+	ratings := make(map[ratingKey]models.SportRating)
+
+	type pairKey struct {
+		sport        string
+		user1, user2 int64
+	}
+	seeded, err := s.partnershipRepo.GetSeeded()
+	if err != nil {
+		return 0, err
+	}
+	partnerships := make(map[pairKey]models.Partnership, len(seeded))
+	for _, p := range seeded {
+		partnerships[pairKey{p.Sport, p.User1ID, p.User2ID}] = p
+	}
+	played := make(map[pairKey]bool)
+
+	games := 0
+	err = s.gameRepo.ForEachConfirmed(func(game *models.Game) error {
+		format := game.Format()
+		current := make(map[int64]models.SportRating, len(game.Participants))
+		for _, p := range game.Participants {
+			if r, ok := ratings[ratingKey{p.UserID, game.Sport, format}]; ok {
+				current[p.UserID] = r
+			}
+		}
+		for _, r := range rateGame(game, current) {
+			ratings[ratingKey{r.UserID, r.Sport, r.Format}] = r
+		}
+
+		if format == models.RatingFormatDoubles && game.ConfirmedAt != nil {
+			teams := make(map[int][]int64, 2)
+			for _, p := range game.Participants {
+				teams[p.Team] = append(teams[p.Team], p.UserID)
+			}
+			pairs := make(map[int]models.Partnership, 2)
+			for team := 1; team <= 2; team++ {
+				user1, user2 := teams[team][0], teams[team][1]
+				if user1 > user2 {
+					user1, user2 = user2, user1
+				}
+				if p, ok := partnerships[pairKey{game.Sport, user1, user2}]; ok && p.ActiveAt(*game.ConfirmedAt) {
+					pairs[team] = p
+				}
+			}
+			if len(pairs) > 0 {
+				for _, p := range ratePartnerships(game, current, pairs) {
+					key := pairKey{p.Sport, p.User1ID, p.User2ID}
+					partnerships[key] = p
+					played[key] = true
+				}
+			}
+		}
+
+		games++
+		return nil
+	})
+	if err != nil {
+		return games, fmt.Errorf("failed to replay games: %w", err)
+	}
+
+	all := make([]models.SportRating, 0, len(ratings))
+	for _, r := range ratings {
+		all = append(all, r)
+	}
+	if err := s.ratingRepo.ReplaceAll(all); err != nil {
+		return games, err
+	}
+
+	rated := make([]models.Partnership, 0, len(played))
+	for key := range played {
+		rated = append(rated, partnerships[key])
+	}
+	if err := s.partnershipRepo.ReplaceRatings(rated); err != nil {
+		return games, err
+	}
+
+	return games, nil
+}
+
+// attachRatings fills in the ratings of each profile. Profiles are still usable without them,
+// so failures are only logged.
+func attachRatings(ratingRepo *repository.RatingRepository, profiles ...*models.UserProfile) {
+	ids := make([]int64, len(profiles))
+	for i, p := range profiles {
+		ids[i] = p.ID
+	}
+
+	ratings, err := ratingRepo.GetForUsers(ids)
+	if err != nil {
+		fmt.Printf("Failed to load ratings: %v\n", err)
+		return
+	}
+	for _, p := range profiles {
+		p.Ratings = ratings[p.ID]
+	}
+}
+
+// rateGame computes the participants' ratings after a confirmed game. Teammates share their
+// team's result: each is rated against the opposing team's combined rating, so singles and
+// doubles use the same update and are only kept apart by format. Time since a player's last game
+// widens their deviation first.
+func rateGame(game *models.Game, current map[int64]models.SportRating) []models.SportRating {
+	cfg := config.AppConfig.Rating
+	tau := cfg.Tau
+	if tau <= 0 {
+		tau = rating.DefaultTau
+	}
+
+	before := make(map[int64]rating.Rating, len(game.Participants))
+	teams := make(map[int][]rating.Rating)
+	for _, p := range game.Participants {
+		r := rating.Default()
+		if existing, ok := current[p.UserID]; ok {
+			r = rateBefore(rating.Rating{Rating: existing.Rating, Deviation: existing.Deviation, Volatility: existing.Volatility},
+				existing.LastPlayedAt, game.PlayedAt)
+		}
+		before[p.UserID] = r
+		teams[p.Team] = append(teams[p.Team], r)
+	}
+	team1, team2 := rating.Team(teams[1]), rating.Team(teams[2])
+
+	updated := make([]models.SportRating, 0, len(game.Participants))
+	for _, p := range game.Participants {
+		own, opponents := team1, team2
+		if p.Team == 2 {
+			own, opponents = team2, team1
+		}
+
+		result := rating.TeamResult(before[p.UserID], own, opponents, teamScore(game.WinnerTeam, p.Team))
+		after := rating.Update(before[p.UserID], []rating.Result{result}, tau)
+
+		r := current[p.UserID]
+		r.UserID = p.UserID
+		r.Sport = game.Sport
+		r.Format = game.Format()
+		r.Rating = after.Rating
+		r.Deviation = after.Deviation
+		r.Volatility = after.Volatility
+		r.Confidence = after.Confidence()
+		r.Provisional = after.Provisional()
+		r.GamesPlayed++
+		if r.LastPlayedAt == nil || game.PlayedAt.After(*r.LastPlayedAt) {
+			playedAt := game.PlayedAt
+			r.LastPlayedAt = &playedAt
+		}
+		updated = append(updated, r)
+	}
+
+	return updated
+}
+
+// ratePartnerships computes the ratings of the partnerships that played a doubles game
+// together. A partnership is rated against the opposing partnership when the opponents are one
+// too, and otherwise against the opponents' combined ratings.
+func ratePartnerships(game *models.Game, current map[int64]models.SportRating, pairs map[int]models.Partnership) []models.Partnership {
+	tau := config.AppConfig.Rating.Tau
+	if tau <= 0 {
+		tau = rating.DefaultTau
+	}
+
+	strength := make(map[int]rating.Rating, 2)
+	for team := 1; team <= 2; team++ {
+		if p, ok := pairs[team]; ok {
+			strength[team] = rateBefore(rating.Rating{Rating: p.Rating, Deviation: p.Deviation, Volatility: p.Volatility},
+				p.LastPlayedAt, game.PlayedAt)
+			continue
+		}
+		var members []rating.Rating
+		for _, p := range game.Participants {
+			if p.Team != team {
+				continue
+			}
+			r := rating.Default()
+			if existing, ok := current[p.UserID]; ok {
+				r = rateBefore(rating.Rating{Rating: existing.Rating, Deviation: existing.Deviation, Volatility: existing.Volatility},
+					existing.LastPlayedAt, game.PlayedAt)
+			}
+			members = append(members, r)
+		}
+		strength[team] = rating.Team(members)
+	}
+
+	updated := make([]models.Partnership, 0, len(pairs))
+	for team := 1; team <= 2; team++ {
+		p, ok := pairs[team]
+		if !ok {
+			continue
+		}
+		result := rating.Result{Opponent: strength[3-team], Score: teamScore(game.WinnerTeam, team)}
+		after := rating.Update(strength[team], []rating.Result{result}, tau)
+
+		p.Rating = after.Rating
+		p.Deviation = after.Deviation
+		p.Volatility = after.Volatility
+		p.Confidence = after.Confidence()
+		p.Provisional = after.Provisional()
+		p.GamesPlayed++
+		if p.LastPlayedAt == nil || game.PlayedAt.After(*p.LastPlayedAt) {
+			playedAt := game.PlayedAt
+			p.LastPlayedAt = &playedAt
+		}
+		updated = append(updated, p)
+	}
+
+	return updated
+}
+
+// rateBefore widens a stored rating for the time since it was last played, ahead of an update
+// for a game played at playedAt
+func rateBefore(r rating.Rating, lastPlayedAt *time.Time, playedAt time.Time) rating.Rating {
+	period := config.AppConfig.Rating.Period
+	if lastPlayedAt == nil || period <= 0 {
+		return r
+	}
+	// The update itself accounts for one period
+	return rating.Idle(r, playedAt.Sub(*lastPlayedAt).Hours()/period.Hours()-1)
+}
+
+// teamScore is the Glicko score of a team: 1 for a win, 0 for a loss, 0.5 for a draw
+func teamScore(winner *int, team int) float64 {
+	switch {
+	case winner == nil:
+		return 0.5
+	case *winner == team:
+		return 1
+	default:
+		return 0
+	}
+}
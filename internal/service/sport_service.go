@@ -0,0 +1,168 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/redis"
+	"swipe-sports-backend/internal/repository"
+)
+
+var sportSlugPattern = regexp.MustCompile(`^[a-z0-9]+(?:[-_][a-z0-9]+)*$`)
+
+type SportService struct {
+	sportRepo *repository.SportRepository
+}
+
+func NewSportService() *SportService {
+	return &SportService{
+		sportRepo: repository.NewSportRepository(),
+	}
+}
+
+// ListSports returns the active sports catalog, served from cache when possible
+func (s *SportService) ListSports() ([]models.Sport, error) {
+	cachedData, err := redis.GetSportsCatalog()
+	if err == nil {
+		var sports []models.Sport
+		if err := json.Unmarshal(cachedData, &sports); err == nil {
+			return sports, nil
+		}
+	}
+
+	sports, err := s.sportRepo.GetAll(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sports: %w", err)
+	}
+
+	if data, err := json.Marshal(sports); err == nil {
+		if err := redis.SetSportsCatalog(data); err != nil {
+			fmt.Printf("Failed to cache sports catalog: %v\n", err)
+		}
+	}
+
+	return sports, nil
+}
+
+// ListAllSports returns every sport including deactivated ones (admin view)
+func (s *SportService) ListAllSports() ([]models.Sport, error) {
+	return s.sportRepo.GetAll(true)
+}
+
+func (s *SportService) GetSport(slug string) (*models.Sport, error) {
+	return s.sportRepo.GetBySlug(slug)
+}
+
+func (s *SportService) CreateSport(req models.CreateSportRequest) (*models.Sport, error) {
+	if !sportSlugPattern.MatchString(req.Slug) {
+		return nil, fmt.Errorf("invalid sport slug: use lowercase letters, digits, '-' or '_'")
+	}
+
+	existing, err := s.sportRepo.GetBySlug(req.Slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sport: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("sport %s already exists", req.Slug)
+	}
+
+	sport := &models.Sport{
+		Slug:         req.Slug,
+		Name:         req.Name,
+		DisplayNames: req.DisplayNames,
+		TeamSize:     req.TeamSize,
+		RatingSystem: req.RatingSystem,
+		SkillLevels:  req.SkillLevels,
+		Active:       true,
+	}
+	if sport.DisplayNames == nil {
+		sport.DisplayNames = models.LocalizedNames{"en": req.Name}
+	}
+
+	if err := s.sportRepo.Create(sport); err != nil {
+		return nil, fmt.Errorf("failed to create sport: %w", err)
+	}
+
+	s.invalidateCatalog()
+	return sport, nil
+}
+
+func (s *SportService) UpdateSport(slug string, req models.UpdateSportRequest) (*models.Sport, error) {
+	sport, err := s.sportRepo.GetBySlug(slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sport: %w", err)
+	}
+
+	if sport == nil {
+		return nil, nil
+	}
+
+	if req.Name != nil {
+		sport.Name = *req.Name
+	}
+	if req.DisplayNames != nil {
+		sport.DisplayNames = *req.DisplayNames
+	}
+	if req.TeamSize != nil {
+		sport.TeamSize = *req.TeamSize
+	}
+	if req.RatingSystem != nil {
+		sport.RatingSystem = *req.RatingSystem
+	}
+	if req.SkillLevels != nil {
+		sport.SkillLevels = *req.SkillLevels
+	}
+	if req.Active != nil {
+		sport.Active = *req.Active
+	}
+
+	if err := s.sportRepo.Update(sport); err != nil {
+		return nil, fmt.Errorf("failed to update sport: %w", err)
+	}
+
+	s.invalidateCatalog()
+	return sport, nil
+}
+
+// ValidateSportPreferences checks that every key is an active sport in the catalog and,
+// when a skill level is given, that each selected sport accepts it.
+func (s *SportService) ValidateSportPreferences(prefs models.SportPreferences, skillLevel string) error {
+	sports, err := s.ListSports()
+	if err != nil {
+		return fmt.Errorf("failed to load sports catalog: %w", err)
+	}
+
+	catalog := make(map[string]models.Sport, len(sports))
+	for _, sport := range sports {
+		catalog[sport.Slug] = sport
+	}
+
+	for slug, selected := range prefs {
+		sport, ok := catalog[slug]
+		if !ok {
+			return fmt.Errorf("unknown sport: %s", slug)
+		}
+		if selected && skillLevel != "" && !sport.HasSkillLevel(skillLevel) {
+			return fmt.Errorf("skill level %s is not available for %s", skillLevel, slug)
+		}
+	}
+
+	return nil
+}
+
+// ValidateSports checks that every slug is an active sport in the catalog
+func (s *SportService) ValidateSports(slugs []string) error {
+	prefs := make(models.SportPreferences, len(slugs))
+	for _, slug := range slugs {
+		prefs[slug] = true
+	}
+	return s.ValidateSportPreferences(prefs, "")
+}
+
+func (s *SportService) invalidateCatalog() {
+	if err := redis.DeleteSportsCatalog(); err != nil {
+		fmt.Printf("Failed to invalidate sports catalog cache: %v\n", err)
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"swipe-sports-backend/internal/config"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/redis"
+)
+
+const (
+	// deckSize is how many ranked profiles are stored per deck
+	deckSize = 100
+	// deckLowWatermark triggers a background rebuild once fewer cards are left
+	deckLowWatermark = 10
+	// deckRebuildCooldown limits background rebuilds to one per user in this window
+	deckRebuildCooldown = 30 * time.Second
+	// defaultPageSize is used when the request does not set a limit
+	defaultPageSize = 20
+)
+
+// GetProfiles returns a page of the user's swipe deck. Decks are ranked in batches and
+// stored in Redis so most requests never touch MySQL; the cursor stays valid as long as
+// the deck it points into is current, otherwise paging restarts at the top of the new deck.
+func (s *SwipeService) GetProfiles(userID int64, filter models.ProfileFilter) (*models.ProfilePage, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	cursor := filter.Cursor
+	filter.Limit, filter.Cursor = 0, ""
+
+	filterKey, err := deckFilterKey(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	s.touchLastActive(userID)
+
+	meta, err := redis.GetSwipeDeckMeta(userID)
+	rebuilt := false
+	if err != nil || meta.Filter != filterKey {
+		var profiles []models.UserProfile
+		profiles, meta, err = s.buildDeck(userID, filter)
+		if err != nil {
+			return nil, err
+		}
+		if err := storeDeck(userID, meta, profiles); err != nil {
+			// Serve the freshly ranked deck directly when Redis is unavailable. Every request
+			// ranks it again, so its version comes from its contents to keep the cursor valid
+			// while the ranking stays the same.
+			fmt.Printf("Failed to cache swipe deck: %v\n", err)
+			deckVersion := uncachedDeckVersion(filterKey, profiles)
+			version, after := decodeDeckCursor(cursor)
+			if version != deckVersion {
+				after = -1
+			}
+			return pageFromProfiles(profiles, deckVersion, after, limit), nil
+		}
+		rebuilt = true
+	}
+
+	version, after := decodeDeckCursor(cursor)
+	if version != meta.Version {
+		after = -1
+	}
+
+	cards, remaining, err := redis.GetSwipeDeckPage(userID, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read swipe deck: %w", err)
+	}
+
+	page := &models.ProfilePage{Profiles: make([]models.UserProfile, 0, len(cards))}
+	for _, card := range cards {
+		var profile models.UserProfile
+		if err := json.Unmarshal(card.Data, &profile); err != nil {
+			continue
+		}
+		page.Profiles = append(page.Profiles, profile)
+	}
+	if len(cards) > 0 && remaining > 0 {
+		page.NextCursor = encodeDeckCursor(meta.Version, cards[len(cards)-1].Position)
+	}
+
+	if !rebuilt && remaining < deckLowWatermark {
+		go s.refreshDeck(userID)
+	}
+
+	return page, nil
+}
+
+// buildDeck ranks a fresh batch of candidates for the user
+func (s *SwipeService) buildDeck(userID int64, filter models.ProfileFilter) ([]models.UserProfile, *redis.SwipeDeckMeta, error) {
+	viewer, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if viewer == nil {
+		return nil, nil, fmt.Errorf("user not found")
+	}
+
+	prefs, err := s.prefsRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	filterKey, err := deckFilterKey(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	poolSize := config.AppConfig.Ranking.CandidatePoolSize
+	if poolSize < deckSize {
+		poolSize = deckSize
+	}
+
+	candidates, err := s.userRepo.GetProfilesForSwipe(viewer, filter.WithDefaults(prefs), poolSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get profiles: %w", err)
+	}
+
+	scorer, err := s.newScorer()
+	if err != nil {
+		return nil, nil, err
+	}
+	if filter.PartnerSport != "" {
+		scorer.ForPartner(filter.PartnerSport)
+	}
+
+	userIDs := []int64{userID}
+	for _, c := range candidates {
+		userIDs = append(userIDs, c.Profile.ID)
+	}
+	if favourites, err := s.venueRepo.GetFavourites(userIDs); err != nil {
+		fmt.Printf("Failed to load favourite venues: %v\n", err)
+	} else {
+		scorer.WithVenues(favourites)
+	}
+
+	profiles := scorer.Rank(viewer.Profile(), candidates)
+	if len(profiles) > deckSize {
+		profiles = profiles[:deckSize]
+	}
+
+	cards := make([]*models.UserProfile, len(profiles))
+	for i := range profiles {
+		cards[i] = &profiles[i]
+	}
+	attachRatings(s.ratingRepo, cards...)
+
+	meta := &redis.SwipeDeckMeta{
+		Version: strconv.FormatInt(time.Now().UnixNano(), 36),
+		Filter:  filterKey,
+	}
+	return profiles, meta, nil
+}
+
+// refreshDeck rebuilds the user's deck with the filter it was last built with. It runs in
+// the background, so failures are only logged.
+func (s *SwipeService) refreshDeck(userID int64) {
+	acquired, err := redis.AcquireSwipeDeckLock(userID, deckRebuildCooldown)
+	if err != nil || !acquired {
+		return
+	}
+
+	var filter models.ProfileFilter
+	if meta, err := redis.GetSwipeDeckMeta(userID); err == nil {
+		if err := json.Unmarshal([]byte(meta.Filter), &filter); err != nil {
+			fmt.Printf("Failed to decode swipe deck filter: %v\n", err)
+			return
+		}
+	}
+
+	profiles, meta, err := s.buildDeck(userID, filter)
+	if err != nil {
+		fmt.Printf("Failed to rebuild swipe deck: %v\n", err)
+		return
+	}
+	if err := storeDeck(userID, meta, profiles); err != nil {
+		fmt.Printf("Failed to cache swipe deck: %v\n", err)
+	}
+}
+
+// consumeDeckCard removes a swiped profile from the deck and tops the deck up when it runs low
+func (s *SwipeService) consumeDeckCard(userID, profileID int64) {
+	remaining, err := redis.RemoveFromSwipeDeck(userID, profileID)
+	if err != nil {
+		fmt.Printf("Failed to update swipe deck: %v\n", err)
+		return
+	}
+	if remaining < deckLowWatermark {
+		go s.refreshDeck(userID)
+	}
+}
+
+// invalidateSwipeDeck drops the user's deck so the next request ranks a new one
+func invalidateSwipeDeck(userID int64) {
+	if err := redis.DeleteSwipeDeck(userID); err != nil {
+		fmt.Printf("Failed to invalidate swipe deck: %v\n", err)
+	}
+}
+
+func storeDeck(userID int64, meta *redis.SwipeDeckMeta, profiles []models.UserProfile) error {
+	cards := make([]redis.SwipeDeckCard, 0, len(profiles))
+	for i, profile := range profiles {
+		data, err := json.Marshal(profile)
+		if err != nil {
+			return fmt.Errorf("failed to marshal profile: %w", err)
+		}
+		cards = append(cards, redis.SwipeDeckCard{ProfileID: profile.ID, Position: int64(i), Data: data})
+	}
+	return redis.StoreSwipeDeck(userID, *meta, cards)
+}
+
+func pageFromProfiles(profiles []models.UserProfile, version string, after int64, limit int) *models.ProfilePage {
+	start := int(after + 1)
+	if start > len(profiles) {
+		start = len(profiles)
+	}
+	end := start + limit
+	if end > len(profiles) {
+		end = len(profiles)
+	}
+
+	page := &models.ProfilePage{Profiles: profiles[start:end]}
+	if end < len(profiles) {
+		page.NextCursor = encodeDeckCursor(version, int64(end-1))
+	}
+	return page
+}
+
+// uncachedDeckVersion identifies a deck that could not be stored by its filter and the order of
+// its profiles
+func uncachedDeckVersion(filterKey string, profiles []models.UserProfile) string {
+	hash := fnv.New64a()
+	hash.Write([]byte(filterKey))
+	for _, profile := range profiles {
+		hash.Write([]byte(":" + strconv.FormatInt(profile.ID, 10)))
+	}
+	return strconv.FormatUint(hash.Sum64(), 36)
+}
+
+// deckFilterKey identifies the criteria a deck was built with
+func deckFilterKey(filter models.ProfileFilter) (string, error) {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode filter: %w", err)
+	}
+	return string(data), nil
+}
+
+func encodeDeckCursor(version string, position int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(version + ":" + strconv.FormatInt(position, 10)))
+}
+
+// decodeDeckCursor returns the deck version and last seen position, or an empty version
+// for a missing or malformed cursor
+func decodeDeckCursor(cursor string) (string, int64) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", -1
+	}
+	parts := strings.SplitN(string(data), ":", 2)
+	if len(parts) != 2 {
+		return "", -1
+	}
+	position, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", -1
+	}
+	return parts[0], position
+}
@@ -0,0 +1,437 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"swipe-sports-backend/internal/config"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/ranking"
+	"swipe-sports-backend/internal/redis"
+	"swipe-sports-backend/internal/repository"
+	"swipe-sports-backend/internal/schedule"
+)
+
+type SwipeService struct {
+	swipeRepo    *repository.SwipeRepository
+	userRepo     *repository.UserRepository
+	prefsRepo    *repository.PreferencesRepository
+	ratingRepo   *repository.RatingRepository
+	proposalRepo *repository.ProposalRepository
+	venueRepo    *repository.VenueRepository
+	sportService *SportService
+}
+
+func NewSwipeService() *SwipeService {
+	return &SwipeService{
+		swipeRepo:    repository.NewSwipeRepository(),
+		userRepo:     repository.NewUserRepository(),
+		prefsRepo:    repository.NewPreferencesRepository(),
+		ratingRepo:   repository.NewRatingRepository(),
+		proposalRepo: repository.NewProposalRepository(),
+		venueRepo:    repository.NewVenueRepository(),
+		sportService: NewSportService(),
+	}
+}
+
+func (s *SwipeService) newScorer() (*ranking.Scorer, error) {
+	sports, err := s.sportService.ListSports()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sports catalog: %w", err)
+	}
+
+	cfg := config.AppConfig.Ranking
+	weights := ranking.Weights{
+		SharedSports: cfg.SharedSportsWeight,
+		Rating:       cfg.RatingWeight,
+		Availability: cfg.AvailabilityWeight,
+		Distance:     cfg.DistanceWeight,
+		Recency:      cfg.RecencyWeight,
+		Rank:         cfg.RankWeight,
+		Venues:       cfg.VenuesWeight,
+		Partner:      cfg.PartnerWeight,
+	}
+
+	return ranking.NewScorer(weights, sports, time.Now()), nil
+}
+
+func (s *SwipeService) touchLastActive(userID int64) {
+	if err := s.userRepo.TouchLastActive(userID); err != nil {
+		fmt.Printf("Failed to update last active: %v\n", err)
+	}
+}
+
+func (s *SwipeService) Swipe(userID int64, req models.SwipeRequest) (*models.SwipeResponse, error) {
+	if req.SwipeeID == userID {
+		return nil, fmt.Errorf("cannot swipe on yourself")
+	}
+
+	swipee, err := s.userRepo.GetByID(req.SwipeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if swipee == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	existing, err := s.swipeRepo.GetSwipe(userID, req.SwipeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swipe: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("already swiped on this user")
+	}
+
+	response := &models.SwipeResponse{Matched: false}
+
+	// Likes are limited per day; passing is free
+	var swiper *models.User
+	var quota models.QuotaKind
+	if req.Direction.IsLike() {
+		swiper, err = s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if swiper == nil {
+			return nil, fmt.Errorf("user not found")
+		}
+
+		quota = models.QuotaRightSwipes
+		if req.Direction == models.SwipeDirectionSuper {
+			quota = models.QuotaSuperLikes
+		}
+		remaining, err := consumeQuota(quota, swiper)
+		if err != nil {
+			return nil, err
+		}
+		if quota == models.QuotaSuperLikes {
+			response.SuperLikesRemaining = remaining
+		} else {
+			response.LikesRemaining = remaining
+		}
+	}
+
+	swipe := &models.Swipe{
+		SwiperID:  userID,
+		SwipeeID:  req.SwipeeID,
+		Direction: req.Direction,
+	}
+	if err := s.swipeRepo.CreateSwipe(swipe); err != nil {
+		if swiper != nil {
+			refundQuota(quota, swiper)
+		}
+		return nil, fmt.Errorf("failed to save swipe: %w", err)
+	}
+	s.touchLastActive(userID)
+	s.consumeDeckCard(userID, req.SwipeeID)
+
+	if !req.Direction.IsLike() {
+		return response, nil
+	}
+
+	// Check for a mutual like
+	reverse, err := s.swipeRepo.GetSwipe(req.SwipeeID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swipe: %w", err)
+	}
+	if reverse == nil || !reverse.Direction.IsLike() {
+		if req.Direction == models.SwipeDirectionSuper {
+			// Put the swiper at the front of the recipient's deck and tell them now
+			invalidateSwipeDeck(req.SwipeeID)
+			notifyUser(req.SwipeeID, models.WSMessage{
+				Type:    models.WSMessageTypeSuperLike,
+				Payload: models.WSSuperLikeMessage{From: swiper.Profile()},
+			})
+		}
+		return response, nil
+	}
+
+	match, err := s.swipeRepo.CreateMatch(userID, req.SwipeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create match: %w", err)
+	}
+	// A pair whose match was ended, for example liked again after an undo, is not matched
+	// again: one of them chose to end it
+	if match.Status != models.MatchStatusActive {
+		return response, nil
+	}
+
+	s.invalidateMatches(userID, req.SwipeeID)
+
+	matchResponse, err := s.GetMatch(userID, match.ID)
+	if err != nil || matchResponse == nil {
+		return response, err
+	}
+
+	response.Matched = true
+	response.Match = matchResponse
+	return response, nil
+}
+
+// GetMatches lists the user's matches. The list is cached; availability overlaps are always
+// computed fresh since they depend on the current time.
+func (s *SwipeService) GetMatches(userID int64) ([]models.MatchResponse, error) {
+	var matches []models.MatchResponse
+
+	cachedData, err := redis.GetUserMatches(userID)
+	if err != nil || json.Unmarshal(cachedData, &matches) != nil {
+		matches, err = s.swipeRepo.GetMatchesForUser(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get matches: %w", err)
+		}
+
+		if data, err := json.Marshal(matches); err == nil {
+			if err := redis.SetUserMatches(userID, data); err != nil {
+				fmt.Printf("Failed to cache matches: %v\n", err)
+			}
+		}
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user != nil {
+		now := time.Now()
+		for i := range matches {
+			matches[i].AvailabilityOverlap = schedule.Overlap(user.Availability, matches[i].User.Availability, now, now.Add(schedule.DefaultHorizon))
+		}
+	}
+
+	return matches, nil
+}
+
+// GetMatch returns a single match from the user's point of view, or nil if the user is not part of it
+func (s *SwipeService) GetMatch(userID, matchID int64) (*models.MatchResponse, error) {
+	match, err := s.swipeRepo.GetMatchByID(matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match: %w", err)
+	}
+	if match == nil || match.Status != models.MatchStatusActive || match.Partner(userID) == 0 {
+		return nil, nil
+	}
+
+	otherID := match.Partner(userID)
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	other, err := s.userRepo.GetProfileByID(otherID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || other == nil {
+		return nil, nil
+	}
+	attachRatings(s.ratingRepo, other)
+
+	now := time.Now()
+	return &models.MatchResponse{
+		ID:                  match.ID,
+		User:                *other,
+		AvailabilityOverlap: schedule.Overlap(user.Availability, other.Availability, now, now.Add(schedule.DefaultHorizon)),
+		CreatedAt:           match.CreatedAt,
+	}, nil
+}
+
+func (s *SwipeService) invalidateMatches(userIDs ...int64) {
+	for _, userID := range userIDs {
+		if err := redis.DeleteUserMatches(userID); err != nil {
+			fmt.Printf("Failed to invalidate matches cache: %v\n", err)
+		}
+	}
+}
+
+// UndoSwipe reverts the user's most recent swipe if it is still within the undo window. A match
+// that relied on the swipe is withdrawn too, as long as nobody has written in it yet, and the
+// partner is told live.
+func (s *SwipeService) UndoSwipe(userID int64) (*models.UndoSwipeResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	swipe, err := s.swipeRepo.GetLatestSwipe(userID)
+	if err != nil {
+		return nil, err
+	}
+	if swipe == nil {
+		return nil, fmt.Errorf("no swipe to undo")
+	}
+	if time.Since(swipe.CreatedAt) > config.AppConfig.Swipe.UndoWindow {
+		return nil, fmt.Errorf("swipe can no longer be undone")
+	}
+
+	var match *models.Match
+	if swipe.Direction.IsLike() {
+		match, err = s.swipeRepo.GetMatchBetween(userID, swipe.SwipeeID)
+		if err != nil {
+			return nil, err
+		}
+		// An ended match stays on record
+		if match != nil && match.Status != models.MatchStatusActive {
+			match = nil
+		}
+	}
+	var matchID int64
+	if match != nil {
+		messages, err := s.swipeRepo.CountMessages(match.ID)
+		if err != nil {
+			return nil, err
+		}
+		if messages > 0 {
+			return nil, fmt.Errorf("cannot undo a swipe once the conversation has started")
+		}
+		matchID = match.ID
+	}
+
+	remaining, err := consumeQuota(models.QuotaRewinds, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.swipeRepo.UndoSwipe(swipe.ID, matchID); err != nil {
+		refundQuota(models.QuotaRewinds, user)
+		return nil, err
+	}
+
+	// Re-rank so the profile can come back into the deck
+	invalidateSwipeDeck(userID)
+
+	if match != nil {
+		s.invalidateMatches(userID, swipe.SwipeeID)
+		// Both users are told so open chat connections for the match are closed
+		event := models.WSMessage{
+			Type:    models.WSMessageTypeMatchUndone,
+			Payload: models.WSMatchUndoneMessage{MatchID: match.ID, UserID: userID},
+		}
+		notifyUser(swipe.SwipeeID, event)
+		notifyUser(userID, event)
+	}
+
+	profile, err := s.userRepo.GetProfileByID(swipe.SwipeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	return &models.UndoSwipeResponse{
+		Swipe:            *swipe,
+		Profile:          profile,
+		MatchRemoved:     match != nil,
+		RewindsRemaining: remaining,
+	}, nil
+}
+
+// Unmatch ends a match on behalf of one of its users. It returns nil if the user is not part
+// of the match. The match is kept on record but disappears from both users' lists, and both
+// are told live so their chat connections for it close.
+func (s *SwipeService) Unmatch(userID, matchID int64, req models.UnmatchRequest) (*models.Match, error) {
+	match, err := s.swipeRepo.GetMatchByID(matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match: %w", err)
+	}
+	if match == nil || match.Partner(userID) == 0 {
+		return nil, nil
+	}
+
+	var reason *models.EndReason
+	if req.Reason != "" {
+		reason = &req.Reason
+	}
+
+	ended, err := s.swipeRepo.EndMatch(matchID, userID, reason)
+	if err != nil {
+		return nil, err
+	}
+	if !ended {
+		return nil, fmt.Errorf("match already ended")
+	}
+
+	s.invalidateMatches(match.User1ID, match.User2ID)
+	if err := redis.DeleteMatchMessages(matchID); err != nil {
+		fmt.Printf("Failed to invalidate match messages cache: %v\n", err)
+	}
+	// Games still to come are off; calendar feeds pick up the cancellation
+	if err := s.proposalRepo.CancelUpcomingForMatch(matchID, time.Now()); err != nil {
+		fmt.Printf("Failed to cancel scheduled games: %v\n", err)
+	}
+
+	event := models.WSMessage{
+		Type:    models.WSMessageTypeMatchEnded,
+		Payload: models.WSMatchEndedMessage{MatchID: matchID, EndedBy: userID},
+	}
+	notifyUser(match.User1ID, event)
+	notifyUser(match.User2ID, event)
+
+	return s.swipeRepo.GetMatchByID(matchID)
+}
+
+// GetQuotas reports the user's daily swipe allowances and how much of them is used
+func (s *SwipeService) GetQuotas(userID int64) (*models.QuotaStatus, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return quotaStatus(user)
+}
+
+// GetReceivedLikes lists the people who liked the user and have not been swiped on by them
+// yet, newest first. Answering one is an ordinary swipe.
+func (s *SwipeService) GetReceivedLikes(userID int64, query models.ReceivedLikesQuery) (*models.ReceivedLikesPage, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	beforeID, err := decodeIDCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra to know whether there is another page
+	likes, swipeIDs, err := s.swipeRepo.GetReceivedLikes(userID, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ReceivedLikesPage{Likes: likes}
+	if len(likes) > limit {
+		page.Likes = likes[:limit]
+		page.NextCursor = encodeIDCursor(swipeIDs[limit-1])
+	}
+
+	return page, nil
+}
+
+func encodeIDCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeIDCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	id, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return id, nil
+}
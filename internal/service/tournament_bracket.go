@@ -0,0 +1,186 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"swipe-sports-backend/internal/bracket"
+	"swipe-sports-backend/internal/models"
+)
+
+// loadBracket decodes a tournament's stored bracket
+func loadBracket(t *models.Tournament) (*bracket.Bracket, error) {
+	if len(t.State) == 0 {
+		return nil, fmt.Errorf("tournament has not started")
+	}
+	var b bracket.Bracket
+	if err := json.Unmarshal(t.State, &b); err != nil {
+		return nil, fmt.Errorf("failed to decode bracket: %w", err)
+	}
+	return &b, nil
+}
+
+// saveBracket stores the bracket in the tournament, completing it once every match is decided
+func saveBracket(t *models.Tournament, b *bracket.Bracket) error {
+	state, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("failed to encode bracket: %w", err)
+	}
+	t.State = state
+	if b.Done() {
+		t.Status = models.TournamentStatusCompleted
+		if champion := b.Champion(); champion != 0 {
+			t.WinnerID = &champion
+		}
+	}
+	return nil
+}
+
+// describeBracket lays out a tournament's stored bracket for display, naming the players from
+// the tournament's entries
+func describeBracket(t *models.Tournament) (*models.TournamentBracket, error) {
+	b, err := loadBracket(t)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int64]string, len(t.Entries))
+	for _, e := range t.Entries {
+		if e.User != nil {
+			names[e.UserID] = e.User.Name
+		}
+	}
+	entrants := make(map[int64]*models.BracketEntrant, len(b.Seeds))
+	for i, id := range b.Seeds {
+		entrants[id] = &models.BracketEntrant{UserID: id, Name: names[id], Seed: i + 1}
+	}
+
+	view := &models.TournamentBracket{Format: t.Format, Rounds: []models.BracketRound{}}
+	for _, m := range b.Matches {
+		last := len(view.Rounds) - 1
+		if last < 0 || view.Rounds[last].Section != string(m.Section) || view.Rounds[last].Round != m.Round {
+			view.Rounds = append(view.Rounds, models.BracketRound{Section: string(m.Section), Round: m.Round})
+			last++
+		}
+		match, err := describeMatch(m, entrants)
+		if err != nil {
+			return nil, err
+		}
+		view.Rounds[last].Matches = append(view.Rounds[last].Matches, match)
+	}
+	nameRounds(b.Format, view.Rounds)
+
+	if b.Format == bracket.RoundRobin {
+		for _, s := range b.Standings() {
+			view.Standings = append(view.Standings, models.BracketStanding{
+				BracketEntrant: *entrants[s.ID],
+				Played:         s.Played,
+				Wins:           s.Wins,
+				Draws:          s.Draws,
+				Losses:         s.Losses,
+				Points:         s.Points,
+			})
+		}
+	}
+	if b.Done() {
+		view.Champion = entrants[b.Champion()]
+	}
+
+	return view, nil
+}
+
+func describeMatch(m bracket.Match, entrants map[int64]*models.BracketEntrant) (models.BracketMatch, error) {
+	match := models.BracketMatch{
+		ID:       m.ID,
+		Position: m.Position,
+		Status:   string(m.Status),
+		Winner:   entrants[m.Winner],
+	}
+	for i, player := range m.Players {
+		slot := models.BracketSlot{Entrant: entrants[player]}
+		switch {
+		case slot.Entrant != nil:
+			slot.Label = slot.Entrant.Name
+		case m.Sources[i].Match == 0 || m.Status == bracket.StatusBye:
+			slot.Label = "Bye"
+		case m.Sources[i].Loser:
+			slot.Label = fmt.Sprintf("Loser of match %d", m.Sources[i].Match)
+		default:
+			slot.Label = fmt.Sprintf("Winner of match %d", m.Sources[i].Match)
+		}
+		match.Slots[i] = slot
+	}
+	var err error
+	if match.Score, err = decodeScore(m.Score); err != nil {
+		return match, err
+	}
+	if m.Report != nil {
+		report := &models.BracketReport{ReportedBy: entrants[m.Report.By], Winner: entrants[m.Report.Winner]}
+		if report.Score, err = decodeScore(m.Report.Score); err != nil {
+			return match, err
+		}
+		match.Report = report
+	}
+	return match, nil
+}
+
+// decodeScore reads a score stored in the bracket, or returns nil if there is none
+func decodeScore(raw json.RawMessage) (*models.GameScore, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var score models.GameScore
+	if err := json.Unmarshal(raw, &score); err != nil {
+		return nil, fmt.Errorf("failed to decode score: %w", err)
+	}
+	return &score, nil
+}
+
+// nameRounds names the rounds as organizers would call them out: the last rounds of the
+// winners' bracket by how many players are left, the rest by number
+func nameRounds(format bracket.Format, rounds []models.BracketRound) {
+	winners, losers := 0, 0
+	for _, r := range rounds {
+		switch bracket.Section(r.Section) {
+		case bracket.Winners:
+			winners = r.Round
+		case bracket.Losers:
+			losers = r.Round
+		}
+	}
+
+	for i := range rounds {
+		r := &rounds[i]
+		switch bracket.Section(r.Section) {
+		case bracket.Winners:
+			switch winners - r.Round {
+			case 0:
+				if format == bracket.DoubleElimination {
+					r.Name = "Winners' final"
+				} else {
+					r.Name = "Final"
+				}
+			case 1:
+				r.Name = "Semi-finals"
+			case 2:
+				r.Name = "Quarter-finals"
+			default:
+				r.Name = fmt.Sprintf("Round %d", r.Round)
+			}
+		case bracket.Losers:
+			if r.Round == losers {
+				r.Name = "Losers' final"
+			} else {
+				r.Name = fmt.Sprintf("Losers' round %d", r.Round)
+			}
+		case bracket.Final:
+			if r.Round == 1 {
+				r.Name = "Grand final"
+			} else {
+				r.Name = "Grand final reset"
+			}
+		default:
+			r.Name = fmt.Sprintf("Round %d", r.Round)
+		}
+	}
+}
@@ -0,0 +1,493 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"swipe-sports-backend/internal/bracket"
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/rating"
+	"swipe-sports-backend/internal/repository"
+)
+
+// maxTournamentsPerPage caps the tournament list
+const maxTournamentsPerPage = 50
+
+// tournamentExportVersion is bumped whenever the export format changes incompatibly
+const tournamentExportVersion = 1
+
+// tournamentRatingFormats is the order in which a player's ratings are preferred for seeding
+var tournamentRatingFormats = []models.RatingFormat{models.RatingFormatSingles, models.RatingFormatDoubles, models.RatingFormatTeam}
+
+type TournamentService struct {
+	tournamentRepo *repository.TournamentRepository
+	swipeRepo      *repository.SwipeRepository
+	userRepo       *repository.UserRepository
+	ratingRepo     *repository.RatingRepository
+	sportService   *SportService
+}
+
+func NewTournamentService() *TournamentService {
+	return &TournamentService{
+		tournamentRepo: repository.NewTournamentRepository(),
+		swipeRepo:      repository.NewSwipeRepository(),
+		userRepo:       repository.NewUserRepository(),
+		ratingRepo:     repository.NewRatingRepository(),
+		sportService:   NewSportService(),
+	}
+}
+
+// CreateTournament opens a tournament for registration. A tournament run for a group chat is
+// only visible to the group's members.
+func (s *TournamentService) CreateTournament(organizerID int64, req models.CreateTournamentRequest) (*models.Tournament, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	sport, err := s.sportService.GetSport(req.Sport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sport: %w", err)
+	}
+	if sport == nil || !sport.Active {
+		return nil, fmt.Errorf("unknown sport %s", req.Sport)
+	}
+	if !req.StartsAt.After(time.Now()) {
+		return nil, fmt.Errorf("start time must be in the future")
+	}
+	if req.GroupID != nil {
+		if err := checkGroupMember(s.swipeRepo, organizerID, *req.GroupID); err != nil {
+			return nil, err
+		}
+	}
+
+	tournament := &models.Tournament{
+		OrganizerID: organizerID,
+		GroupID:     req.GroupID,
+		Name:        name,
+		Sport:       sport.Slug,
+		Format:      req.Format,
+		Status:      models.TournamentStatusRegistration,
+		MaxEntrants: req.MaxEntrants,
+		StartsAt:    req.StartsAt.UTC(),
+		Venue:       req.Venue,
+		Note:        req.Note,
+	}
+	if err := s.tournamentRepo.Create(tournament); err != nil {
+		return nil, err
+	}
+	return s.GetTournament(organizerID, tournament.ID)
+}
+
+// ListTournaments returns the tournaments open for registration or under way, soonest first:
+// the public ones, or a group's when the query names a group the user is in
+func (s *TournamentService) ListTournaments(userID int64, query models.TournamentQuery) ([]models.Tournament, error) {
+	if query.GroupID != nil {
+		if err := checkGroupMember(s.swipeRepo, userID, *query.GroupID); err != nil {
+			return nil, err
+		}
+	}
+
+	tournaments, err := s.tournamentRepo.List(query.Sport, query.GroupID, maxTournamentsPerPage)
+	if err != nil {
+		return nil, err
+	}
+	return tournaments, s.attachOrganizers(tournaments)
+}
+
+// GetMyTournaments returns the tournaments the user organizes or is entered in, newest first
+func (s *TournamentService) GetMyTournaments(userID int64) ([]models.Tournament, error) {
+	tournaments, err := s.tournamentRepo.GetForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return tournaments, s.attachOrganizers(tournaments)
+}
+
+// GetTournament returns a tournament with its entries and bracket, or nil if the user cannot
+// see it
+func (s *TournamentService) GetTournament(userID, tournamentID int64) (*models.Tournament, error) {
+	tournament, err := s.tournamentRepo.GetByID(tournamentID)
+	if err != nil || tournament == nil {
+		return nil, err
+	}
+	if err := s.describe(tournament); err != nil {
+		return nil, err
+	}
+
+	visible, err := s.canSee(userID, tournament)
+	if err != nil || !visible {
+		return nil, err
+	}
+	return tournament, nil
+}
+
+// Register enters the user in the tournament. It returns nil if the user cannot see it.
+func (s *TournamentService) Register(userID, tournamentID int64) (*models.Tournament, error) {
+	tournament, err := s.GetTournament(userID, tournamentID)
+	if err != nil || tournament == nil {
+		return nil, err
+	}
+	if tournament.Status != models.TournamentStatusRegistration {
+		return nil, fmt.Errorf("registration is closed")
+	}
+	if tournament.GroupID != nil {
+		if err := checkGroupMember(s.swipeRepo, userID, *tournament.GroupID); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range tournament.Entries {
+		if e.UserID == userID {
+			return nil, fmt.Errorf("you are already registered")
+		}
+	}
+
+	registered, err := s.tournamentRepo.Register(tournamentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !registered {
+		return nil, fmt.Errorf("tournament is full or registration is closed")
+	}
+
+	return s.publish(tournamentID, 0)
+}
+
+// Withdraw takes the user out of a tournament before it starts. It returns nil if the user
+// cannot see it.
+func (s *TournamentService) Withdraw(userID, tournamentID int64) (*models.Tournament, error) {
+	tournament, err := s.GetTournament(userID, tournamentID)
+	if err != nil || tournament == nil {
+		return nil, err
+	}
+
+	withdrawn, err := s.tournamentRepo.Withdraw(tournamentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !withdrawn {
+		return nil, fmt.Errorf("you are not registered, or the tournament has started")
+	}
+
+	return s.publish(tournamentID, 0, userID)
+}
+
+// Start closes registration, seeds the entrants by their rating in the sport and draws the
+// bracket. It returns nil if the tournament is not the organizer's.
+func (s *TournamentService) Start(organizerID, tournamentID int64) (*models.Tournament, error) {
+	tournament, err := s.GetTournament(organizerID, tournamentID)
+	if err != nil || tournament == nil || tournament.OrganizerID != organizerID {
+		return nil, err
+	}
+	if tournament.Status != models.TournamentStatusRegistration {
+		return nil, fmt.Errorf("tournament has already started")
+	}
+	if len(tournament.Entries) < bracket.MinEntrants {
+		return nil, fmt.Errorf("at least %d players must register first", bracket.MinEntrants)
+	}
+
+	entries, seeds, err := s.seed(tournament)
+	if err != nil {
+		return nil, err
+	}
+	drawn, err := bracket.New(bracket.Format(tournament.Format), seeds)
+	if err != nil {
+		return nil, err
+	}
+	state, err := json.Marshal(drawn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bracket: %w", err)
+	}
+
+	started, err := s.tournamentRepo.Start(tournamentID, entries, state)
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		return nil, fmt.Errorf("tournament has already started")
+	}
+
+	return s.publish(tournamentID, 0)
+}
+
+// ReportResult reports the result of a bracket match; the first slot of the match is team 1 in
+// the score. A player's report waits for the other player or the organizer to confirm it. The
+// organizer's is recorded straight away, and can correct a result until a later match that
+// depends on it has been played. It returns nil if the user cannot see the tournament.
+func (s *TournamentService) ReportResult(userID, tournamentID int64, matchID int, req models.TournamentResultRequest) (*models.Tournament, error) {
+	tournament, err := s.GetTournament(userID, tournamentID)
+	if err != nil || tournament == nil {
+		return nil, err
+	}
+	if tournament.Status != models.TournamentStatusInProgress {
+		return nil, fmt.Errorf("tournament is not under way")
+	}
+	if err := req.Score.Validate(); err != nil {
+		return nil, err
+	}
+	score, err := json.Marshal(req.Score)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode score: %w", err)
+	}
+
+	updated, err := s.tournamentRepo.UpdateBracket(tournamentID, func(t *models.Tournament) error {
+		b, err := loadBracket(t)
+		if err != nil {
+			return err
+		}
+		m := b.Match(matchID)
+		if m == nil {
+			return fmt.Errorf("match %d not found", matchID)
+		}
+		player := userID == m.Players[0] || userID == m.Players[1]
+		if userID != t.OrganizerID && !player {
+			return fmt.Errorf("only the organizer or the players can report this result")
+		}
+
+		var winner int64
+		if team := req.Score.Winner(); team != nil {
+			winner = m.Players[*team-1]
+		}
+		// An organizer playing in the match reports like any other player
+		switch {
+		case player:
+			err = b.Report(matchID, userID, winner, score)
+		case m.Status == bracket.StatusCompleted:
+			err = b.Correct(matchID, winner, score)
+		default:
+			err = b.Record(matchID, winner, score)
+		}
+		if err != nil {
+			return err
+		}
+
+		return saveBracket(t, b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("tournament is not under way")
+	}
+
+	return s.publish(tournamentID, matchID)
+}
+
+// ConfirmResult records the result a player reported for a bracket match and moves the players
+// on. The other player or the organizer can confirm it. It returns nil if the user cannot see
+// the tournament.
+func (s *TournamentService) ConfirmResult(userID, tournamentID int64, matchID int) (*models.Tournament, error) {
+	tournament, err := s.GetTournament(userID, tournamentID)
+	if err != nil || tournament == nil {
+		return nil, err
+	}
+	if tournament.Status != models.TournamentStatusInProgress {
+		return nil, fmt.Errorf("tournament is not under way")
+	}
+
+	updated, err := s.tournamentRepo.UpdateBracket(tournamentID, func(t *models.Tournament) error {
+		b, err := loadBracket(t)
+		if err != nil {
+			return err
+		}
+		m := b.Match(matchID)
+		if m == nil {
+			return fmt.Errorf("match %d not found", matchID)
+		}
+		if userID != t.OrganizerID && userID != m.Players[0] && userID != m.Players[1] {
+			return fmt.Errorf("only the organizer or the players can confirm this result")
+		}
+
+		if err := b.Confirm(matchID, userID); err != nil {
+			return err
+		}
+		return saveBracket(t, b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("tournament is not under way")
+	}
+
+	return s.publish(tournamentID, matchID)
+}
+
+// Cancel calls off a tournament that has not finished. It returns nil if the tournament is not
+// the organizer's.
+func (s *TournamentService) Cancel(organizerID, tournamentID int64) (*models.Tournament, error) {
+	tournament, err := s.GetTournament(organizerID, tournamentID)
+	if err != nil || tournament == nil || tournament.OrganizerID != organizerID {
+		return nil, err
+	}
+
+	cancelled, err := s.tournamentRepo.Cancel(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, fmt.Errorf("tournament has already finished")
+	}
+
+	return s.publish(tournamentID, 0)
+}
+
+// seed rates the entrants in the tournament's sport, by their rating when they have one and
+// otherwise by their overall rank, and returns the entries with their seeds in seed order
+func (s *TournamentService) seed(tournament *models.Tournament) ([]models.TournamentEntry, []int64, error) {
+	ids := make([]int64, len(tournament.Entries))
+	for i, e := range tournament.Entries {
+		ids[i] = e.UserID
+	}
+	ratings, err := s.ratingRepo.GetForUsers(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entrants := make([]bracket.Entrant, len(tournament.Entries))
+	for i, e := range tournament.Entries {
+		value := rating.DefaultRating
+		if e.User != nil {
+			value += float64(e.User.Rank - defaultRank)
+		}
+		if r := sportRating(ratings[e.UserID], tournament.Sport, tournamentRatingFormats); r != nil {
+			value = r.Rating
+		}
+		entrants[i] = bracket.Entrant{ID: e.UserID, Rating: value}
+	}
+	seeds := bracket.Seed(entrants)
+
+	byID := make(map[int64]bracket.Entrant, len(entrants))
+	for _, e := range entrants {
+		byID[e.ID] = e
+	}
+	entries := make([]models.TournamentEntry, len(seeds))
+	for i, id := range seeds {
+		seed, value := i+1, byID[id].Rating
+		entries[i] = models.TournamentEntry{TournamentID: tournament.ID, UserID: id, Seed: &seed, Rating: &value}
+	}
+	return entries, seeds, nil
+}
+
+// canSee allows everyone to see public tournaments, and the organizer, entrants and group
+// members to see a group's
+func (s *TournamentService) canSee(userID int64, tournament *models.Tournament) (bool, error) {
+	if tournament.GroupID == nil || tournament.OrganizerID == userID {
+		return true, nil
+	}
+	for _, e := range tournament.Entries {
+		if e.UserID == userID {
+			return true, nil
+		}
+	}
+
+	inGroup, err := s.swipeRepo.IsUserInMatch(userID, *tournament.GroupID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check group: %w", err)
+	}
+	return inGroup, nil
+}
+
+// describe fills in the organizer, the entries and the bracket
+func (s *TournamentService) describe(tournament *models.Tournament) error {
+	organizer, err := s.userRepo.GetByID(tournament.OrganizerID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if organizer != nil {
+		profile := organizer.Profile()
+		tournament.Organizer = &profile
+	}
+
+	if tournament.Entries, err = s.tournamentRepo.GetEntries(tournament.ID); err != nil {
+		return err
+	}
+	if len(tournament.State) > 0 {
+		if tournament.Bracket, err = describeBracket(tournament); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *TournamentService) attachOrganizers(tournaments []models.Tournament) error {
+	ids := make([]int64, len(tournaments))
+	for i, t := range tournaments {
+		ids[i] = t.OrganizerID
+	}
+	profiles, err := s.userRepo.GetProfilesByIDs(ids)
+	if err != nil {
+		return err
+	}
+	for i := range tournaments {
+		tournaments[i].Organizer = profiles[tournaments[i].OrganizerID]
+	}
+	return nil
+}
+
+// publish reloads the tournament and pushes it to the organizer, the entrants and anyone else
+// affected, such as a player who just withdrew
+func (s *TournamentService) publish(tournamentID int64, matchID int, also ...int64) (*models.Tournament, error) {
+	tournament, err := s.tournamentRepo.GetByID(tournamentID)
+	if err != nil || tournament == nil {
+		return nil, err
+	}
+	if err := s.describe(tournament); err != nil {
+		return nil, err
+	}
+
+	event := models.WSMessage{
+		Type:    models.WSMessageTypeTournamentUpdated,
+		Payload: models.WSTournamentMessage{Tournament: tournament, MatchID: matchID},
+	}
+	recipients := append([]int64{tournament.OrganizerID}, also...)
+	for _, e := range tournament.Entries {
+		recipients = append(recipients, e.UserID)
+	}
+	for _, id := range uniqueIDs(recipients, nil) {
+		notifyUser(id, event)
+	}
+
+	return tournament, nil
+}
+
+// Export returns the printable snapshot of a tournament, or nil if the user cannot see it
+func (s *TournamentService) Export(userID, tournamentID int64) (*models.TournamentExport, error) {
+	tournament, err := s.GetTournament(userID, tournamentID)
+	if err != nil || tournament == nil {
+		return nil, err
+	}
+
+	export := &models.TournamentExport{
+		Version:    tournamentExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Tournament: models.TournamentSummary{
+			ID:       tournament.ID,
+			Name:     tournament.Name,
+			Sport:    tournament.Sport,
+			Format:   tournament.Format,
+			Status:   tournament.Status,
+			StartsAt: tournament.StartsAt,
+			Venue:    tournament.Venue,
+		},
+		Entrants: []models.BracketEntrant{},
+		Bracket:  tournament.Bracket,
+	}
+	if tournament.Organizer != nil {
+		export.Tournament.Organizer = tournament.Organizer.Name
+	}
+	for _, e := range tournament.Entries {
+		entrant := models.BracketEntrant{UserID: e.UserID}
+		if e.User != nil {
+			entrant.Name = e.User.Name
+		}
+		if e.Seed != nil {
+			entrant.Seed = *e.Seed
+		}
+		export.Entrants = append(export.Entrants, entrant)
+	}
+
+	return export, nil
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"swipe-sports-backend/internal/models"
+)
+
+// maxVenueImportRows caps the size of a single bulk import
+const maxVenueImportRows = 5000
+
+// VenueImportFormat is the file format of a bulk import
+type VenueImportFormat string
+
+const (
+	VenueImportCSV     VenueImportFormat = "csv"
+	VenueImportGeoJSON VenueImportFormat = "geojson"
+)
+
+// DetectVenueImportFormat picks the format from the file name, falling back to sniffing the
+// content: GeoJSON is a JSON object, anything else is read as CSV
+func DetectVenueImportFormat(filename string, data []byte) VenueImportFormat {
+	name := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(name, ".csv"):
+		return VenueImportCSV
+	case strings.HasSuffix(name, ".geojson"), strings.HasSuffix(name, ".json"):
+		return VenueImportGeoJSON
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return VenueImportGeoJSON
+	}
+	return VenueImportCSV
+}
+
+// ImportVenues creates the venues of a CSV or GeoJSON file, updating those whose external_id
+// was imported before. Invalid rows are reported and skipped; the rest are still imported.
+//
+// CSV files need a header row. name, latitude and longitude are required; the other columns
+// are external_id, address, sports (separated by ";"), surface, indoor, lights, cost_per_hour,
+// currency, time_zone and opening_hours ("monday 07:00-22:00; saturday 08:00-20:00").
+// GeoJSON files are a FeatureCollection of Points with the same keys as properties, where
+// sports may also be an array and opening_hours an object as in the API.
+func (s *VenueService) ImportVenues(data []byte, format VenueImportFormat) (*models.VenueImportResult, error) {
+	var rows []venueImportRow
+	var err error
+	switch format {
+	case VenueImportCSV:
+		rows, err = parseVenueCSV(data)
+	case VenueImportGeoJSON:
+		rows, err = parseVenueGeoJSON(data)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.VenueImportResult{Errors: []models.VenueImportError{}}
+	for _, row := range rows {
+		if row.err != nil {
+			result.Errors = append(result.Errors, models.VenueImportError{Row: row.number, Error: row.err.Error()})
+			continue
+		}
+		venue, err := s.newVenue(row.req)
+		if err != nil {
+			result.Errors = append(result.Errors, models.VenueImportError{Row: row.number, Error: err.Error()})
+			continue
+		}
+		created, err := s.venueRepo.Upsert(venue)
+		if err != nil {
+			fmt.Printf("Failed to import venue: %v\n", err)
+			result.Errors = append(result.Errors, models.VenueImportError{Row: row.number, Error: "failed to save venue"})
+			continue
+		}
+		if created {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+
+	return result, nil
+}
+
+// venueImportRow is one parsed row; err is set when it could not be read
+type venueImportRow struct {
+	number int
+	req    models.CreateVenueRequest
+	err    error
+}
+
+func parseVenueCSV(data []byte) ([]venueImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: missing header row")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "latitude", "longitude"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("invalid CSV: missing %s column", required)
+		}
+	}
+
+	var rows []venueImportRow
+	for number := 1; ; number++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if _, ok := err.(*csv.ParseError); !ok {
+				return nil, fmt.Errorf("invalid CSV: %w", err)
+			}
+			rows = append(rows, venueImportRow{number: number, err: err})
+			continue
+		}
+		if len(rows) == maxVenueImportRows {
+			return nil, fmt.Errorf("too many venues in one import (max %d)", maxVenueImportRows)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		req, err := venueFromFields(field)
+		rows = append(rows, venueImportRow{number: number, req: req, err: err})
+	}
+	return rows, nil
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Geometry *struct {
+			Type        string    `json:"type"`
+			Coordinates []float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	} `json:"features"`
+}
+
+func parseVenueGeoJSON(data []byte) ([]venueImportRow, error) {
+	var collection geoJSONFeatureCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("invalid GeoJSON: expected a FeatureCollection")
+	}
+	if len(collection.Features) > maxVenueImportRows {
+		return nil, fmt.Errorf("too many venues in one import (max %d)", maxVenueImportRows)
+	}
+
+	rows := make([]venueImportRow, 0, len(collection.Features))
+	for i, feature := range collection.Features {
+		row := venueImportRow{number: i + 1}
+		if feature.Geometry == nil || feature.Geometry.Type != "Point" || len(feature.Geometry.Coordinates) < 2 {
+			row.err = fmt.Errorf("geometry must be a Point")
+			rows = append(rows, row)
+			continue
+		}
+
+		// Array and object properties keep their JSON form; strings and numbers are unquoted
+		props := feature.Properties
+		field := func(name string) string {
+			raw, ok := props[name]
+			if !ok {
+				return ""
+			}
+			var s string
+			if err := json.Unmarshal(raw, &s); err == nil {
+				return strings.TrimSpace(s)
+			}
+			if string(raw) == "null" {
+				return ""
+			}
+			return string(raw)
+		}
+		// GeoJSON positions are longitude first
+		lng, lat := feature.Geometry.Coordinates[0], feature.Geometry.Coordinates[1]
+		withCoordinates := func(name string) string {
+			switch name {
+			case "latitude":
+				return strconv.FormatFloat(lat, 'f', -1, 64)
+			case "longitude":
+				return strconv.FormatFloat(lng, 'f', -1, 64)
+			}
+			return field(name)
+		}
+
+		row.req, row.err = venueFromFields(withCoordinates)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// venueFromFields builds a creation request from named text fields. Structural checks are
+// left to CreateVenueRequest.Validate.
+func venueFromFields(field func(name string) string) (models.CreateVenueRequest, error) {
+	req := models.CreateVenueRequest{
+		Name:    field("name"),
+		Address: field("address"),
+	}
+
+	if id := field("external_id"); id != "" {
+		req.ExternalID = &id
+	}
+
+	for _, name := range []string{"latitude", "longitude"} {
+		value, err := strconv.ParseFloat(field(name), 64)
+		if err != nil {
+			return req, fmt.Errorf("invalid %s %q", name, field(name))
+		}
+		if name == "latitude" {
+			req.Latitude = &value
+		} else {
+			req.Longitude = &value
+		}
+	}
+
+	sports, err := parseSportList(field("sports"))
+	if err != nil {
+		return req, err
+	}
+	req.Sports = sports
+
+	if surface := strings.ToLower(field("surface")); surface != "" {
+		s := models.Surface(surface)
+		req.Surface = &s
+	}
+	if req.Indoor, err = parseFlag("indoor", field("indoor")); err != nil {
+		return req, err
+	}
+	if req.Lights, err = parseFlag("lights", field("lights")); err != nil {
+		return req, err
+	}
+
+	if cost := field("cost_per_hour"); cost != "" {
+		value, err := strconv.ParseFloat(cost, 64)
+		if err != nil {
+			return req, fmt.Errorf("invalid cost_per_hour %q", cost)
+		}
+		req.CostPerHour = &value
+	}
+	if currency := field("currency"); currency != "" {
+		req.Currency = &currency
+	}
+
+	hours, err := parseOpeningHours(field("time_zone"), field("opening_hours"))
+	if err != nil {
+		return req, err
+	}
+	req.OpeningHours = hours
+	return req, nil
+}
+
+// parseSportList reads a JSON array or a list of slugs separated by ";" or ","
+func parseSportList(value string) (models.StringList, error) {
+	sports := models.StringList{}
+	if value == "" {
+		return sports, nil
+	}
+	if strings.HasPrefix(value, "[") {
+		if err := json.Unmarshal([]byte(value), &sports); err != nil {
+			return nil, fmt.Errorf("invalid sports %s", value)
+		}
+		return sports, nil
+	}
+	for _, slug := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' }) {
+		if slug = strings.ToLower(strings.TrimSpace(slug)); slug != "" {
+			sports = append(sports, slug)
+		}
+	}
+	return sports, nil
+}
+
+// parseFlag reads yes/no style booleans; an empty value is false
+func parseFlag(name, value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "0", "false", "no", "n":
+		return false, nil
+	case "1", "true", "yes", "y":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid %s %q: expected yes or no", name, value)
+}
+
+// parseOpeningHours reads either the API's JSON form or "day HH:MM-HH:MM" entries separated by
+// ";". The time zone column, when set, overrides the one in the JSON.
+func parseOpeningHours(timeZone, value string) (models.Availability, error) {
+	hours := models.Availability{TimeZone: timeZone, Windows: []models.AvailabilityWindow{}}
+	if value == "" {
+		return hours, nil
+	}
+
+	if strings.HasPrefix(value, "{") {
+		if err := json.Unmarshal([]byte(value), &hours); err != nil {
+			return hours, fmt.Errorf("invalid opening_hours: %w", err)
+		}
+		if timeZone != "" {
+			hours.TimeZone = timeZone
+		}
+		return hours, nil
+	}
+
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Fields(entry)
+		span := strings.SplitN(strings.Join(parts[1:], ""), "-", 2)
+		if len(parts) < 2 || len(span) != 2 {
+			return hours, fmt.Errorf("invalid opening_hours entry %q: expected \"monday 07:00-22:00\"", entry)
+		}
+		hours.Windows = append(hours.Windows, models.AvailabilityWindow{
+			Day:   strings.ToLower(parts[0]),
+			Start: span[0],
+			End:   span[1],
+		})
+	}
+	if hours.TimeZone == "" {
+		return hours, fmt.Errorf("time_zone is required with opening_hours")
+	}
+	return hours, nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"swipe-sports-backend/internal/models"
+	"swipe-sports-backend/internal/repository"
+)
+
+const (
+	// defaultVenueRadiusKm is the search radius when the query does not set one
+	defaultVenueRadiusKm = 10
+	// defaultVenueLimit is the page size when the query does not set one
+	defaultVenueLimit = 50
+)
+
+type VenueService struct {
+	venueRepo    *repository.VenueRepository
+	userRepo     *repository.UserRepository
+	sportService *SportService
+}
+
+func NewVenueService() *VenueService {
+	return &VenueService{
+		venueRepo:    repository.NewVenueRepository(),
+		userRepo:     repository.NewUserRepository(),
+		sportService: NewSportService(),
+	}
+}
+
+// SearchVenues finds venues near the given point, or near the user when the query has none
+func (s *VenueService) SearchVenues(userID int64, q models.VenueSearchQuery) ([]models.Venue, error) {
+	if q.Latitude == nil || q.Longitude == nil {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil || user.Latitude == nil || user.Longitude == nil {
+			return nil, fmt.Errorf("latitude and longitude are required when your location is not set")
+		}
+		q.Latitude, q.Longitude = user.Latitude, user.Longitude
+	}
+	if q.RadiusKm <= 0 {
+		q.RadiusKm = defaultVenueRadiusKm
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultVenueLimit
+	}
+	q.Sport = strings.TrimSpace(q.Sport)
+
+	return s.venueRepo.Search(userID, q)
+}
+
+// GetVenue returns a venue with the user's favourite flag set, or nil if it does not exist
+func (s *VenueService) GetVenue(userID, venueID int64) (*models.Venue, error) {
+	venue, err := s.venueRepo.GetByID(venueID)
+	if err != nil || venue == nil {
+		return nil, err
+	}
+
+	favourites, err := s.venueRepo.GetFavourites([]int64{userID})
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range favourites[userID] {
+		if f.ID == venue.ID {
+			venue.Favourite = true
+		}
+	}
+	return venue, nil
+}
+
+func (s *VenueService) CreateVenue(req models.CreateVenueRequest) (*models.Venue, error) {
+	venue, err := s.newVenue(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.venueRepo.Create(venue); err != nil {
+		return nil, err
+	}
+	return s.venueRepo.GetByID(venue.ID)
+}
+
+// UpdateVenue applies the set fields of the request. It returns nil if the venue does not exist.
+func (s *VenueService) UpdateVenue(venueID int64, req models.UpdateVenueRequest) (*models.Venue, error) {
+	venue, err := s.venueRepo.GetByID(venueID)
+	if err != nil || venue == nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		venue.Name = *req.Name
+	}
+	if req.Address != nil {
+		venue.Address = *req.Address
+	}
+	if req.Latitude != nil {
+		venue.Latitude = *req.Latitude
+	}
+	if req.Longitude != nil {
+		venue.Longitude = *req.Longitude
+	}
+	if req.Sports != nil {
+		if err := s.sportService.ValidateSports(*req.Sports); err != nil {
+			return nil, err
+		}
+		venue.Sports = *req.Sports
+	}
+	if req.Surface != nil {
+		venue.Surface = req.Surface
+	}
+	if req.Indoor != nil {
+		venue.Indoor = *req.Indoor
+	}
+	if req.Lights != nil {
+		venue.Lights = *req.Lights
+	}
+	if req.CostPerHour != nil {
+		venue.CostPerHour = req.CostPerHour
+	}
+	if req.Currency != nil {
+		currency := strings.ToUpper(*req.Currency)
+		venue.Currency = &currency
+	}
+	if req.OpeningHours != nil {
+		if len(req.OpeningHours.Windows) > 0 {
+			if err := req.OpeningHours.Validate(); err != nil {
+				return nil, fmt.Errorf("opening_hours: %w", err)
+			}
+		}
+		venue.OpeningHours = *req.OpeningHours
+	}
+
+	if err := s.venueRepo.Update(venue); err != nil {
+		return nil, err
+	}
+	return s.venueRepo.GetByID(venue.ID)
+}
+
+// DeleteVenue reports false if the venue does not exist
+func (s *VenueService) DeleteVenue(venueID int64) (bool, error) {
+	return s.venueRepo.Delete(venueID)
+}
+
+// AddFavourite saves a venue for the user. It returns nil if the venue does not exist.
+func (s *VenueService) AddFavourite(userID, venueID int64) (*models.Venue, error) {
+	venue, err := s.venueRepo.GetByID(venueID)
+	if err != nil || venue == nil {
+		return nil, err
+	}
+	if err := s.venueRepo.AddFavourite(userID, venueID); err != nil {
+		return nil, err
+	}
+
+	// Favourite venues feed into the deck ranking
+	invalidateSwipeDeck(userID)
+
+	venue.Favourite = true
+	return venue, nil
+}
+
+// RemoveFavourite reports false if the venue was not one of the user's favourites
+func (s *VenueService) RemoveFavourite(userID, venueID int64) (bool, error) {
+	removed, err := s.venueRepo.RemoveFavourite(userID, venueID)
+	if err != nil || !removed {
+		return false, err
+	}
+	invalidateSwipeDeck(userID)
+	return true, nil
+}
+
+func (s *VenueService) GetFavourites(userID int64) ([]models.Venue, error) {
+	favourites, err := s.venueRepo.GetFavourites([]int64{userID})
+	if err != nil {
+		return nil, err
+	}
+	if favourites[userID] == nil {
+		return []models.Venue{}, nil
+	}
+	return favourites[userID], nil
+}
+
+// newVenue validates a creation request against the sports catalog
+func (s *VenueService) newVenue(req models.CreateVenueRequest) (*models.Venue, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if len(req.Sports) > 0 {
+		if err := s.sportService.ValidateSports(req.Sports); err != nil {
+			return nil, err
+		}
+	}
+
+	venue := &models.Venue{
+		ExternalID:   req.ExternalID,
+		Name:         strings.TrimSpace(req.Name),
+		Address:      strings.TrimSpace(req.Address),
+		Latitude:     *req.Latitude,
+		Longitude:    *req.Longitude,
+		Sports:       req.Sports,
+		Surface:      req.Surface,
+		Indoor:       req.Indoor,
+		Lights:       req.Lights,
+		CostPerHour:  req.CostPerHour,
+		OpeningHours: req.OpeningHours,
+	}
+	if venue.Sports == nil {
+		venue.Sports = models.StringList{}
+	}
+	if req.Currency != nil {
+		currency := strings.ToUpper(*req.Currency)
+		venue.Currency = &currency
+	}
+	return venue, nil
+}
@@ -0,0 +1,374 @@
+// Package teams splits a roster of rated players into balanced teams.
+//
+// Players are placed greedily, strongest first, into the weakest team with room, then pairs
+// are swapped between teams while that narrows the gap between them. The search is restarted
+// from several shuffled orders and one of the best splits found is kept, so asking again can
+// offer a different split that is just as fair.
+package teams
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"swipe-sports-backend/internal/rating"
+)
+
+const (
+	// restarts is how many shuffled starting points the search tries
+	restarts = 40
+	// tolerance is how much weaker than the best split, in rating points, an alternative may be
+	tolerance = 5.0
+	// groupPenalty is the cost, in rating points, of each player a team has beyond an even
+	// share of a group
+	groupPenalty = 100.0
+	// MinTeamSize is the smallest team a split may produce
+	MinTeamSize = 2
+)
+
+// Player is a member of the roster
+type Player struct {
+	ID     int64
+	Rating rating.Rating
+	// Group is balanced across teams when Options.BalanceGroups is set; empty means none
+	Group string
+}
+
+type Options struct {
+	Teams int
+	// Together lists sets of players to keep on the same team
+	Together [][]int64
+	// BalanceGroups spreads each Player.Group as evenly as the teams allow
+	BalanceGroups bool
+	// Avoid is a previous split to steer away from when another one is as fair
+	Avoid [][]int64
+	Rand  *rand.Rand
+}
+
+// Team is one side of a split
+type Team struct {
+	Players []int64
+	Rating  rating.Rating
+	// WinProbability is the chance of beating each of the other teams, on average
+	WinProbability float64
+}
+
+type Split struct {
+	Teams []Team
+	// Spread is the gap between the strongest and the weakest team ratings
+	Spread float64
+}
+
+// Generate splits the players into opts.Teams teams whose sizes differ by at most one
+func Generate(players []Player, opts Options) (Split, error) {
+	n := len(players)
+	if opts.Teams < 2 {
+		return Split{}, fmt.Errorf("at least 2 teams are needed")
+	}
+	if n < opts.Teams*MinTeamSize {
+		return Split{}, fmt.Errorf("%d players are too few for %d teams", n, opts.Teams)
+	}
+
+	units, err := groupUnits(players, opts.Together)
+	if err != nil {
+		return Split{}, err
+	}
+	rng := opts.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewSource(1))
+	}
+
+	sizes := make([]int, opts.Teams)
+	for i := range sizes {
+		sizes[i] = n / opts.Teams
+		if i < n%opts.Teams {
+			sizes[i]++
+		}
+	}
+
+	s := search{players: players, units: units, sizes: sizes, balanceGroups: opts.BalanceGroups}
+	type candidate struct {
+		teams []int
+		cost  float64
+	}
+	found := make(map[string]candidate)
+	for i := 0; i < restarts; i++ {
+		teams, ok := s.place(rng)
+		if !ok {
+			continue
+		}
+		cost := s.improve(teams)
+		key := Key(s.assignment(teams))
+		if _, seen := found[key]; !seen {
+			found[key] = candidate{teams: teams, cost: cost}
+		}
+	}
+	if len(found) == 0 {
+		return Split{}, fmt.Errorf("players kept together do not fit into %d even teams", opts.Teams)
+	}
+
+	keys := make([]string, 0, len(found))
+	best := math.Inf(1)
+	for key, c := range found {
+		keys = append(keys, key)
+		best = math.Min(best, c.cost)
+	}
+	sort.Strings(keys) // map order must not decide the pick
+
+	avoid := ""
+	if opts.Avoid != nil {
+		avoid = Key(opts.Avoid)
+	}
+	var picks []string
+	for _, key := range keys {
+		if found[key].cost <= best+tolerance && key != avoid {
+			picks = append(picks, key)
+		}
+	}
+	if len(picks) == 0 {
+		for _, key := range keys {
+			if found[key].cost == best {
+				picks = append(picks, key)
+			}
+		}
+	}
+
+	chosen := found[picks[rng.Intn(len(picks))]]
+	return Evaluate(players, s.assignment(chosen.teams)), nil
+}
+
+// Evaluate rates a given split. Players missing from the roster count as new players.
+func Evaluate(players []Player, assignment [][]int64) Split {
+	ratings := make(map[int64]rating.Rating, len(players))
+	for _, p := range players {
+		ratings[p.ID] = p.Rating
+	}
+
+	split := Split{Teams: make([]Team, len(assignment))}
+	for i, ids := range assignment {
+		members := make([]rating.Rating, len(ids))
+		for j, id := range ids {
+			r, ok := ratings[id]
+			if !ok {
+				r = rating.Default()
+			}
+			members[j] = r
+		}
+		split.Teams[i] = Team{Players: ids, Rating: rating.Team(members)}
+	}
+
+	low, high := math.Inf(1), math.Inf(-1)
+	for i := range split.Teams {
+		var total float64
+		for j := range split.Teams {
+			if i != j {
+				total += rating.ExpectedScore(split.Teams[i].Rating, split.Teams[j].Rating)
+			}
+		}
+		if len(split.Teams) > 1 {
+			split.Teams[i].WinProbability = total / float64(len(split.Teams)-1)
+		}
+		low = math.Min(low, split.Teams[i].Rating.Rating)
+		high = math.Max(high, split.Teams[i].Rating.Rating)
+	}
+	if len(split.Teams) > 0 {
+		split.Spread = high - low
+	}
+	return split
+}
+
+// Key identifies a split regardless of the order of teams and of players within them
+func Key(assignment [][]int64) string {
+	teams := make([]string, 0, len(assignment))
+	for _, team := range assignment {
+		ids := append([]int64(nil), team...)
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		parts := make([]string, len(ids))
+		for i, id := range ids {
+			parts[i] = strconv.FormatInt(id, 10)
+		}
+		teams = append(teams, strings.Join(parts, ","))
+	}
+	sort.Strings(teams)
+	return strings.Join(teams, "|")
+}
+
+// groupUnits merges the players to keep together into units, as indexes into players. Every
+// other player is a unit of their own.
+func groupUnits(players []Player, together [][]int64) ([][]int, error) {
+	index := make(map[int64]int, len(players))
+	for i, p := range players {
+		index[p.ID] = i
+	}
+
+	parent := make([]int, len(players))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for _, set := range together {
+		for j, id := range set {
+			i, ok := index[id]
+			if !ok {
+				return nil, fmt.Errorf("player %d is not on the roster", id)
+			}
+			if j > 0 {
+				parent[find(i)] = find(index[set[0]])
+			}
+		}
+	}
+
+	byRoot := make(map[int][]int)
+	var roots []int
+	for i := range players {
+		root := find(i)
+		if byRoot[root] == nil {
+			roots = append(roots, root)
+		}
+		byRoot[root] = append(byRoot[root], i)
+	}
+	units := make([][]int, len(roots))
+	for i, root := range roots {
+		units[i] = byRoot[root]
+	}
+	return units, nil
+}
+
+type search struct {
+	players       []Player
+	units         [][]int
+	sizes         []int
+	balanceGroups bool
+}
+
+// place puts each unit, largest and strongest first with ties shuffled, into the team with the
+// lowest total rating that still has room for it. It returns each unit's team.
+func (s *search) place(rng *rand.Rand) ([]int, bool) {
+	order := rng.Perm(len(s.units))
+	sort.SliceStable(order, func(a, b int) bool {
+		ua, ub := s.units[order[a]], s.units[order[b]]
+		if len(ua) != len(ub) {
+			return len(ua) > len(ub)
+		}
+		return s.strength(ua) > s.strength(ub)
+	})
+
+	teams := make([]int, len(s.units))
+	room := append([]int(nil), s.sizes...)
+	totals := make([]float64, len(s.sizes))
+	for _, u := range order {
+		best := -1
+		for _, t := range rng.Perm(len(room)) {
+			if room[t] >= len(s.units[u]) && (best < 0 || totals[t] < totals[best]) {
+				best = t
+			}
+		}
+		if best < 0 {
+			return nil, false
+		}
+		teams[u] = best
+		room[best] -= len(s.units[u])
+		totals[best] += s.strength(s.units[u])
+	}
+	return teams, true
+}
+
+// improve swaps units of the same size between teams while that lowers the cost, and returns
+// the final cost
+func (s *search) improve(teams []int) float64 {
+	cost := s.cost(teams)
+	for improved := true; improved; {
+		improved = false
+		for a := range s.units {
+			for b := a + 1; b < len(s.units); b++ {
+				if teams[a] == teams[b] || len(s.units[a]) != len(s.units[b]) {
+					continue
+				}
+				teams[a], teams[b] = teams[b], teams[a]
+				if c := s.cost(teams); c < cost-1e-9 {
+					cost, improved = c, true
+				} else {
+					teams[a], teams[b] = teams[b], teams[a]
+				}
+			}
+		}
+	}
+	return cost
+}
+
+// cost is the standard deviation of the team ratings plus the group imbalance penalty
+func (s *search) cost(teams []int) float64 {
+	sums := make([]float64, len(s.sizes))
+	groups := make(map[string][]int)
+	for u, t := range teams {
+		for _, i := range s.units[u] {
+			sums[t] += s.players[i].Rating.Rating
+			if g := s.players[i].Group; s.balanceGroups && g != "" {
+				if groups[g] == nil {
+					groups[g] = make([]int, len(s.sizes))
+				}
+				groups[g][t]++
+			}
+		}
+	}
+
+	var mean float64
+	for t := range sums {
+		sums[t] /= float64(s.sizes[t])
+		mean += sums[t]
+	}
+	mean /= float64(len(sums))
+	var variance float64
+	for _, r := range sums {
+		variance += (r - mean) * (r - mean)
+	}
+	cost := math.Sqrt(variance / float64(len(sums)))
+
+	for _, counts := range groups {
+		low, high := counts[0], counts[0]
+		for _, c := range counts {
+			if c < low {
+				low = c
+			}
+			if c > high {
+				high = c
+			}
+		}
+		if high-low > 1 {
+			cost += groupPenalty * float64(high-low-1)
+		}
+	}
+	return cost
+}
+
+func (s *search) strength(unit []int) float64 {
+	var total float64
+	for _, i := range unit {
+		total += s.players[i].Rating.Rating
+	}
+	return total
+}
+
+// assignment lists the player IDs of each team
+func (s *search) assignment(teams []int) [][]int64 {
+	assignment := make([][]int64, len(s.sizes))
+	for t := range assignment {
+		assignment[t] = []int64{}
+	}
+	for u, t := range teams {
+		for _, i := range s.units[u] {
+			assignment[t] = append(assignment[t], s.players[i].ID)
+		}
+	}
+	return assignment
+}
@@ -0,0 +1,136 @@
+package teams
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"swipe-sports-backend/internal/rating"
+)
+
+func roster(ratings ...float64) []Player {
+	players := make([]Player, len(ratings))
+	for i, r := range ratings {
+		players[i] = Player{ID: int64(i + 1), Rating: rating.Rating{Rating: r, Deviation: 100, Volatility: 0.06}}
+	}
+	return players
+}
+
+func teamOf(split Split, id int64) int {
+	for t, team := range split.Teams {
+		for _, p := range team.Players {
+			if p == id {
+				return t
+			}
+		}
+	}
+	return -1
+}
+
+func TestGenerate_BalancesTwoTeams(t *testing.T) {
+	players := roster(2000, 1900, 1800, 1700, 1600, 1500, 1400, 1300)
+
+	split, err := Generate(players, Options{Teams: 2, Rand: rand.New(rand.NewSource(7))})
+	require.NoError(t, err)
+
+	require.Len(t, split.Teams, 2)
+	assert.Len(t, split.Teams[0].Players, 4)
+	assert.Len(t, split.Teams[1].Players, 4)
+	// 2000+1700+1600+1300 against 1900+1800+1500+1400 is a perfect split
+	assert.InDelta(t, 0, split.Spread, tolerance)
+	assert.InDelta(t, 0.5, split.Teams[0].WinProbability, 0.01)
+	assert.InDelta(t, 1, split.Teams[0].WinProbability+split.Teams[1].WinProbability, 1e-9)
+}
+
+func TestGenerate_UnevenRosterSizesDifferByOne(t *testing.T) {
+	players := roster(1500, 1500, 1500, 1500, 1500, 1500, 1500)
+
+	split, err := Generate(players, Options{Teams: 3})
+	require.NoError(t, err)
+
+	sizes := []int{len(split.Teams[0].Players), len(split.Teams[1].Players), len(split.Teams[2].Players)}
+	assert.ElementsMatch(t, []int{3, 2, 2}, sizes)
+}
+
+func TestGenerate_KeepsFriendsTogether(t *testing.T) {
+	// The two strongest would never share a team in a purely balanced split
+	players := roster(2000, 1950, 1500, 1450, 1400, 1100)
+
+	split, err := Generate(players, Options{Teams: 2, Together: [][]int64{{1, 2}}})
+	require.NoError(t, err)
+
+	assert.Equal(t, teamOf(split, 1), teamOf(split, 2))
+}
+
+func TestGenerate_MergesOverlappingFriendGroups(t *testing.T) {
+	players := roster(1500, 1500, 1500, 1500, 1500, 1500)
+
+	split, err := Generate(players, Options{Teams: 2, Together: [][]int64{{1, 2}, {2, 3}}})
+	require.NoError(t, err)
+
+	assert.Equal(t, teamOf(split, 1), teamOf(split, 2))
+	assert.Equal(t, teamOf(split, 2), teamOf(split, 3))
+}
+
+func TestGenerate_BalancesGroups(t *testing.T) {
+	players := roster(1500, 1500, 1500, 1500, 1500, 1500, 1500, 1500)
+	for i := range players {
+		players[i].Group = "a"
+		if i%4 == 0 {
+			players[i].Group = "b"
+		}
+	}
+
+	split, err := Generate(players, Options{Teams: 2, BalanceGroups: true})
+	require.NoError(t, err)
+
+	// Players 1 and 5 are the only ones in group b
+	assert.NotEqual(t, teamOf(split, 1), teamOf(split, 5))
+}
+
+func TestGenerate_AvoidsPreviousSplit(t *testing.T) {
+	players := roster(1500, 1500, 1500, 1500, 1500, 1500)
+
+	first, err := Generate(players, Options{Teams: 2})
+	require.NoError(t, err)
+	previous := [][]int64{first.Teams[0].Players, first.Teams[1].Players}
+
+	second, err := Generate(players, Options{Teams: 2, Avoid: previous})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, Key(previous), Key([][]int64{second.Teams[0].Players, second.Teams[1].Players}))
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	_, err := Generate(roster(1500, 1500, 1500), Options{Teams: 2})
+	assert.Error(t, err)
+
+	_, err = Generate(roster(1500, 1500, 1500, 1500), Options{Teams: 1})
+	assert.Error(t, err)
+
+	_, err = Generate(roster(1500, 1500, 1500, 1500), Options{Teams: 2, Together: [][]int64{{1, 9}}})
+	assert.Error(t, err)
+
+	// Three friends cannot fit in a team of two
+	_, err = Generate(roster(1500, 1500, 1500, 1500), Options{Teams: 2, Together: [][]int64{{1, 2, 3}}})
+	assert.Error(t, err)
+}
+
+func TestEvaluate(t *testing.T) {
+	players := roster(1700, 1300, 1500, 1500)
+
+	split := Evaluate(players, [][]int64{{1, 3}, {2, 4}})
+
+	assert.InDelta(t, 1600, split.Teams[0].Rating.Rating, 1e-9)
+	assert.InDelta(t, 1400, split.Teams[1].Rating.Rating, 1e-9)
+	assert.InDelta(t, 200, split.Spread, 1e-9)
+	assert.Greater(t, split.Teams[0].WinProbability, 0.5)
+	assert.InDelta(t, 1, split.Teams[0].WinProbability+split.Teams[1].WinProbability, 1e-9)
+}
+
+func TestKey_IgnoresOrder(t *testing.T) {
+	assert.Equal(t, Key([][]int64{{3, 1}, {2, 4}}), Key([][]int64{{4, 2}, {1, 3}}))
+	assert.NotEqual(t, Key([][]int64{{1, 2}, {3, 4}}), Key([][]int64{{1, 3}, {2, 4}}))
+}
@@ -93,7 +93,9 @@ func (r *UserRepository) CreateUser(user *models.User) error {
 }
 
 func (r *UserRepository) GetUserByID(id int64) (*models.User, error) {
-	query := `SELECT * FROM users WHERE id = ?`
+	query := `SELECT id, oauth_id, oauth_provider, name, first_name, last_name, age, email, gender, location,
+		latitude, longitude, ` + "`rank`" + `, profile_pic_url, bio, sport_preferences, skill_level, ntrp_rating, play_style,
+		preferred_timeslots, availability, created_at, updated_at FROM users WHERE id = ?`
 	
 	var user models.User
 	err := r.db.QueryRow(query, id).Scan(
@@ -113,7 +115,9 @@ func (r *UserRepository) GetUserByID(id int64) (*models.User, error) {
 }
 
 func (r *UserRepository) GetUserByOAuthID(oauthID, provider string) (*models.User, error) {
-	query := `SELECT * FROM users WHERE oauth_id = ? AND oauth_provider = ?`
+	query := `SELECT id, oauth_id, oauth_provider, name, first_name, last_name, age, email, gender, location,
+		latitude, longitude, ` + "`rank`" + `, profile_pic_url, bio, sport_preferences, skill_level, ntrp_rating, play_style,
+		preferred_timeslots, availability, created_at, updated_at FROM users WHERE oauth_id = ? AND oauth_provider = ?`
 	
 	var user models.User
 	err := r.db.QueryRow(query, oauthID, provider).Scan(
@@ -426,7 +430,7 @@ func main() {
 					PreferredTimeslots: &defaultTimeslots,
 					Rank:              1000,
 					SportPreferences:  make(models.SportPreferences),
-					Availability:      make(models.Availability),
+					Availability:      models.Availability{},
 				}
 
 				if err := userRepo.CreateUser(user); err != nil {
@@ -493,7 +497,7 @@ func main() {
 					PreferredTimeslots: &defaultTimeslots,
 					Rank:              1000,
 					SportPreferences:  make(models.SportPreferences),
-					Availability:      make(models.Availability),
+					Availability:      models.Availability{},
 				}
 
 				if err := userRepo.CreateUser(user); err != nil {